@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"server/internal/db"
+	"server/internal/user"
+	"server/pkg/packets"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+var (
+	dbPath     = flag.String("db", "db.sqlite", "Path to the SQLite database used by the server")
+	jsonOutput = flag.Bool("json", false, "Print results as JSON instead of tables")
+)
+
+const usage = `Usage: admin [flags] <command> [arguments]
+
+Commands:
+  users list
+  users create <username> <password>
+  users disable <username>
+  users enable <username>
+  users reset-password <username> <password>
+  tokens list [username]
+  tokens revoke <jti>
+  tokens revoke-user <username>
+  tokens purge
+  rooms list
+  rooms delete <id>
+
+Flags:
+`
+
+type command struct {
+	args int
+	run  func(c context.Context, service *user.Service, args []string) error
+}
+
+var commands = map[string]command{
+	"users list":           {0, listUsers},
+	"users create":         {2, createUser},
+	"users disable":        {1, disableUser},
+	"users enable":         {1, enableUser},
+	"users reset-password": {2, resetPassword},
+	"tokens list":          {-1, listTokens},
+	"tokens revoke":        {1, revokeToken},
+	"tokens revoke-user":   {1, revokeUserTokens},
+	"tokens purge":         {0, purgeTokens},
+	"rooms list":           {0, listRooms},
+	"rooms delete":         {1, deleteRoom},
+}
+
+func main() {
+	log.SetFlags(0)
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0) + " " + flag.Arg(1)
+	args := flag.Args()[2:]
+	cmd, found := commands[name]
+	if !found || (cmd.args >= 0 && len(args) != cmd.args) || (cmd.args < 0 && len(args) > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dbPool, err := db.NewDatabase(*dbPath)
+	if err != nil {
+		log.Fatalf("Error opening database: %v", err)
+	}
+	defer dbPool.Close()
+
+	service := user.NewService(user.NewRepository(dbPool), nil)
+	if err := cmd.run(context.Background(), &service, args); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+}
+
+func listUsers(c context.Context, service *user.Service, args []string) error {
+	users, err := service.ListUsers(c)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		Id         string     `json:"id"`
+		Username   string     `json:"username"`
+		CreatedAt  time.Time  `json:"created_at"`
+		DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	}
+	rows := make([]row, 0, len(users))
+	for _, u := range users {
+		r := row{Id: u.ID, Username: u.Username, CreatedAt: u.CreatedAt}
+		if u.DisabledAt.Valid {
+			r.DisabledAt = &u.DisabledAt.Time
+		}
+		rows = append(rows, r)
+	}
+
+	return printRows(rows, []string{"ID", "USERNAME", "CREATED", "DISABLED"}, func(r row) []string {
+		return []string{r.Id, r.Username, formatTime(&r.CreatedAt), formatTime(r.DisabledAt)}
+	})
+}
+
+func createUser(c context.Context, service *user.Service, args []string) error {
+	response, err := service.Register(c, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return printResponse(response, "user created")
+}
+
+func disableUser(c context.Context, service *user.Service, args []string) error {
+	if err := service.SetUserDisabled(c, args[0], true); err != nil {
+		return err
+	}
+	return printResult("user disabled")
+}
+
+func enableUser(c context.Context, service *user.Service, args []string) error {
+	if err := service.SetUserDisabled(c, args[0], false); err != nil {
+		return err
+	}
+	return printResult("user enabled")
+}
+
+func resetPassword(c context.Context, service *user.Service, args []string) error {
+	response, err := service.ResetPassword(c, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	return printResponse(response, "password reset")
+}
+
+func listTokens(c context.Context, service *user.Service, args []string) error {
+	username := ""
+	if len(args) > 0 {
+		username = args[0]
+	}
+
+	tokens, err := service.ListTokens(c, username)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		Jti       string     `json:"jti"`
+		UserId    string     `json:"user_id"`
+		CreatedAt time.Time  `json:"created_at"`
+		ExpireAt  time.Time  `json:"expire_at"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	}
+	rows := make([]row, 0, len(tokens))
+	for _, t := range tokens {
+		r := row{Jti: t.Jti, UserId: t.UserID, CreatedAt: t.CreatedAt, ExpireAt: t.ExpireAt}
+		if t.RevokedAt.Valid {
+			r.RevokedAt = &t.RevokedAt.Time
+		}
+		rows = append(rows, r)
+	}
+
+	return printRows(rows, []string{"JTI", "USER ID", "CREATED", "EXPIRES", "REVOKED"}, func(r row) []string {
+		return []string{r.Jti, r.UserId, formatTime(&r.CreatedAt), formatTime(&r.ExpireAt), formatTime(r.RevokedAt)}
+	})
+}
+
+func revokeToken(c context.Context, service *user.Service, args []string) error {
+	if err := service.RevokeToken(c, args[0]); err != nil {
+		return err
+	}
+	return printResult("token revoked")
+}
+
+func revokeUserTokens(c context.Context, service *user.Service, args []string) error {
+	revoked, err := service.RevokeTokensForUsername(c, args[0])
+	if err != nil {
+		return err
+	}
+	return printResult(fmt.Sprintf("%d tokens revoked", revoked))
+}
+
+func purgeTokens(c context.Context, service *user.Service, args []string) error {
+	deleted, err := service.PurgeTokens(c)
+	if err != nil {
+		return err
+	}
+	return printResult(fmt.Sprintf("%d expired or revoked tokens deleted", deleted))
+}
+
+func listRooms(c context.Context, service *user.Service, args []string) error {
+	rooms, err := service.ListRooms(c)
+	if err != nil {
+		return err
+	}
+
+	return printRows(rooms, []string{"ID", "NAME", "OWNER ID", "CREATED"}, func(r db.Room) []string {
+		return []string{strconv.FormatInt(r.ID, 10), r.Name, r.OwnerID, formatTime(&r.CreatedAt)}
+	})
+}
+
+func deleteRoom(c context.Context, service *user.Service, args []string) error {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		reason := fmt.Sprintf("invalid room id %q", args[0])
+		return errors.New(reason)
+	}
+
+	if err := service.DeleteRoom(c, id); err != nil {
+		return err
+	}
+	return printResult("room deleted, the server takes its clients out of it within a minute")
+}
+
+// Prints rows as a table, or as a JSON array when -json is set
+func printRows[T any](rows []T, header []string, columns func(T) []string) error {
+	if *jsonOutput {
+		return printJson(rows)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	printTableRow(writer, header)
+	for _, row := range rows {
+		printTableRow(writer, columns(row))
+	}
+	return writer.Flush()
+}
+
+func printTableRow(writer *tabwriter.Writer, columns []string) {
+	for i, column := range columns {
+		if i > 0 {
+			fmt.Fprint(writer, "\t")
+		}
+		fmt.Fprint(writer, column)
+	}
+	fmt.Fprintln(writer)
+}
+
+// Prints the outcome of a service call that answers with an ok or deny message
+func printResponse(response *packets.Message, success string) error {
+	if deny, ok := response.Type.(*packets.Message_DenyResponse); ok {
+		return errors.New(deny.DenyResponse.Reason)
+	}
+	return printResult(success)
+}
+
+func printResult(result string) error {
+	if *jsonOutput {
+		return printJson(map[string]string{"result": result})
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func printJson(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
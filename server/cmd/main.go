@@ -1,32 +1,159 @@
 package main
 
 import (
+	"context"
 	_ "embed"
+	"flag"
 	"log"
+	"server/internal/attachments"
 	"server/internal/db"
+	"server/internal/profile"
+	"server/internal/scheduler"
+	"server/internal/unfurl"
 	"server/internal/user"
 	"server/internal/ws"
 	"server/router"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
 
+var (
+	dbPath         = flag.String("db", "db.sqlite", "Path to the SQLite database")
+	attachmentsDir = flag.String("attachments-dir", "attachments", "Directory where uploaded attachments are stored")
+
+	jobMetricsInterval = flag.Duration("job-metrics-interval", time.Hour, "How often the metrics of the background jobs are logged")
+)
+
 func main() {
-	dbPool, err := db.NewDatabase()
+	flag.Parse()
+
+	dbPool, err := db.NewDatabase(*dbPath)
 	if err != nil {
 		log.Fatalf("Error creating database: %v", err)
 	}
 
+	unfurlRepository := unfurl.NewRepository(dbPool)
+	unfurlService := unfurl.NewService(unfurlRepository, unfurl.NewHTTPFetcher())
+
 	hub := ws.NewHub()
 	wsRepository := ws.NewRepository(dbPool)
-	wsService := ws.NewService(wsRepository)
+	wsService := ws.NewService(wsRepository, unfurlService)
 	wsHandler := ws.NewHandler(hub, wsService)
 
+	if err := wsService.LoadRooms(context.Background(), hub); err != nil {
+		log.Fatalf("Error loading rooms: %v", err)
+	}
+
 	userRepository := user.NewRepository(dbPool)
 	userService := user.NewService(userRepository, hub)
 	userHandler := user.NewHandler(userService)
 
+	profileRepository := profile.NewRepository(dbPool)
+	profileService := profile.NewService(profileRepository, hub)
+	profileHandler := profile.NewHandler(profileService)
+
+	blobs, err := attachments.NewDiskStore(*attachmentsDir)
+	if err != nil {
+		log.Fatalf("Error creating attachment store: %v", err)
+	}
+	attachmentsRepository := attachments.NewRepository(dbPool)
+	attachmentsService := attachments.NewService(attachmentsRepository, blobs, hub, wsService)
+	attachmentsHandler := attachments.NewHandler(attachmentsService)
+
+	jobs := scheduler.NewScheduler()
+	jobs.Add(scheduler.Job{
+		Name:     "token-cleanup",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run:      userService.PurgeTokens,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "message-retention",
+		Interval: time.Minute,
+		Jitter:   10 * time.Second,
+		Run: func(c context.Context) (int64, error) {
+			return hub.RemoveOldMessages(), nil
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "stale-state",
+		Interval: time.Minute,
+		Jitter:   10 * time.Second,
+		Run: func(c context.Context) (int64, error) {
+			return wsService.RemoveStaleState(c, hub)
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "disabled-users",
+		Interval: 5 * time.Second,
+		Run: func(c context.Context) (int64, error) {
+			return wsService.DisconnectDisabledUsers(c, hub)
+		},
+	})
+
+	jobs.Add(scheduler.Job{
+		Name:     "idle-presence",
+		Interval: 30 * time.Second,
+		Jitter:   5 * time.Second,
+		Run: func(c context.Context) (int64, error) {
+			return wsService.UpdateIdlePresence(c, hub)
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "event-retention",
+		Interval: time.Hour,
+		Jitter:   time.Minute,
+		Run: func(c context.Context) (int64, error) {
+			return wsService.RemoveOldRoomEvents(c)
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "attachment-cleanup",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run:      attachmentsService.RemoveOrphans,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "link-preview-cleanup",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run:      unfurlService.RemoveOldPreviews,
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "room-mutes",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run: func(c context.Context) (int64, error) {
+			return wsService.RemoveExpiredMutes(c)
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "dropped-packets",
+		Interval: time.Minute,
+		Run: func(c context.Context) (int64, error) {
+			return hub.ReportDroppedPackets(), nil
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "rate-limits",
+		Interval: 10 * time.Minute,
+		Jitter:   time.Minute,
+		Run: func(c context.Context) (int64, error) {
+			return hub.PruneRateLimits(), nil
+		},
+	})
+
+	jobs.Add(scheduler.Job{
+		Name:     "job-metrics",
+		Interval: *jobMetricsInterval,
+		Run: func(c context.Context) (int64, error) {
+			return jobs.LogMetrics(), nil
+		},
+	})
+
 	go hub.Run()
+	go jobs.Run(context.Background())
 
-	router.StartRouter(userHandler, wsHandler)
+	router.StartRouter(userHandler, wsHandler, profileHandler, attachmentsHandler)
 }
@@ -8,6 +8,9 @@ require (
 	github.com/rs/cors v1.11.1
 	github.com/segmentio/ksuid v1.0.4
 	golang.org/x/crypto v0.38.0
+	golang.org/x/image v0.25.0
+	golang.org/x/net v0.40.0
+	golang.org/x/text v0.25.0
 	google.golang.org/protobuf v1.36.6
 	modernc.org/sqlite v1.37.0
 )
@@ -19,7 +22,6 @@ require (
 	github.com/ncruces/go-strftime v0.1.9 // indirect
 	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
 	golang.org/x/exp v0.0.0-20250305212735-054e65f0b394 // indirect
-	golang.org/x/sync v0.14.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 	modernc.org/libc v1.62.1 // indirect
 	modernc.org/mathutil v1.7.1 // indirect
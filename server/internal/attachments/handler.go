@@ -0,0 +1,132 @@
+package attachments
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"server/internal/jwt"
+	"server/pkg/packets"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{
+		Service: s,
+	}
+}
+
+func (h *Handler) UploadAttachment(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, MaxUploadBytes()))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(writer, "Attachment too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_UploadAttachment)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	attachmentMessage, err := h.Service.Upload(request.Context(), accessToken.Subject, pktMessage.UploadAttachment)
+	if err != nil {
+		log.Printf("An error occured when trying to upload attachment: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(attachmentMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+// Serves the raw attachment, or one of its thumbnails when thumb is set. The
+// token comes in the query string so images can be used directly as an image
+// source
+func (h *Handler) GetAttachment(writer http.ResponseWriter, request *http.Request) {
+	token := request.URL.Query().Get("token")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id := request.URL.Query().Get("id")
+	thumbnailSize := 0
+	if thumb := request.URL.Query().Get("thumb"); thumb != "" {
+		thumbnailSize, err = strconv.Atoi(thumb)
+		if err != nil || thumbnailSize <= 0 {
+			http.Error(writer, "Invalid thumbnail size", http.StatusBadRequest)
+			return
+		}
+	}
+
+	attachment, data, err := h.Service.Open(request.Context(), accessToken.Subject, id, thumbnailSize)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(writer, "Attachment not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("An error occured when trying to get attachment: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+	defer data.Close()
+
+	// Only images are shown inline, anything else is downloaded
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.Mime, "image/") {
+		disposition = "inline"
+	}
+
+	writer.Header().Set("Content-Type", attachment.Mime)
+	writer.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	writer.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Cache-Control", "private, max-age=86400")
+	writer.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(writer, data); err != nil {
+		log.Printf("Error writing attachment %s: %v", attachment.ID, err)
+	}
+}
@@ -0,0 +1,325 @@
+package attachments
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// Longest side of each thumbnail, in pixels. Only sizes smaller than the
+	// image itself are generated
+	thumbnailSizes = []int{64, 256, 640}
+
+	// Larger images are stored without thumbnails, decoding them would use too much memory
+	maxImagePixels = 50_000_000
+
+	thumbnailQuality = 80
+)
+
+// What could be learned from an uploaded image
+type imageInfo struct {
+	width      int
+	height     int
+	thumbnails []thumbnail
+}
+
+type thumbnail struct {
+	size   int
+	width  int
+	height int
+	mime   string
+	data   []byte
+}
+
+// Decodes the image, which must already be stripped of its location, and
+// generates its thumbnails. Dimensions are the ones the image is displayed
+// with, after applying its EXIF orientation
+func processImage(data []byte, orientation int) (imageInfo, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return imageInfo{}, err
+	}
+	if config.Width*config.Height > maxImagePixels {
+		reason := fmt.Sprintf("image of %dx%d is too large to process", config.Width, config.Height)
+		return imageInfo{}, errors.New(reason)
+	}
+
+	info := imageInfo{width: config.Width, height: config.Height}
+	if orientation >= 5 {
+		info.width, info.height = info.height, info.width
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return imageInfo{}, err
+	}
+
+	// Largest first, so each thumbnail is scaled from the previous one
+	for i := len(thumbnailSizes) - 1; i >= 0; i-- {
+		size := thumbnailSizes[i]
+		if size >= max(config.Width, config.Height) {
+			continue
+		}
+
+		scaled := scale(source, size)
+		source = scaled
+
+		oriented := orient(scaled, orientation)
+		thumb, err := encodeThumbnail(oriented)
+		if err != nil {
+			return imageInfo{}, err
+		}
+		thumb.size = size
+		info.thumbnails = append([]thumbnail{thumb}, info.thumbnails...)
+	}
+
+	return info, nil
+}
+
+// Scales the image down so its longest side is size pixels, keeping its aspect ratio
+func scale(source image.Image, size int) *image.NRGBA {
+	bounds := source.Bounds()
+	width, height := size, size
+	if bounds.Dx() > bounds.Dy() {
+		height = max(1, bounds.Dy()*size/bounds.Dx())
+	} else {
+		width = max(1, bounds.Dx()*size/bounds.Dy())
+	}
+
+	scaled := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), source, bounds, draw.Src, nil)
+	return scaled
+}
+
+// Rotates and flips the image as its EXIF orientation says it should be displayed
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+
+	out := image.NewNRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := range height {
+		for x := range width {
+			var outX, outY int
+			switch orientation {
+			case 2:
+				outX, outY = width-1-x, y
+			case 3:
+				outX, outY = width-1-x, height-1-y
+			case 4:
+				outX, outY = x, height-1-y
+			case 5:
+				outX, outY = y, x
+			case 6:
+				outX, outY = height-1-y, x
+			case 7:
+				outX, outY = height-1-y, width-1-x
+			case 8:
+				outX, outY = y, width-1-x
+			}
+			out.SetNRGBA(outX, outY, img.NRGBAAt(x, y))
+		}
+	}
+	return out
+}
+
+// Opaque thumbnails are JPEG, the ones with transparency PNG
+func encodeThumbnail(img *image.NRGBA) (thumbnail, error) {
+	var buffer bytes.Buffer
+	thumb := thumbnail{width: img.Bounds().Dx(), height: img.Bounds().Dy()}
+
+	if img.Opaque() {
+		thumb.mime = "image/jpeg"
+		if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return thumbnail{}, err
+		}
+	} else {
+		thumb.mime = "image/png"
+		if err := png.Encode(&buffer, img); err != nil {
+			return thumbnail{}, err
+		}
+	}
+
+	thumb.data = buffer.Bytes()
+	return thumb, nil
+}
+
+// Removes the GPS location from the EXIF metadata of JPEG, PNG and WebP images,
+// leaving the rest of the file untouched. Returns the cleaned copy of the image
+// and its EXIF orientation, zero if it has none
+func stripLocation(data []byte, mediaType string) ([]byte, int) {
+	data = bytes.Clone(data)
+	switch mediaType {
+	case "image/jpeg":
+		return data, scrubJpeg(data)
+	case "image/png":
+		return data, scrubPng(data)
+	case "image/webp":
+		return data, scrubWebp(data)
+	}
+	return data, 0
+}
+
+var exifHeader = []byte("Exif\x00\x00")
+
+func scrubJpeg(data []byte) int {
+	orientation := 0
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return orientation
+		}
+		marker := data[i+1]
+		switch {
+		case marker == 0xFF:
+			// Padding before a marker
+			i++
+			continue
+		case marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			i += 2
+			continue
+		case marker == 0xD9 || marker == 0xDA:
+			// The image data starts, there is no metadata after it
+			return orientation
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return orientation
+		}
+		payload := data[i+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(payload, exifHeader) {
+			orientation = scrubExif(payload[len(exifHeader):])
+		}
+		i = end
+	}
+	return orientation
+}
+
+func scrubPng(data []byte) int {
+	orientation := 0
+	for i := 8; i+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if end > len(data) {
+			return orientation
+		}
+
+		chunkType := data[i+4 : i+8]
+		if string(chunkType) == "eXIf" {
+			orientation = scrubExif(data[i+8 : i+8+length])
+			// The chunk changed, so its checksum has to be computed again
+			checksum := crc32.ChecksumIEEE(data[i+4 : i+8+length])
+			binary.BigEndian.PutUint32(data[i+8+length:], checksum)
+		}
+		if string(chunkType) == "IEND" {
+			return orientation
+		}
+		i = end
+	}
+	return orientation
+}
+
+func scrubWebp(data []byte) int {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0
+	}
+
+	orientation := 0
+	for i := 12; i+8 <= len(data); {
+		length := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + length
+		if end > len(data) {
+			return orientation
+		}
+
+		if string(data[i:i+4]) == "EXIF" {
+			exif := data[i+8 : end]
+			orientation = scrubExif(bytes.TrimPrefix(exif, exifHeader))
+		}
+		// Chunks are padded to an even size
+		i = end + length%2
+	}
+	return orientation
+}
+
+// Sizes in bytes of the TIFF field types
+var tiffTypeSizes = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// Empties the GPS directory of EXIF data in place and returns the orientation
+// of the image. The size of the data doesn't change, so offsets stay valid
+func scrubExif(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	orientation := 0
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		switch order.Uint16(tiff[entry:]) {
+		case 0x0112:
+			orientation = int(order.Uint16(tiff[entry+8:]))
+		case 0x8825:
+			clearIfd(tiff, order, int(order.Uint32(tiff[entry+8:])))
+		}
+	}
+	return orientation
+}
+
+// Zeroes every entry of the directory, the values they point to, and leaves it
+// with no entries
+func clearIfd(tiff []byte, order binary.ByteOrder, ifd int) {
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := range count {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		size := tiffTypeSizes[order.Uint16(tiff[entry+2:])] * int(order.Uint32(tiff[entry+4:]))
+		if offset := int(order.Uint32(tiff[entry+8:])); size > 4 && offset >= 8 && offset+size <= len(tiff) {
+			clear(tiff[offset : offset+size])
+		}
+		clear(tiff[entry : entry+12])
+	}
+	order.PutUint16(tiff[ifd:], 0)
+}
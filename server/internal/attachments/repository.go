@@ -0,0 +1,56 @@
+package attachments
+
+import (
+	"context"
+	"database/sql"
+	"server/internal/db"
+	"time"
+)
+
+type Repository struct {
+	dbPool  *sql.DB
+	queries *db.Queries
+}
+
+func NewRepository(dbPool *sql.DB) Repository {
+	return Repository{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+	}
+}
+
+func (r *Repository) CreateAttachment(ctx context.Context, params db.CreateAttachmentParams) (db.Attachment, error) {
+	return r.queries.CreateAttachment(ctx, params)
+}
+
+func (r *Repository) CreateAttachmentThumbnail(ctx context.Context, params db.CreateAttachmentThumbnailParams) error {
+	return r.queries.CreateAttachmentThumbnail(ctx, params)
+}
+
+func (r *Repository) GetAttachmentThumbnail(ctx context.Context, params db.GetAttachmentThumbnailParams) (db.AttachmentThumbnail, error) {
+	return r.queries.GetAttachmentThumbnail(ctx, params)
+}
+
+func (r *Repository) ListAttachmentThumbnails(ctx context.Context, attachmentId string) ([]db.AttachmentThumbnail, error) {
+	return r.queries.ListAttachmentThumbnails(ctx, attachmentId)
+}
+
+func (r *Repository) DeleteAttachmentThumbnails(ctx context.Context, attachmentId string) error {
+	return r.queries.DeleteAttachmentThumbnails(ctx, attachmentId)
+}
+
+func (r *Repository) GetAttachment(ctx context.Context, id string) (db.Attachment, error) {
+	return r.queries.GetAttachment(ctx, id)
+}
+
+func (r *Repository) GetMessage(ctx context.Context, id int64) (db.Message, error) {
+	return r.queries.GetMessage(ctx, id)
+}
+
+func (r *Repository) ListOrphanAttachments(ctx context.Context, uploadedBefore time.Time) ([]db.Attachment, error) {
+	return r.queries.ListOrphanAttachments(ctx, uploadedBefore)
+}
+
+func (r *Repository) DeleteAttachment(ctx context.Context, id string) error {
+	return r.queries.DeleteAttachment(ctx, id)
+}
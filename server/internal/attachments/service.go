@@ -0,0 +1,316 @@
+package attachments
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"server/internal/db"
+	"server/internal/ws"
+	"server/pkg/packets"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/segmentio/ksuid"
+)
+
+var (
+	maxAttachmentBytes        = flag.Int("max-attachment-bytes", 10*1024*1024, "Largest attachment that can be uploaded, in bytes")
+	unsentAttachmentRetention = flag.Duration("unsent-attachment-retention", 24*time.Hour, "How long uploaded attachments that were never sent in a message are kept")
+
+	maxFilenameChars = 255
+
+	allowedAttachmentMimes = map[string]bool{
+		"image/png":          true,
+		"image/jpeg":         true,
+		"image/gif":          true,
+		"image/webp":         true,
+		"application/pdf":    true,
+		"application/zip":    true,
+		"text/plain":         true,
+		"audio/mpeg":         true,
+		"audio/wave":         true,
+		"video/mp4":          true,
+		"video/webm":         true,
+		"application/ogg":    true,
+		"application/x-gzip": true,
+	}
+)
+
+type Service struct {
+	repo  Repository
+	blobs BlobStore
+	hub   *ws.Hub
+	rooms ws.Service
+}
+
+func NewService(repository Repository, blobs BlobStore, hub *ws.Hub, rooms ws.Service) Service {
+	return Service{
+		repo:  repository,
+		blobs: blobs,
+		hub:   hub,
+		rooms: rooms,
+	}
+}
+
+// Largest request body an upload can have, the attachment plus the rest of the message
+func MaxUploadBytes() int64 {
+	return int64(*maxAttachmentBytes) + 4096
+}
+
+// Stores an uploaded attachment. It can then be sent in one message by its
+// uploader. Images lose their location metadata and get thumbnails
+func (s *Service) Upload(c context.Context, uploaderId string, upload *packets.UploadAttachmentRequestMessage) (*packets.Message, error) {
+	contentType, err := validateAttachment(upload.Data)
+	if err != nil {
+		reason := fmt.Sprintf("Invalid attachment: %v", err)
+		reasonMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg(reason),
+		}
+		return reasonMessage, nil
+	}
+
+	id := ksuid.New().String()
+	data := upload.Data
+	var info imageInfo
+	if mediaType, _, _ := mime.ParseMediaType(contentType); strings.HasPrefix(mediaType, "image/") {
+		var orientation int
+		data, orientation = stripLocation(data, mediaType)
+		if info, err = processImage(data, orientation); err != nil {
+			// Still worth keeping, clients can show it without a preview
+			log.Printf("Error processing image of attachment %s: %v", id, err)
+		}
+	}
+
+	keys := []string{id}
+	if err := s.blobs.Put(c, id, bytes.NewReader(data)); err != nil {
+		reason := fmt.Sprintf("error storing attachment: %v", err)
+		return nil, errors.New(reason)
+	}
+	for _, thumb := range info.thumbnails {
+		key := thumbnailKey(id, thumb.size)
+		if err := s.blobs.Put(c, key, bytes.NewReader(thumb.data)); err != nil {
+			s.removeBlobs(c, keys)
+			reason := fmt.Sprintf("error storing thumbnail: %v", err)
+			return nil, errors.New(reason)
+		}
+		keys = append(keys, key)
+	}
+
+	attachment, err := s.saveAttachment(c, db.CreateAttachmentParams{
+		ID:         id,
+		UploaderID: uploaderId,
+		Filename:   cleanFilename(upload.Filename),
+		Mime:       contentType,
+		Size:       int64(len(data)),
+		Width:      int64(info.width),
+		Height:     int64(info.height),
+		CreatedAt:  time.Now().UTC(),
+	}, info.thumbnails)
+	if err != nil {
+		s.removeBlobs(c, keys)
+		reason := fmt.Sprintf("error saving attachment: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	attachmentMessage := &packets.Message{
+		Type: packets.NewAttachmentMsg(attachment),
+	}
+	return attachmentMessage, nil
+}
+
+func (s *Service) saveAttachment(c context.Context, params db.CreateAttachmentParams, thumbnails []thumbnail) (*packets.AttachmentMessage, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	saved, err := queries.CreateAttachment(c, params)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := packets.NewAttachment(saved.ID, saved.Filename, saved.Mime, uint64(saved.Size))
+	attachment.Width = uint32(saved.Width)
+	attachment.Height = uint32(saved.Height)
+	for _, thumb := range thumbnails {
+		err := queries.CreateAttachmentThumbnail(c, db.CreateAttachmentThumbnailParams{
+			AttachmentID: saved.ID,
+			Size:         int64(thumb.size),
+			Width:        int64(thumb.width),
+			Height:       int64(thumb.height),
+			Mime:         thumb.mime,
+			Bytes:        int64(len(thumb.data)),
+		})
+		if err != nil {
+			return nil, err
+		}
+		attachment.Thumbnails = append(attachment.Thumbnails, packets.NewThumbnail(uint32(thumb.size), uint32(thumb.width), uint32(thumb.height), thumb.mime))
+	}
+
+	return attachment, tx.Commit()
+}
+
+func (s *Service) removeBlobs(c context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(c, key); err != nil {
+			log.Printf("Error removing blob %s: %v", key, err)
+		}
+	}
+}
+
+func thumbnailKey(attachmentId string, size int) string {
+	return fmt.Sprintf("%s-%d", attachmentId, size)
+}
+
+// Returns the attachment with its contents, or with the type, length and
+// contents of its thumbnail of the given size when it isn't zero. The caller
+// must close the contents. Attachments not sent yet are only visible to their uploader,
+// sent ones to those who can join the room of their message, and those of
+// deleted messages to nobody. Returns sql.ErrNoRows when the user can't see it
+func (s *Service) Open(c context.Context, userId string, id string, thumbnailSize int) (db.Attachment, io.ReadCloser, error) {
+	attachment, err := s.repo.GetAttachment(c, id)
+	if err != nil {
+		return db.Attachment{}, nil, err
+	}
+
+	if !attachment.MessageID.Valid {
+		if attachment.UploaderID != userId {
+			return db.Attachment{}, nil, sql.ErrNoRows
+		}
+	} else if err := s.checkRoomAccess(c, userId, attachment.MessageID.Int64); err != nil {
+		return db.Attachment{}, nil, err
+	}
+
+	key := attachment.ID
+	if thumbnailSize != 0 {
+		thumb, err := s.repo.GetAttachmentThumbnail(c, db.GetAttachmentThumbnailParams{
+			AttachmentID: attachment.ID,
+			Size:         int64(thumbnailSize),
+		})
+		if err != nil {
+			return db.Attachment{}, nil, err
+		}
+		key = thumbnailKey(attachment.ID, thumbnailSize)
+		attachment.Mime = thumb.Mime
+		attachment.Size = thumb.Bytes
+	}
+
+	data, err := s.blobs.Get(c, key)
+	if errors.Is(err, ErrBlobNotFound) {
+		return db.Attachment{}, nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return db.Attachment{}, nil, err
+	}
+	return attachment, data, nil
+}
+
+// Returns sql.ErrNoRows unless the message is still there and the user can join
+// its room, so attachments of private rooms stay within their members
+func (s *Service) checkRoomAccess(c context.Context, userId string, messageId int64) error {
+	message, err := s.repo.GetMessage(c, messageId)
+	if err != nil {
+		return err
+	}
+	if message.DeletedAt.Valid {
+		return sql.ErrNoRows
+	}
+
+	room, found := s.hub.Rooms.Get(uint64(message.RoomID))
+	if !found {
+		return sql.ErrNoRows
+	}
+	allowed, err := s.rooms.CanJoinRoom(c, room, userId)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Deletes attachments of deleted messages and those never sent within the
+// retention period. Returns how many were removed
+func (s *Service) RemoveOrphans(c context.Context) (int64, error) {
+	orphans, err := s.repo.ListOrphanAttachments(c, time.Now().Add(-*unsentAttachmentRetention).UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	removed := int64(0)
+	for _, attachment := range orphans {
+		thumbnails, err := s.repo.ListAttachmentThumbnails(c, attachment.ID)
+		if err != nil {
+			return removed, err
+		}
+		for _, thumb := range thumbnails {
+			if err := s.blobs.Delete(c, thumbnailKey(attachment.ID, int(thumb.Size))); err != nil {
+				return removed, err
+			}
+		}
+		if err := s.repo.DeleteAttachmentThumbnails(c, attachment.ID); err != nil {
+			return removed, err
+		}
+
+		if err := s.blobs.Delete(c, attachment.ID); err != nil {
+			return removed, err
+		}
+		if err := s.repo.DeleteAttachment(c, attachment.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// Returns the MIME type of the attachment if it can be uploaded
+func validateAttachment(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", errors.New("empty")
+	}
+	if len(data) > *maxAttachmentBytes {
+		reason := fmt.Sprintf("larger than %d bytes", *maxAttachmentBytes)
+		return "", errors.New(reason)
+	}
+
+	contentType := http.DetectContentType(data)
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || !allowedAttachmentMimes[mediaType] {
+		reason := fmt.Sprintf("unsupported type %s", contentType)
+		return "", errors.New(reason)
+	}
+	return contentType, nil
+}
+
+// Keeps only the base name of the file, without characters that could break
+// the download headers
+func cleanFilename(filename string) string {
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '/' || r == '\\' {
+			return -1
+		}
+		return r
+	}, filepath.Base(filename))
+	filename = strings.TrimSpace(filename)
+
+	if utf8.RuneCountInString(filename) > maxFilenameChars {
+		filename = string([]rune(filename)[:maxFilenameChars])
+	}
+	if filename == "" || filename == "." {
+		return "attachment"
+	}
+	return filename
+}
@@ -0,0 +1,90 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Error returned by blob stores when there is no blob with the key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// Where attachment contents are kept. Keys are attachment ids
+type BlobStore interface {
+	Put(c context.Context, key string, data io.Reader) error
+	Get(c context.Context, key string) (io.ReadCloser, error)
+	Delete(c context.Context, key string) error
+}
+
+// Keeps every blob as a file in a directory of the local disk
+type DiskStore struct {
+	dir string
+}
+
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		reason := fmt.Sprintf("error creating attachments directory: %v", err)
+		return nil, errors.New(reason)
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+// Writes to a temporary file first, so a failed upload never leaves a partial blob
+func (s *DiskStore) Put(c context.Context, key string, data io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (s *DiskStore) Get(c context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return file, err
+}
+
+func (s *DiskStore) Delete(c context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *DiskStore) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key || key[0] == '.' {
+		reason := fmt.Sprintf("invalid blob key %q", key)
+		return "", errors.New(reason)
+	}
+	return filepath.Join(s.dir, key), nil
+}
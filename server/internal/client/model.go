@@ -11,18 +11,39 @@ type ClientInterfacer interface {
 	Id() uint64
 	UserId() string
 	Username() string
-	RoomId() uint64
+	Profile() *packets.ProfileMessage
+
+	// Replaces the cached profile, e.g. after the user edits it
+	SetProfile(profile *packets.ProfileMessage)
+
+	// Rooms the client is currently a member of
+	RoomIds() []uint64
+	IsInRoom(roomId uint64) bool
+
+	// Called by the hub after the client was added to a room. It runs on the hub, so the
+	// room's state is sent by whoever asked the hub to add the client
+	JoinedRoom(roomId uint64)
+
+	// Called by the hub after the client was removed from a room
+	LeftRoom(roomId uint64)
 
 	ProcessMessage(senderId uint64, roomId uint64, message packets.Pkt)
 
-	// Puts data from this client into the write pump
+	// Puts data from this client into the write pump, outside of any room
 	SocketSend(message packets.Pkt)
 
 	// Puts data from another client into the write pump
 	SocketSendAs(message packets.Pkt, senderId uint64, roomId uint64)
 
+	// Puts a packet into the write pump as it is, e.g. a room event with its sequence number
+	SocketSendPacket(packet *packets.Packet)
+
+	// Records that a packet for the client was dropped before reaching its send buffer,
+	// so the client gets told to resync
+	MissedPacket(roomId uint64)
+
 	// Foward message to another client for processing
-	PassToPeer(message packets.Pkt, peerId uint64)
+	PassToPeer(message packets.Pkt, peerId uint64, roomId uint64)
 
 	// Forward message to all other clients for processing
 	Broadcast(message packets.Pkt, roomId uint64)
@@ -1,17 +1,47 @@
 -- name: CreateUser :one
 INSERT INTO users (
-  id, username, password_hash
+  id, username, username_key, password_hash
 ) VALUES (
-  ?, ?, ?
+  ?, ?, ?, ?
 )
 RETURNING *;
 
 -- name: GetUserByUsername :one
 SELECT *
 FROM users
-WHERE LOWER(username) = LOWER(?)
+WHERE username_key = ?
 LIMIT 1;
 
+-- name: GetUserById :one
+SELECT *
+FROM users
+WHERE id = ?
+LIMIT 1;
+
+-- name: ListUsers :many
+SELECT *
+FROM users
+ORDER BY created_at;
+
+-- name: ListDisabledUserIds :many
+SELECT id
+FROM users
+WHERE disabled_at IS NOT NULL;
+
+-- name: SetUserDisabled :execrows
+UPDATE users
+SET disabled_at = ?
+WHERE id = ?;
+
+-- name: UpdatePassword :execrows
+UPDATE users
+SET password_hash = ?
+WHERE id = ?;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+
 -- name: GetUsernameById :one
 SELECT username
 FROM users
@@ -49,7 +79,922 @@ SELECT *
 FROM refresh_tokens
 WHERE user_id = ?;
 
+-- name: ListTokens :many
+SELECT *
+FROM refresh_tokens
+ORDER BY created_at;
+
+-- name: DeleteTokensForUser :exec
+DELETE FROM refresh_tokens
+WHERE user_id = ?;
+
 -- name: DeleteExpiredOrRevokedTokens :execrows
 DELETE FROM refresh_tokens
 WHERE expire_at <= CURRENT_TIMESTAMP
-  OR revoked_at IS NOT NULL;
\ No newline at end of file
+  OR revoked_at IS NOT NULL;
+
+-- name: GetProfile :one
+SELECT user_id, display_name, bio, status, avatar_mime, version, updated_at
+FROM profiles
+WHERE user_id = ?
+LIMIT 1;
+
+-- name: UpsertProfile :exec
+INSERT INTO profiles (
+  user_id, display_name, bio, status
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET display_name = excluded.display_name,
+  bio = excluded.bio,
+  status = excluded.status,
+  version = version + 1,
+  updated_at = CURRENT_TIMESTAMP;
+
+-- name: SetProfileDisplayName :exec
+INSERT INTO profiles (
+  user_id, display_name
+) VALUES (
+  ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET display_name = excluded.display_name,
+  version = version + 1,
+  updated_at = CURRENT_TIMESTAMP;
+
+-- name: SetProfileAvatar :exec
+-- Saved along with UpsertProfile, which counts the change in the version
+UPDATE profiles
+SET avatar = ?,
+  avatar_mime = ?
+WHERE user_id = ?;
+
+-- name: GetProfileAvatar :one
+SELECT avatar, avatar_mime
+FROM profiles
+WHERE user_id = ?
+  AND avatar IS NOT NULL
+LIMIT 1;
+
+-- name: DeleteProfile :exec
+DELETE FROM profiles
+WHERE user_id = ?;
+
+-- name: CreateRoom :one
+INSERT INTO rooms (
+  owner_id, name, visibility
+) VALUES (
+  ?, ?, ?
+)
+RETURNING *;
+
+-- name: ListRooms :many
+SELECT *
+FROM rooms
+ORDER BY id;
+
+-- name: ListRoomIdsByOwner :many
+SELECT id
+FROM rooms
+WHERE owner_id = ?
+ORDER BY id;
+
+-- name: GetRoomSuccessor :one
+-- Who inherits the room when its owner leaves: its moderators, then its other
+-- members, then whoever has been posting in it the longest
+SELECT candidates.user_id
+FROM (
+  SELECT rm.user_id, rm.role AS priority, rm.created_at AS since
+  FROM room_members rm
+  WHERE rm.room_id = sqlc.arg(room_id)
+  UNION ALL
+  SELECT m.sender_id AS user_id, -1 AS priority, MIN(m.created_at) AS since
+  FROM messages m
+  WHERE m.room_id = sqlc.arg(room_id)
+    AND m.deleted_at IS NULL
+  GROUP BY m.sender_id
+) candidates
+JOIN users u ON u.id = candidates.user_id
+WHERE u.id != sqlc.arg(owner_id)
+  AND u.disabled_at IS NULL
+ORDER BY candidates.priority DESC, candidates.since
+LIMIT 1;
+
+-- name: UpdateRoomOwner :exec
+UPDATE rooms
+SET owner_id = ?
+WHERE id = ?;
+
+-- name: SetRoomSlowMode :exec
+UPDATE rooms
+SET slow_mode_seconds = ?
+WHERE id = ?;
+
+-- name: SetRoomDetails :exec
+UPDATE rooms
+SET topic = ?,
+  description = ?
+WHERE id = ?;
+
+-- name: RenameRoom :exec
+UPDATE rooms
+SET name = ?
+WHERE id = ?;
+
+-- name: SetRoomVisibility :exec
+UPDATE rooms
+SET visibility = ?
+WHERE id = ?;
+
+-- name: DeleteRoom :execrows
+DELETE FROM rooms
+WHERE id = ?;
+
+-- name: DeleteRoomMessageEdits :exec
+DELETE FROM message_edits
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?);
+
+-- name: DeleteRoomReactions :exec
+DELETE FROM message_reactions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?);
+
+-- name: DeleteRoomMentions :exec
+DELETE FROM mentions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?);
+
+-- name: DeleteRoomLinkPreviews :exec
+DELETE FROM message_link_previews
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?);
+
+-- name: DetachRoomAttachments :exec
+-- Their files are removed with the other orphans, uploaded long before
+UPDATE attachments
+SET message_id = NULL
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?);
+
+-- name: DeleteRoomMessages :exec
+DELETE FROM messages
+WHERE room_id = ?;
+
+-- name: DeleteRoomReads :exec
+DELETE FROM room_reads
+WHERE room_id = ?;
+
+-- name: DeleteRoomEvents :exec
+DELETE FROM room_events
+WHERE room_id = ?;
+
+-- name: DeleteRoomMutes :exec
+DELETE FROM room_mutes
+WHERE room_id = ?;
+
+-- name: DeleteRoomMembers :exec
+DELETE FROM room_members
+WHERE room_id = ?;
+
+-- name: DeleteRoomInvites :exec
+DELETE FROM room_invites
+WHERE room_id = ?;
+
+-- name: DeleteRoomInviteCodes :exec
+DELETE FROM room_invite_codes
+WHERE room_id = ?;
+
+-- name: DeleteRoomInviteCodeUses :exec
+DELETE FROM room_invite_code_uses
+WHERE room_id = ?;
+
+-- name: CreateConversation :one
+INSERT INTO conversations (
+  member_key
+) VALUES (
+  ?
+)
+RETURNING *;
+
+-- name: GetConversationByMemberKey :one
+SELECT *
+FROM conversations
+WHERE member_key = ?
+LIMIT 1;
+
+-- name: TouchConversation :exec
+UPDATE conversations
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: AddConversationMember :exec
+INSERT INTO conversation_members (
+  conversation_id, user_id
+) VALUES (
+  ?, ?
+);
+
+-- name: ListConversationMembers :many
+SELECT m.user_id, u.username
+FROM conversation_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.conversation_id = ?
+ORDER BY u.username;
+
+-- name: ListConversationsForUser :many
+SELECT c.*
+FROM conversations c
+JOIN conversation_members m ON m.conversation_id = c.id
+WHERE m.user_id = ?
+ORDER BY c.updated_at DESC;
+
+-- name: RemoveUserFromConversations :exec
+DELETE FROM conversation_members
+WHERE user_id = ?;
+
+-- name: CreateDirectMessage :one
+INSERT INTO direct_messages (
+  conversation_id, sender_id, msg
+) VALUES (
+  ?, ?, ?
+)
+RETURNING *;
+
+-- name: ListDirectMessages :many
+SELECT d.id, d.conversation_id, d.sender_id, u.username AS sender_username, d.msg, d.created_at
+FROM direct_messages d
+LEFT JOIN users u ON u.id = d.sender_id
+WHERE d.conversation_id = ?
+  AND d.id < ?
+ORDER BY d.id DESC
+LIMIT ?;
+
+-- name: ListDirectMessagesBySender :many
+SELECT *
+FROM direct_messages
+WHERE sender_id = ?
+ORDER BY id;
+
+-- name: AnonymizeDirectMessagesBySender :exec
+UPDATE direct_messages
+SET sender_id = ''
+WHERE sender_id = ?;
+
+-- name: DeleteDirectMessagesBySender :exec
+DELETE FROM direct_messages
+WHERE sender_id = ?;
+
+-- name: CreateMessage :one
+INSERT INTO messages (
+  room_id, parent_id, sender_id, sender_username, msg, client_id, action, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetMessage :one
+SELECT *
+FROM messages
+WHERE id = ?
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: UpdateMessage :one
+UPDATE messages
+SET msg = ?,
+  edited_at = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: CreateMessageEdit :exec
+INSERT INTO message_edits (
+  message_id, previous_msg, edited_by
+) VALUES (
+  ?, ?, ?
+);
+
+-- name: ListMessageEdits :many
+SELECT *
+FROM message_edits
+WHERE message_id = ?
+ORDER BY id;
+
+-- name: DeleteMessage :exec
+UPDATE messages
+SET msg = '',
+  deleted_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteMessageEdits :exec
+DELETE FROM message_edits
+WHERE message_id = ?;
+
+-- name: ListMessagesBySender :many
+SELECT *
+FROM messages
+WHERE sender_id = ?
+  AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: AnonymizeMessagesBySender :exec
+-- The client ids go too, anonymous messages can't be resent
+UPDATE messages
+SET sender_id = '',
+  sender_username = ?,
+  client_id = NULL
+WHERE sender_id = ?;
+
+-- name: DeleteMessagesBySender :exec
+-- Rows are kept so message ids never get reused, with nothing left of the sender
+UPDATE messages
+SET sender_id = '',
+  sender_username = ?,
+  msg = '',
+  client_id = NULL,
+  action = FALSE,
+  deleted_at = CURRENT_TIMESTAMP
+WHERE sender_id = ?;
+
+-- name: DeleteReactionsOnMessagesBySender :exec
+DELETE FROM message_reactions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?);
+
+-- name: DeleteMentionsOnMessagesBySender :exec
+DELETE FROM mentions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?);
+
+-- name: DeleteMessageEditsBySender :exec
+DELETE FROM message_edits
+WHERE edited_by = ?
+  OR message_id IN (SELECT id FROM messages WHERE sender_id = ?);
+
+-- name: AddReaction :exec
+INSERT INTO message_reactions (
+  message_id, user_id, emoji
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveReaction :exec
+DELETE FROM message_reactions
+WHERE message_id = ?
+  AND user_id = ?
+  AND emoji = ?;
+
+-- name: CountReactionEmojis :one
+SELECT COUNT(DISTINCT emoji)
+FROM message_reactions
+WHERE message_id = ?;
+
+-- name: ListReactions :many
+SELECT emoji, user_id
+FROM message_reactions
+WHERE message_id = ?
+ORDER BY rowid;
+
+-- name: ListRoomReactions :many
+SELECT r.message_id, r.emoji, r.user_id
+FROM message_reactions r
+JOIN messages m ON m.id = r.message_id
+WHERE m.room_id = ?
+  AND r.message_id >= ?
+ORDER BY r.message_id, r.rowid;
+
+-- name: DeleteMessageReactions :exec
+DELETE FROM message_reactions
+WHERE message_id = ?;
+
+-- name: ListReactionsByUser :many
+SELECT *
+FROM message_reactions
+WHERE user_id = ?
+ORDER BY created_at;
+
+-- name: DeleteReactionsByUser :exec
+DELETE FROM message_reactions
+WHERE user_id = ?;
+
+-- name: ListThreadReplies :many
+SELECT *
+FROM messages
+WHERE parent_id = ?
+  AND id > ?
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT ?;
+
+-- name: CountThreadReplies :one
+SELECT COUNT(*)
+FROM messages
+WHERE parent_id = ?
+  AND deleted_at IS NULL;
+
+-- name: ListRoomReplyCounts :many
+SELECT parent_id, COUNT(*) AS reply_count
+FROM messages
+WHERE room_id = ?
+  AND parent_id >= ?
+  AND deleted_at IS NULL
+GROUP BY parent_id;
+
+-- name: ListThreadReactions :many
+SELECT r.message_id, r.emoji, r.user_id
+FROM message_reactions r
+JOIN messages m ON m.id = r.message_id
+WHERE m.id = sqlc.arg(parent_id)
+  OR m.parent_id = sqlc.arg(parent_id)
+ORDER BY r.message_id, r.rowid;
+
+-- name: GetMessageRoomId :one
+SELECT room_id
+FROM messages
+WHERE id = ?
+LIMIT 1;
+
+-- name: MarkRoomRead :execrows
+INSERT INTO room_reads (
+  user_id, room_id, last_read_id
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT (user_id, room_id) DO UPDATE
+SET last_read_id = excluded.last_read_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE excluded.last_read_id > room_reads.last_read_id;
+
+-- name: ListRoomReadsForUser :many
+SELECT *
+FROM room_reads
+WHERE user_id = ?
+ORDER BY room_id;
+
+-- name: ListUnreadCounts :many
+-- Only the rooms the user can see, each counted from its read position on
+-- the messages(room_id, id) index. CROSS JOIN keeps SQLite from scanning
+-- all the messages first
+SELECT r.id AS room_id, COUNT(*) AS unread_count
+FROM rooms r
+LEFT JOIN room_reads rr ON rr.room_id = r.id AND rr.user_id = sqlc.arg(user_id)
+CROSS JOIN messages m ON m.room_id = r.id AND m.id > COALESCE(rr.last_read_id, 0)
+WHERE m.sender_id != sqlc.arg(user_id)
+  AND m.deleted_at IS NULL
+  AND (
+    r.visibility != 1
+    OR r.owner_id = sqlc.arg(user_id)
+    OR EXISTS (
+      SELECT 1 FROM room_members rm WHERE rm.room_id = r.id AND rm.user_id = sqlc.arg(user_id)
+    )
+  )
+GROUP BY r.id;
+
+-- name: DeleteRoomReadsForUser :exec
+DELETE FROM room_reads
+WHERE user_id = ?;
+
+-- name: GetPresence :one
+SELECT *
+FROM presence
+WHERE user_id = ?
+LIMIT 1;
+
+-- name: SetPresenceStatus :exec
+INSERT INTO presence (
+  user_id, status
+) VALUES (
+  ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET status = excluded.status;
+
+-- name: SetLastSeen :exec
+-- Nothing is saved for users whose account was deleted while they were online
+INSERT INTO presence (
+  user_id, last_seen_at
+)
+SELECT u.id, sqlc.arg(last_seen_at)
+FROM users u
+WHERE u.id = sqlc.arg(user_id)
+ON CONFLICT (user_id) DO UPDATE
+SET last_seen_at = excluded.last_seen_at;
+
+-- name: DeletePresence :exec
+DELETE FROM presence
+WHERE user_id = ?;
+
+-- name: CreateMention :one
+INSERT INTO mentions (
+  message_id, user_id
+) VALUES (
+  ?, ?
+)
+RETURNING *;
+
+-- name: ListUnseenMentions :many
+-- Mentions in private rooms (visibility 1) are only listed to their owner and members
+SELECT mn.id, mn.message_id, m.room_id, m.sender_id, m.sender_username, m.msg, m.created_at
+FROM mentions mn
+JOIN messages m ON m.id = mn.message_id
+JOIN rooms r ON r.id = m.room_id
+WHERE mn.user_id = sqlc.arg(user_id)
+  AND mn.seen_at IS NULL
+  AND m.deleted_at IS NULL
+  AND (
+    r.visibility != 1
+    OR r.owner_id = sqlc.arg(user_id)
+    OR EXISTS (
+      SELECT 1 FROM room_members rm WHERE rm.room_id = r.id AND rm.user_id = sqlc.arg(user_id)
+    )
+  )
+ORDER BY mn.id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: MarkMentionsSeen :exec
+UPDATE mentions
+SET seen_at = CURRENT_TIMESTAMP
+WHERE user_id = ?
+  AND seen_at IS NULL
+  AND message_id IN (
+    SELECT m.id FROM messages m WHERE m.room_id = ? AND m.id <= sqlc.arg(last_read_id)
+  );
+
+-- name: DeleteMessageMentions :exec
+DELETE FROM mentions
+WHERE message_id = ?;
+
+-- name: DeleteMentionsForUser :exec
+DELETE FROM mentions
+WHERE user_id = ?;
+
+-- name: GetMessageByClientId :one
+SELECT *
+FROM messages
+WHERE sender_id = ?
+  AND client_id = ?
+LIMIT 1;
+
+-- name: GetRoomLastSeq :one
+SELECT last_seq
+FROM rooms
+WHERE id = ?
+LIMIT 1;
+
+-- name: NextRoomSeq :one
+UPDATE rooms
+SET last_seq = last_seq + 1
+WHERE id = ?
+RETURNING last_seq;
+
+-- name: CreateRoomEvent :exec
+INSERT INTO room_events (
+  room_id, seq, user_id, message_id, packet, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+);
+
+-- name: ListRoomEvents :many
+SELECT *
+FROM room_events
+WHERE room_id = ?
+  AND seq > ?
+ORDER BY seq
+LIMIT ?;
+
+-- name: ListRoomEventsForMessage :many
+SELECT *
+FROM room_events
+WHERE message_id = ?;
+
+-- name: UpdateRoomEventPacket :exec
+UPDATE room_events
+SET packet = ?
+WHERE room_id = ?
+  AND seq = ?;
+
+-- name: DeleteOldRoomEvents :execrows
+DELETE FROM room_events
+WHERE created_at < ?;
+
+-- name: DeleteRoomEventsByUser :exec
+-- Both the events the user caused and the ones about their messages
+DELETE FROM room_events
+WHERE user_id = sqlc.arg(user_id)
+  OR message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = sqlc.arg(user_id));
+
+-- name: CreateAttachment :one
+INSERT INTO attachments (
+  id, uploader_id, filename, mime, size, width, height, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: CreateAttachmentThumbnail :exec
+INSERT INTO attachment_thumbnails (
+  attachment_id, size, width, height, mime, bytes
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+);
+
+-- name: GetAttachmentThumbnail :one
+SELECT *
+FROM attachment_thumbnails
+WHERE attachment_id = ?
+  AND size = ?
+LIMIT 1;
+
+-- name: ListAttachmentThumbnails :many
+SELECT *
+FROM attachment_thumbnails
+WHERE attachment_id = ?
+ORDER BY size;
+
+-- name: ListMessageThumbnails :many
+SELECT t.*
+FROM attachment_thumbnails t
+JOIN attachments a ON a.id = t.attachment_id
+WHERE a.message_id = ?
+ORDER BY t.attachment_id, t.size;
+
+-- name: ListThreadThumbnails :many
+SELECT t.*
+FROM attachment_thumbnails t
+JOIN attachments a ON a.id = t.attachment_id
+JOIN messages m ON m.id = a.message_id
+WHERE m.id = sqlc.arg(parent_id)
+  OR m.parent_id = sqlc.arg(parent_id)
+ORDER BY t.attachment_id, t.size;
+
+-- name: DeleteAttachmentThumbnails :exec
+DELETE FROM attachment_thumbnails
+WHERE attachment_id = ?;
+
+-- name: GetAttachment :one
+SELECT *
+FROM attachments
+WHERE id = ?
+LIMIT 1;
+
+-- name: BindAttachment :one
+UPDATE attachments
+SET message_id = ?
+WHERE id = ?
+  AND uploader_id = ?
+  AND message_id IS NULL
+RETURNING *;
+
+-- name: ListMessageAttachments :many
+SELECT *
+FROM attachments
+WHERE message_id = ?
+ORDER BY rowid;
+
+-- name: ListThreadAttachments :many
+SELECT a.*
+FROM attachments a
+JOIN messages m ON m.id = a.message_id
+WHERE m.id = sqlc.arg(parent_id)
+  OR m.parent_id = sqlc.arg(parent_id)
+ORDER BY a.message_id, a.rowid;
+
+-- name: ListOrphanAttachments :many
+SELECT a.*
+FROM attachments a
+LEFT JOIN messages m ON m.id = a.message_id
+WHERE (a.message_id IS NULL AND a.created_at < ?)
+  OR (a.message_id IS NOT NULL AND m.id IS NULL)
+  OR m.deleted_at IS NOT NULL;
+
+-- name: ListAttachmentsByUploader :many
+SELECT *
+FROM attachments
+WHERE uploader_id = ?
+ORDER BY created_at;
+
+-- name: AnonymizeAttachmentsByUploader :exec
+UPDATE attachments
+SET uploader_id = ''
+WHERE uploader_id = ?;
+
+-- name: DeleteAttachment :exec
+DELETE FROM attachments
+WHERE id = ?;
+
+-- name: GetLinkPreview :one
+SELECT *
+FROM link_previews
+WHERE url = ?
+LIMIT 1;
+
+-- name: UpsertLinkPreview :one
+INSERT INTO link_previews (
+  url, title, description, image_url, site_name, fetched_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (url) DO UPDATE SET
+  title = excluded.title,
+  description = excluded.description,
+  image_url = excluded.image_url,
+  site_name = excluded.site_name,
+  fetched_at = excluded.fetched_at
+RETURNING *;
+
+-- name: DeleteOldLinkPreviews :execrows
+DELETE FROM link_previews
+WHERE fetched_at < ?;
+
+-- name: CreateMessageLinkPreview :exec
+INSERT INTO message_link_previews (
+  message_id, url, title, description, image_url, site_name, position
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+);
+
+-- name: DeleteMessageLinkPreviews :exec
+DELETE FROM message_link_previews
+WHERE message_id = ?;
+
+-- name: DeleteLinkPreviewsBySender :exec
+DELETE FROM message_link_previews
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?);
+
+-- name: ListMessageLinkPreviews :many
+SELECT *
+FROM message_link_previews
+WHERE message_id = ?
+ORDER BY position;
+
+-- name: ListThreadLinkPreviews :many
+SELECT mp.*
+FROM message_link_previews mp
+JOIN messages m ON m.id = mp.message_id
+WHERE m.id = sqlc.arg(parent_id)
+  OR m.parent_id = sqlc.arg(parent_id)
+ORDER BY mp.message_id, mp.position;
+
+-- name: GetRoomMute :one
+SELECT *
+FROM room_mutes
+WHERE room_id = ?
+  AND user_id = ?
+  AND muted_until > ?
+LIMIT 1;
+
+-- name: UpsertRoomMute :exec
+INSERT INTO room_mutes (
+  room_id, user_id, muted_by, muted_until
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET muted_by = excluded.muted_by,
+  muted_until = excluded.muted_until;
+
+-- name: DeleteRoomMute :execrows
+DELETE FROM room_mutes
+WHERE room_id = ?
+  AND user_id = ?;
+
+-- name: DeleteExpiredRoomMutes :execrows
+DELETE FROM room_mutes
+WHERE muted_until <= ?;
+
+-- name: DeleteRoomMutesForUser :exec
+DELETE FROM room_mutes
+WHERE user_id = ?;
+
+-- name: AddRoomMember :exec
+INSERT INTO room_members (
+  room_id, user_id, added_by
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO NOTHING;
+
+-- name: IsRoomMember :one
+SELECT EXISTS (
+  SELECT 1
+  FROM room_members
+  WHERE room_id = ?
+    AND user_id = ?
+);
+
+-- name: GetRoomMember :one
+SELECT *
+FROM room_members
+WHERE room_id = ?
+  AND user_id = ?;
+
+-- name: UpsertRoomMemberRole :exec
+INSERT INTO room_members (
+  room_id, user_id, added_by, role
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET role = max(room_members.role, excluded.role);
+
+-- name: ListModeratorMemberships :many
+SELECT room_id, user_id
+FROM room_members
+WHERE role >= 1;
+
+-- name: ListMemberRoomIds :many
+SELECT room_id
+FROM room_members
+WHERE user_id = ?;
+
+-- name: DeleteRoomMember :execrows
+DELETE FROM room_members
+WHERE room_id = ?
+  AND user_id = ?;
+
+-- name: DeleteRoomMembershipsForUser :exec
+DELETE FROM room_members
+WHERE user_id = ?;
+
+-- name: UpsertRoomInvite :exec
+INSERT INTO room_invites (
+  room_id, user_id, inviter_id, created_at
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET inviter_id = excluded.inviter_id,
+  created_at = excluded.created_at;
+
+-- name: GetRoomInvite :one
+SELECT *
+FROM room_invites
+WHERE room_id = ?
+  AND user_id = ?;
+
+-- name: ListRoomInvitesForUser :many
+SELECT i.room_id, r.name AS room_name, i.inviter_id, u.username AS inviter_username
+FROM room_invites i
+JOIN rooms r ON r.id = i.room_id
+JOIN users u ON u.id = i.inviter_id
+WHERE i.user_id = ?
+ORDER BY i.created_at;
+
+-- name: DeleteRoomInvite :exec
+DELETE FROM room_invites
+WHERE room_id = ?
+  AND user_id = ?;
+
+-- name: DeleteRoomInvitesForUser :exec
+DELETE FROM room_invites
+WHERE user_id = sqlc.arg(user_id)
+  OR inviter_id = sqlc.arg(user_id);
+
+-- name: CreateInviteCode :one
+INSERT INTO room_invite_codes (
+  code, room_id, created_by, role, max_uses, expires_at, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetInviteCode :one
+SELECT *
+FROM room_invite_codes
+WHERE code = ?;
+
+-- name: ListInviteCodes :many
+SELECT *
+FROM room_invite_codes
+WHERE room_id = ?
+ORDER BY created_at DESC;
+
+-- name: RevokeInviteCode :exec
+UPDATE room_invite_codes
+SET revoked_at = ?
+WHERE code = ?
+  AND revoked_at IS NULL;
+
+-- name: UseInviteCode :execrows
+UPDATE room_invite_codes
+SET uses = uses + 1
+WHERE code = ?
+  AND (max_uses = 0 OR uses < max_uses);
+
+-- name: CreateInviteCodeUse :exec
+INSERT INTO room_invite_code_uses (
+  code, room_id, user_id, role, used_at
+) VALUES (
+  ?, ?, ?, ?, ?
+);
+
+-- name: ListInviteCodeUses :many
+SELECT cu.code, cu.user_id, u.username, cu.used_at
+FROM room_invite_code_uses cu
+JOIN users u ON u.id = cu.user_id
+WHERE cu.room_id = ?
+ORDER BY cu.used_at;
+
+-- name: DeleteInviteCodeUsesForUser :exec
+DELETE FROM room_invite_code_uses
+WHERE user_id = sqlc.arg(user_id)
+  OR code IN (SELECT c.code FROM room_invite_codes c WHERE c.created_by = sqlc.arg(user_id));
+
+-- name: DeleteInviteCodesByCreator :exec
+DELETE FROM room_invite_codes
+WHERE created_by = ?;
@@ -1,10 +1,15 @@
 CREATE TABLE IF NOT EXISTS users (
   id TEXT PRIMARY KEY,
   username TEXT UNIQUE NOT NULL,
+  username_key TEXT UNIQUE NOT NULL,
   password_hash TEXT NOT NULL,
-  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  disabled_at DATETIME
 );
 
+-- Databases from before usernames had keys get the column without its constraint
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_key ON users(username_key);
+
 CREATE TABLE IF NOT EXISTS refresh_tokens (
   jti TEXT PRIMARY KEY,
   user_id TEXT NOT NULL,
@@ -12,4 +17,256 @@ CREATE TABLE IF NOT EXISTS refresh_tokens (
   expire_at DATETIME NOT NULL,
   revoked_at DATETIME,
   FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS profiles (
+  user_id TEXT PRIMARY KEY,
+  display_name TEXT NOT NULL DEFAULT '',
+  bio TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL DEFAULT '',
+  avatar BLOB,
+  avatar_mime TEXT NOT NULL DEFAULT '',
+  version INTEGER NOT NULL DEFAULT 0,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS rooms (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  owner_id TEXT NOT NULL,
+  name TEXT NOT NULL,
+  last_seq INTEGER NOT NULL DEFAULT 0,
+  slow_mode_seconds INTEGER NOT NULL DEFAULT 0,
+  topic TEXT NOT NULL DEFAULT '',
+  description TEXT NOT NULL DEFAULT '',
+  -- A RoomVisibility, public unless set otherwise
+  visibility INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS conversations (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  member_key TEXT UNIQUE NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS conversation_members (
+  conversation_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  PRIMARY KEY (conversation_id, user_id),
+  FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS direct_messages (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  conversation_id INTEGER NOT NULL,
+  sender_id TEXT NOT NULL,
+  msg TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (conversation_id) REFERENCES conversations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS messages (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  room_id INTEGER NOT NULL,
+  parent_id INTEGER,
+  sender_id TEXT NOT NULL,
+  sender_username TEXT NOT NULL,
+  msg TEXT NOT NULL,
+  client_id TEXT,
+  -- Sent with /me, the text describes what the sender does
+  action BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  edited_at DATETIME,
+  deleted_at DATETIME,
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS messages_room_id ON messages(room_id, id);
+CREATE INDEX IF NOT EXISTS messages_parent_id ON messages(parent_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS messages_client_id ON messages(sender_id, client_id);
+
+CREATE TABLE IF NOT EXISTS attachments (
+  id TEXT PRIMARY KEY,
+  uploader_id TEXT NOT NULL,
+  message_id INTEGER,
+  filename TEXT NOT NULL,
+  mime TEXT NOT NULL,
+  size INTEGER NOT NULL,
+  width INTEGER NOT NULL DEFAULT 0,
+  height INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL,
+  FOREIGN KEY (message_id) REFERENCES messages(id)
+);
+
+CREATE INDEX IF NOT EXISTS attachments_message_id ON attachments(message_id);
+
+CREATE TABLE IF NOT EXISTS attachment_thumbnails (
+  attachment_id TEXT NOT NULL,
+  size INTEGER NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  mime TEXT NOT NULL,
+  bytes INTEGER NOT NULL,
+  PRIMARY KEY (attachment_id, size),
+  FOREIGN KEY (attachment_id) REFERENCES attachments(id)
+);
+
+-- Metadata fetched for URLs in messages. URLs that couldn't be unfurled are kept
+-- with an empty title so they aren't fetched again until the row expires
+CREATE TABLE IF NOT EXISTS link_previews (
+  url TEXT PRIMARY KEY,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  image_url TEXT NOT NULL,
+  site_name TEXT NOT NULL,
+  fetched_at DATETIME NOT NULL
+);
+
+-- Previews shown with each message, copied from the cache so they outlive it
+CREATE TABLE IF NOT EXISTS message_link_previews (
+  message_id INTEGER NOT NULL,
+  url TEXT NOT NULL,
+  title TEXT NOT NULL,
+  description TEXT NOT NULL,
+  image_url TEXT NOT NULL,
+  site_name TEXT NOT NULL,
+  position INTEGER NOT NULL,
+  PRIMARY KEY (message_id, url),
+  FOREIGN KEY (message_id) REFERENCES messages(id)
+);
+
+CREATE TABLE IF NOT EXISTS message_edits (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  message_id INTEGER NOT NULL,
+  previous_msg TEXT NOT NULL,
+  edited_by TEXT NOT NULL,
+  edited_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS message_reactions (
+  message_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  emoji TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (message_id, user_id, emoji),
+  FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS room_reads (
+  user_id TEXT NOT NULL,
+  room_id INTEGER NOT NULL,
+  last_read_id INTEGER NOT NULL,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, room_id),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS presence (
+  user_id TEXT PRIMARY KEY,
+  status INTEGER NOT NULL DEFAULT 1,
+  last_seen_at DATETIME,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS mentions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  message_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  seen_at DATETIME,
+  UNIQUE (message_id, user_id),
+  FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS mentions_user_id ON mentions(user_id, seen_at);
+
+CREATE TABLE IF NOT EXISTS room_events (
+  room_id INTEGER NOT NULL,
+  seq INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  -- The message the event is about, if any
+  message_id INTEGER,
+  packet BLOB NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room_id, seq),
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS room_events_message_id ON room_events(message_id);
+
+-- Members who can't send messages or react in a room until muted_until
+CREATE TABLE IF NOT EXISTS room_mutes (
+  room_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  muted_by TEXT NOT NULL,
+  muted_until DATETIME NOT NULL,
+  PRIMARY KEY (room_id, user_id),
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+-- Users who can join a private room, besides its owner
+CREATE TABLE IF NOT EXISTS room_members (
+  room_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  added_by TEXT NOT NULL,
+  -- A RoomRole, moderators can moderate the room like its owner
+  role INTEGER NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room_id, user_id),
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS room_members_user_id ON room_members(user_id);
+
+-- Invitations to join a room that the invited user hasn't answered yet
+CREATE TABLE IF NOT EXISTS room_invites (
+  room_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  inviter_id TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (room_id, user_id),
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS room_invites_user_id ON room_invites(user_id);
+
+-- Shareable codes that make whoever redeems them a member of the room
+CREATE TABLE IF NOT EXISTS room_invite_codes (
+  code TEXT PRIMARY KEY,
+  room_id INTEGER NOT NULL,
+  created_by TEXT NOT NULL,
+  -- The RoomRole members who redeem the code get
+  role INTEGER NOT NULL DEFAULT 0,
+  -- Zero when the code can be redeemed any number of times
+  max_uses INTEGER NOT NULL DEFAULT 0,
+  uses INTEGER NOT NULL DEFAULT 0,
+  expires_at DATETIME,
+  revoked_at DATETIME,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  FOREIGN KEY (room_id) REFERENCES rooms(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS room_invite_codes_room_id ON room_invite_codes(room_id);
+
+-- Who redeemed each invite code and when
+CREATE TABLE IF NOT EXISTS room_invite_code_uses (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  code TEXT NOT NULL,
+  room_id INTEGER NOT NULL,
+  user_id TEXT NOT NULL,
+  role INTEGER NOT NULL,
+  used_at DATETIME NOT NULL,
+  FOREIGN KEY (code) REFERENCES room_invite_codes(code) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS room_invite_code_uses_room_id ON room_invite_code_uses(room_id, used_at);
@@ -14,14 +14,24 @@ import (
 //go:embed config/schema.sql
 var schemaGenSql string
 
-func NewDatabase() (*sql.DB, error) {
-	dbPool, err := sql.Open("sqlite", "db.sqlite?_journal_mode=WAL&_busy_timeout=5000")
+func NewDatabase(path string) (*sql.DB, error) {
+	// Transactions take the write lock when they begin, so two of them reading before
+	// writing wait on the busy timeout instead of failing with SQLITE_BUSY. SQLite
+	// only enforces foreign keys on connections that turn them on
+	dbPool, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)&_txlock=immediate")
 	if err != nil {
 		reason := fmt.Sprintf("error opening database: %v", err)
 		log.Fatalln(reason)
 		return nil, errors.New(reason)
 	}
 
+	log.Println("Migrating database")
+	if err := migrate(context.Background(), dbPool); err != nil {
+		reason := fmt.Sprintf("error migrating database: %v", err)
+		log.Fatalln(reason)
+		return nil, errors.New(reason)
+	}
+
 	log.Println("Initializing database")
 	if _, err := dbPool.ExecContext(context.Background(), schemaGenSql); err != nil {
 		reason := fmt.Sprintf("error initializing database: %v", err)
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"server/internal/usernames"
+)
+
+// A change to a table that already exists. The schema only creates what is
+// missing, so columns added to a table after it was first released need one
+type migration struct {
+	table  string
+	column string
+	// The column definition, as written after ALTER TABLE ... ADD COLUMN
+	definition string
+	// Fills in the new column for the rows that were already there
+	backfill func(ctx context.Context, tx *sql.Tx) error
+}
+
+// Applied in order, and never reordered or removed since the database's
+// user_version counts how many of them it has seen. Columns that can't be
+// added with constraints get them from the indexes in the schema
+var migrations = []migration{
+	{table: "users", column: "username_key", definition: "TEXT NOT NULL DEFAULT ''", backfill: backfillUsernameKeys},
+	{table: "users", column: "disabled_at", definition: "DATETIME"},
+	{table: "messages", column: "parent_id", definition: "INTEGER"},
+	{table: "rooms", column: "last_seq", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "messages", column: "client_id", definition: "TEXT"},
+	{table: "rooms", column: "slow_mode_seconds", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "attachments", column: "width", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "attachments", column: "height", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "rooms", column: "topic", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "messages", column: "action", definition: "BOOLEAN NOT NULL DEFAULT FALSE"},
+	{table: "rooms", column: "description", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "rooms", column: "visibility", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "room_members", column: "role", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "room_events", column: "message_id", definition: "INTEGER"},
+}
+
+// Brings the tables of an existing database up to date with the schema. It has
+// to run before the schema, whose indexes may be on the columns it adds
+func migrate(ctx context.Context, dbPool *sql.DB) error {
+	var version int
+	if err := dbPool.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version >= len(migrations) {
+		return nil
+	}
+
+	tx, err := dbPool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i := version; i < len(migrations); i++ {
+		m := migrations[i]
+		// Tables that don't exist yet are created whole by the schema, and
+		// databases created from a newer schema already have the column
+		missing, err := columnMissing(ctx, tx, m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if !missing {
+			continue
+		}
+
+		log.Printf("Adding column %s to table %s", m.column, m.table)
+		statement := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+		if m.backfill != nil {
+			if err := m.backfill(ctx, tx); err != nil {
+				return err
+			}
+		}
+	}
+
+	// PRAGMA doesn't take parameters
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", len(migrations))); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Whether the table exists without the column
+func columnMissing(ctx context.Context, tx *sql.Tx, table string, column string) (bool, error) {
+	var tableCount, columnCount int
+	err := tx.QueryRowContext(ctx,
+		"SELECT (SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?), (SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?)",
+		table, table, column,
+	).Scan(&tableCount, &columnCount)
+	return tableCount > 0 && columnCount == 0, err
+}
+
+// Users registered before usernames had keys get the key of their username.
+// Usernames that now fold to the same key keep working for the oldest user, the
+// others get a key nobody can type so they don't block the unique index, and
+// can't log in until their usernames are changed in the database
+func backfillUsernameKeys(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id, username FROM users ORDER BY created_at, id")
+	if err != nil {
+		return err
+	}
+	type user struct{ id, username string }
+	users := []user{}
+	for rows.Next() {
+		var u user
+		if err := rows.Scan(&u.id, &u.username); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(users))
+	for _, u := range users {
+		key := usernames.Key(u.username)
+		if taken[key] {
+			log.Printf("Username %s of user %s clashes with an older user's, it can't be used to log in", u.username, u.id)
+			key = key + "\x00" + u.id
+		}
+		taken[key] = true
+
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET username_key = ? WHERE id = ?", key, u.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"server/internal/usernames"
+)
+
+// The tables of the first release, before any migration
+const firstSchema = `
+CREATE TABLE users (
+  id TEXT PRIMARY KEY,
+  username TEXT UNIQUE NOT NULL,
+  password_hash TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE refresh_tokens (
+  jti TEXT PRIMARY KEY,
+  user_id TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expire_at DATETIME NOT NULL,
+  revoked_at DATETIME,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+`
+
+func openTestDatabase(t *testing.T, schema string) *sql.DB {
+	t.Helper()
+	dbPool, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbPool.Close() })
+	if _, err := dbPool.Exec(schema); err != nil {
+		t.Fatal(err)
+	}
+	return dbPool
+}
+
+func userVersion(t *testing.T, dbPool *sql.DB) int {
+	t.Helper()
+	var version int
+	if err := dbPool.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestMigrateBackfillsUsernameKeys(t *testing.T) {
+	dbPool := openTestDatabase(t, firstSchema)
+	users := []struct {
+		id        string
+		username  string
+		createdAt string
+		wantKey   string
+	}{
+		{"u1", "Alice", "2024-01-01 00:00:00", usernames.Key("alice")},
+		{"u2", "bob", "2024-01-02 00:00:00", usernames.Key("bob")},
+		// Folds to the same key as the older Alice
+		{"u3", "alice", "2024-01-03 00:00:00", usernames.Key("alice") + "\x00u3"},
+	}
+	for _, u := range users {
+		_, err := dbPool.Exec("INSERT INTO users (id, username, password_hash, created_at) VALUES (?, ?, '', ?)", u.id, u.username, u.createdAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := migrate(context.Background(), dbPool); err != nil {
+		t.Fatalf("migrate() = %v", err)
+	}
+	if version := userVersion(t, dbPool); version != len(migrations) {
+		t.Errorf("user_version = %d, want %d", version, len(migrations))
+	}
+
+	for _, u := range users {
+		var key string
+		if err := dbPool.QueryRow("SELECT username_key FROM users WHERE id = ?", u.id).Scan(&key); err != nil {
+			t.Fatal(err)
+		}
+		if key != u.wantKey {
+			t.Errorf("username_key of %s = %q, want %q", u.username, key, u.wantKey)
+		}
+	}
+	// The schema's unique index can be created on the backfilled keys
+	if _, err := dbPool.Exec(schemaGenSql); err != nil {
+		t.Errorf("applying the schema after migrating = %v", err)
+	}
+}
+
+func TestMigrateSteps(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		version int
+		// Whether users.disabled_at exists after migrating
+		wantDisabledAt bool
+	}{
+		{"new database", "", 0, false},
+		{"first release", firstSchema, 0, true},
+		{"partly migrated", firstSchema + "ALTER TABLE users ADD COLUMN username_key TEXT NOT NULL DEFAULT '';", 1, true},
+		// Columns are only added by the steps the database hasn't seen
+		{"already migrated", firstSchema, len(migrations), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dbPool := openTestDatabase(t, test.schema)
+			if _, err := dbPool.Exec(fmt.Sprintf("PRAGMA user_version = %d", test.version)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := migrate(context.Background(), dbPool); err != nil {
+				t.Fatalf("migrate() = %v", err)
+			}
+			if version := userVersion(t, dbPool); version != len(migrations) {
+				t.Errorf("user_version = %d, want %d", version, len(migrations))
+			}
+
+			var count int
+			err := dbPool.QueryRow("SELECT COUNT(*) FROM pragma_table_info('users') WHERE name = 'disabled_at'").Scan(&count)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hasDisabledAt := count > 0; hasDisabledAt != test.wantDisabledAt {
+				t.Errorf("users.disabled_at exists = %v, want %v", hasDisabledAt, test.wantDisabledAt)
+			}
+		})
+	}
+}
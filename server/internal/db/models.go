@@ -9,6 +9,120 @@ import (
 	"time"
 )
 
+type Attachment struct {
+	ID         string
+	UploaderID string
+	MessageID  sql.NullInt64
+	Filename   string
+	Mime       string
+	Size       int64
+	Width      int64
+	Height     int64
+	CreatedAt  time.Time
+}
+
+type AttachmentThumbnail struct {
+	AttachmentID string
+	Size         int64
+	Width        int64
+	Height       int64
+	Mime         string
+	Bytes        int64
+}
+
+type Conversation struct {
+	ID        int64
+	MemberKey string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ConversationMember struct {
+	ConversationID int64
+	UserID         string
+}
+
+type DirectMessage struct {
+	ID             int64
+	ConversationID int64
+	SenderID       string
+	Msg            string
+	CreatedAt      time.Time
+}
+
+type LinkPreview struct {
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
+	FetchedAt   time.Time
+}
+
+type Mention struct {
+	ID        int64
+	MessageID int64
+	UserID    string
+	CreatedAt time.Time
+	SeenAt    sql.NullTime
+}
+
+type Message struct {
+	ID             int64
+	RoomID         int64
+	ParentID       sql.NullInt64
+	SenderID       string
+	SenderUsername string
+	Msg            string
+	ClientID       sql.NullString
+	Action         bool
+	CreatedAt      time.Time
+	EditedAt       sql.NullTime
+	DeletedAt      sql.NullTime
+}
+
+type MessageEdit struct {
+	ID          int64
+	MessageID   int64
+	PreviousMsg string
+	EditedBy    string
+	EditedAt    time.Time
+}
+
+type MessageLinkPreview struct {
+	MessageID   int64
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
+	Position    int64
+}
+
+type MessageReaction struct {
+	MessageID int64
+	UserID    string
+	Emoji     string
+	CreatedAt time.Time
+}
+
+type Presence struct {
+	UserID     string
+	Status     int64
+	LastSeenAt sql.NullTime
+}
+
+type Profile struct {
+	UserID      string
+	DisplayName string
+	Bio         string
+	Status      string
+	Avatar      []byte
+	AvatarMime  string
+	Version     int64
+	UpdatedAt   time.Time
+}
+
 type RefreshToken struct {
 	Jti       string
 	UserID    string
@@ -17,9 +131,82 @@ type RefreshToken struct {
 	RevokedAt sql.NullTime
 }
 
+type Room struct {
+	ID              int64
+	OwnerID         string
+	Name            string
+	LastSeq         int64
+	SlowModeSeconds int64
+	Topic           string
+	Description     string
+	Visibility      int64
+	CreatedAt       time.Time
+}
+
+type RoomEvent struct {
+	RoomID    int64
+	Seq       int64
+	UserID    string
+	MessageID sql.NullInt64
+	Packet    []byte
+	CreatedAt time.Time
+}
+
+type RoomInvite struct {
+	RoomID    int64
+	UserID    string
+	InviterID string
+	CreatedAt time.Time
+}
+
+type RoomInviteCode struct {
+	Code      string
+	RoomID    int64
+	CreatedBy string
+	Role      int64
+	MaxUses   int64
+	Uses      int64
+	ExpiresAt sql.NullTime
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+}
+
+type RoomInviteCodeUse struct {
+	ID     int64
+	Code   string
+	RoomID int64
+	UserID string
+	Role   int64
+	UsedAt time.Time
+}
+
+type RoomMember struct {
+	RoomID    int64
+	UserID    string
+	AddedBy   string
+	Role      int64
+	CreatedAt time.Time
+}
+
+type RoomMute struct {
+	RoomID     int64
+	UserID     string
+	MutedBy    string
+	MutedUntil time.Time
+}
+
+type RoomRead struct {
+	UserID     string
+	RoomID     int64
+	LastReadID int64
+	UpdatedAt  time.Time
+}
+
 type User struct {
 	ID           string
 	Username     string
+	UsernameKey  string
 	PasswordHash string
 	CreatedAt    time.Time
+	DisabledAt   sql.NullTime
 }
@@ -7,36 +7,614 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
+const addConversationMember = `-- name: AddConversationMember :exec
+INSERT INTO conversation_members (
+  conversation_id, user_id
+) VALUES (
+  ?, ?
+)
+`
+
+type AddConversationMemberParams struct {
+	ConversationID int64
+	UserID         string
+}
+
+func (q *Queries) AddConversationMember(ctx context.Context, arg AddConversationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, addConversationMember, arg.ConversationID, arg.UserID)
+	return err
+}
+
+const addReaction = `-- name: AddReaction :exec
+INSERT INTO message_reactions (
+  message_id, user_id, emoji
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT DO NOTHING
+`
+
+type AddReactionParams struct {
+	MessageID int64
+	UserID    string
+	Emoji     string
+}
+
+func (q *Queries) AddReaction(ctx context.Context, arg AddReactionParams) error {
+	_, err := q.db.ExecContext(ctx, addReaction, arg.MessageID, arg.UserID, arg.Emoji)
+	return err
+}
+
+const addRoomMember = `-- name: AddRoomMember :exec
+INSERT INTO room_members (
+  room_id, user_id, added_by
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO NOTHING
+`
+
+type AddRoomMemberParams struct {
+	RoomID  int64
+	UserID  string
+	AddedBy string
+}
+
+func (q *Queries) AddRoomMember(ctx context.Context, arg AddRoomMemberParams) error {
+	_, err := q.db.ExecContext(ctx, addRoomMember, arg.RoomID, arg.UserID, arg.AddedBy)
+	return err
+}
+
+const anonymizeAttachmentsByUploader = `-- name: AnonymizeAttachmentsByUploader :exec
+UPDATE attachments
+SET uploader_id = ''
+WHERE uploader_id = ?
+`
+
+func (q *Queries) AnonymizeAttachmentsByUploader(ctx context.Context, uploaderID string) error {
+	_, err := q.db.ExecContext(ctx, anonymizeAttachmentsByUploader, uploaderID)
+	return err
+}
+
+const anonymizeDirectMessagesBySender = `-- name: AnonymizeDirectMessagesBySender :exec
+UPDATE direct_messages
+SET sender_id = ''
+WHERE sender_id = ?
+`
+
+func (q *Queries) AnonymizeDirectMessagesBySender(ctx context.Context, senderID string) error {
+	_, err := q.db.ExecContext(ctx, anonymizeDirectMessagesBySender, senderID)
+	return err
+}
+
+const anonymizeMessagesBySender = `-- name: AnonymizeMessagesBySender :exec
+UPDATE messages
+SET sender_id = '',
+  sender_username = ?,
+  client_id = NULL
+WHERE sender_id = ?
+`
+
+type AnonymizeMessagesBySenderParams struct {
+	SenderUsername string
+	SenderID       string
+}
+
+// The client ids go too, anonymous messages can't be resent
+func (q *Queries) AnonymizeMessagesBySender(ctx context.Context, arg AnonymizeMessagesBySenderParams) error {
+	_, err := q.db.ExecContext(ctx, anonymizeMessagesBySender, arg.SenderUsername, arg.SenderID)
+	return err
+}
+
+const bindAttachment = `-- name: BindAttachment :one
+UPDATE attachments
+SET message_id = ?
+WHERE id = ?
+  AND uploader_id = ?
+  AND message_id IS NULL
+RETURNING id, uploader_id, message_id, filename, mime, size, width, height, created_at
+`
+
+type BindAttachmentParams struct {
+	MessageID  sql.NullInt64
+	ID         string
+	UploaderID string
+}
+
+func (q *Queries) BindAttachment(ctx context.Context, arg BindAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, bindAttachment, arg.MessageID, arg.ID, arg.UploaderID)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.UploaderID,
+		&i.MessageID,
+		&i.Filename,
+		&i.Mime,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const countReactionEmojis = `-- name: CountReactionEmojis :one
+SELECT COUNT(DISTINCT emoji)
+FROM message_reactions
+WHERE message_id = ?
+`
+
+func (q *Queries) CountReactionEmojis(ctx context.Context, messageID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countReactionEmojis, messageID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countThreadReplies = `-- name: CountThreadReplies :one
+SELECT COUNT(*)
+FROM messages
+WHERE parent_id = ?
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) CountThreadReplies(ctx context.Context, parentID sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countThreadReplies, parentID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAttachment = `-- name: CreateAttachment :one
+INSERT INTO attachments (
+  id, uploader_id, filename, mime, size, width, height, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id, uploader_id, message_id, filename, mime, size, width, height, created_at
+`
+
+type CreateAttachmentParams struct {
+	ID         string
+	UploaderID string
+	Filename   string
+	Mime       string
+	Size       int64
+	Width      int64
+	Height     int64
+	CreatedAt  time.Time
+}
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, createAttachment,
+		arg.ID,
+		arg.UploaderID,
+		arg.Filename,
+		arg.Mime,
+		arg.Size,
+		arg.Width,
+		arg.Height,
+		arg.CreatedAt,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.UploaderID,
+		&i.MessageID,
+		&i.Filename,
+		&i.Mime,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createAttachmentThumbnail = `-- name: CreateAttachmentThumbnail :exec
+INSERT INTO attachment_thumbnails (
+  attachment_id, size, width, height, mime, bytes
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreateAttachmentThumbnailParams struct {
+	AttachmentID string
+	Size         int64
+	Width        int64
+	Height       int64
+	Mime         string
+	Bytes        int64
+}
+
+func (q *Queries) CreateAttachmentThumbnail(ctx context.Context, arg CreateAttachmentThumbnailParams) error {
+	_, err := q.db.ExecContext(ctx, createAttachmentThumbnail,
+		arg.AttachmentID,
+		arg.Size,
+		arg.Width,
+		arg.Height,
+		arg.Mime,
+		arg.Bytes,
+	)
+	return err
+}
+
+const createConversation = `-- name: CreateConversation :one
+INSERT INTO conversations (
+  member_key
+) VALUES (
+  ?
+)
+RETURNING id, member_key, created_at, updated_at
+`
+
+func (q *Queries) CreateConversation(ctx context.Context, memberKey string) (Conversation, error) {
+	row := q.db.QueryRowContext(ctx, createConversation, memberKey)
+	var i Conversation
+	err := row.Scan(
+		&i.ID,
+		&i.MemberKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createDirectMessage = `-- name: CreateDirectMessage :one
+INSERT INTO direct_messages (
+  conversation_id, sender_id, msg
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, conversation_id, sender_id, msg, created_at
+`
+
+type CreateDirectMessageParams struct {
+	ConversationID int64
+	SenderID       string
+	Msg            string
+}
+
+func (q *Queries) CreateDirectMessage(ctx context.Context, arg CreateDirectMessageParams) (DirectMessage, error) {
+	row := q.db.QueryRowContext(ctx, createDirectMessage, arg.ConversationID, arg.SenderID, arg.Msg)
+	var i DirectMessage
+	err := row.Scan(
+		&i.ID,
+		&i.ConversationID,
+		&i.SenderID,
+		&i.Msg,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createInviteCode = `-- name: CreateInviteCode :one
+INSERT INTO room_invite_codes (
+  code, room_id, created_by, role, max_uses, expires_at, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING code, room_id, created_by, role, max_uses, uses, expires_at, revoked_at, created_at
+`
+
+type CreateInviteCodeParams struct {
+	Code      string
+	RoomID    int64
+	CreatedBy string
+	Role      int64
+	MaxUses   int64
+	ExpiresAt sql.NullTime
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateInviteCode(ctx context.Context, arg CreateInviteCodeParams) (RoomInviteCode, error) {
+	row := q.db.QueryRowContext(ctx, createInviteCode,
+		arg.Code,
+		arg.RoomID,
+		arg.CreatedBy,
+		arg.Role,
+		arg.MaxUses,
+		arg.ExpiresAt,
+		arg.CreatedAt,
+	)
+	var i RoomInviteCode
+	err := row.Scan(
+		&i.Code,
+		&i.RoomID,
+		&i.CreatedBy,
+		&i.Role,
+		&i.MaxUses,
+		&i.Uses,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createInviteCodeUse = `-- name: CreateInviteCodeUse :exec
+INSERT INTO room_invite_code_uses (
+  code, room_id, user_id, role, used_at
+) VALUES (
+  ?, ?, ?, ?, ?
+)
+`
+
+type CreateInviteCodeUseParams struct {
+	Code   string
+	RoomID int64
+	UserID string
+	Role   int64
+	UsedAt time.Time
+}
+
+func (q *Queries) CreateInviteCodeUse(ctx context.Context, arg CreateInviteCodeUseParams) error {
+	_, err := q.db.ExecContext(ctx, createInviteCodeUse,
+		arg.Code,
+		arg.RoomID,
+		arg.UserID,
+		arg.Role,
+		arg.UsedAt,
+	)
+	return err
+}
+
+const createMention = `-- name: CreateMention :one
+INSERT INTO mentions (
+  message_id, user_id
+) VALUES (
+  ?, ?
+)
+RETURNING id, message_id, user_id, created_at, seen_at
+`
+
+type CreateMentionParams struct {
+	MessageID int64
+	UserID    string
+}
+
+func (q *Queries) CreateMention(ctx context.Context, arg CreateMentionParams) (Mention, error) {
+	row := q.db.QueryRowContext(ctx, createMention, arg.MessageID, arg.UserID)
+	var i Mention
+	err := row.Scan(
+		&i.ID,
+		&i.MessageID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.SeenAt,
+	)
+	return i, err
+}
+
+const createMessage = `-- name: CreateMessage :one
+INSERT INTO messages (
+  room_id, parent_id, sender_id, sender_username, msg, client_id, action, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+`
+
+type CreateMessageParams struct {
+	RoomID         int64
+	ParentID       sql.NullInt64
+	SenderID       string
+	SenderUsername string
+	Msg            string
+	ClientID       sql.NullString
+	Action         bool
+	CreatedAt      time.Time
+}
+
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
+	row := q.db.QueryRowContext(ctx, createMessage,
+		arg.RoomID,
+		arg.ParentID,
+		arg.SenderID,
+		arg.SenderUsername,
+		arg.Msg,
+		arg.ClientID,
+		arg.Action,
+		arg.CreatedAt,
+	)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.ParentID,
+		&i.SenderID,
+		&i.SenderUsername,
+		&i.Msg,
+		&i.ClientID,
+		&i.Action,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const createMessageEdit = `-- name: CreateMessageEdit :exec
+INSERT INTO message_edits (
+  message_id, previous_msg, edited_by
+) VALUES (
+  ?, ?, ?
+)
+`
+
+type CreateMessageEditParams struct {
+	MessageID   int64
+	PreviousMsg string
+	EditedBy    string
+}
+
+func (q *Queries) CreateMessageEdit(ctx context.Context, arg CreateMessageEditParams) error {
+	_, err := q.db.ExecContext(ctx, createMessageEdit, arg.MessageID, arg.PreviousMsg, arg.EditedBy)
+	return err
+}
+
+const createMessageLinkPreview = `-- name: CreateMessageLinkPreview :exec
+INSERT INTO message_link_previews (
+  message_id, url, title, description, image_url, site_name, position
+) VALUES (
+  ?, ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreateMessageLinkPreviewParams struct {
+	MessageID   int64
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
+	Position    int64
+}
+
+func (q *Queries) CreateMessageLinkPreview(ctx context.Context, arg CreateMessageLinkPreviewParams) error {
+	_, err := q.db.ExecContext(ctx, createMessageLinkPreview,
+		arg.MessageID,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.SiteName,
+		arg.Position,
+	)
+	return err
+}
+
+const createRoom = `-- name: CreateRoom :one
+INSERT INTO rooms (
+  owner_id, name, visibility
+) VALUES (
+  ?, ?, ?
+)
+RETURNING id, owner_id, name, last_seq, slow_mode_seconds, topic, description, visibility, created_at
+`
+
+type CreateRoomParams struct {
+	OwnerID    string
+	Name       string
+	Visibility int64
+}
+
+func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, error) {
+	row := q.db.QueryRowContext(ctx, createRoom, arg.OwnerID, arg.Name, arg.Visibility)
+	var i Room
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Name,
+		&i.LastSeq,
+		&i.SlowModeSeconds,
+		&i.Topic,
+		&i.Description,
+		&i.Visibility,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRoomEvent = `-- name: CreateRoomEvent :exec
+INSERT INTO room_events (
+  room_id, seq, user_id, message_id, packet, created_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+)
+`
+
+type CreateRoomEventParams struct {
+	RoomID    int64
+	Seq       int64
+	UserID    string
+	MessageID sql.NullInt64
+	Packet    []byte
+	CreatedAt time.Time
+}
+
+func (q *Queries) CreateRoomEvent(ctx context.Context, arg CreateRoomEventParams) error {
+	_, err := q.db.ExecContext(ctx, createRoomEvent,
+		arg.RoomID,
+		arg.Seq,
+		arg.UserID,
+		arg.MessageID,
+		arg.Packet,
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-  id, username, password_hash
+  id, username, username_key, password_hash
 ) VALUES (
-  ?, ?, ?
+  ?, ?, ?, ?
 )
-RETURNING id, username, password_hash, created_at
+RETURNING id, username, username_key, password_hash, created_at, disabled_at
 `
 
 type CreateUserParams struct {
 	ID           string
 	Username     string
+	UsernameKey  string
 	PasswordHash string
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.ID, arg.Username, arg.PasswordHash)
+	row := q.db.QueryRowContext(ctx, createUser,
+		arg.ID,
+		arg.Username,
+		arg.UsernameKey,
+		arg.PasswordHash,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.Username,
+		&i.UsernameKey,
 		&i.PasswordHash,
 		&i.CreatedAt,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
+const deleteAttachment = `-- name: DeleteAttachment :exec
+DELETE FROM attachments
+WHERE id = ?
+`
+
+func (q *Queries) DeleteAttachment(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteAttachment, id)
+	return err
+}
+
+const deleteAttachmentThumbnails = `-- name: DeleteAttachmentThumbnails :exec
+DELETE FROM attachment_thumbnails
+WHERE attachment_id = ?
+`
+
+func (q *Queries) DeleteAttachmentThumbnails(ctx context.Context, attachmentID string) error {
+	_, err := q.db.ExecContext(ctx, deleteAttachmentThumbnails, attachmentID)
+	return err
+}
+
+const deleteDirectMessagesBySender = `-- name: DeleteDirectMessagesBySender :exec
+DELETE FROM direct_messages
+WHERE sender_id = ?
+`
+
+func (q *Queries) DeleteDirectMessagesBySender(ctx context.Context, senderID string) error {
+	_, err := q.db.ExecContext(ctx, deleteDirectMessagesBySender, senderID)
+	return err
+}
+
 const deleteExpiredOrRevokedTokens = `-- name: DeleteExpiredOrRevokedTokens :execrows
 DELETE FROM refresh_tokens
 WHERE expire_at <= CURRENT_TIMESTAMP
@@ -51,82 +629,2427 @@ func (q *Queries) DeleteExpiredOrRevokedTokens(ctx context.Context) (int64, erro
 	return result.RowsAffected()
 }
 
-const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, password_hash, created_at
-FROM users
-WHERE LOWER(username) = LOWER(?)
-LIMIT 1
+const deleteExpiredRoomMutes = `-- name: DeleteExpiredRoomMutes :execrows
+DELETE FROM room_mutes
+WHERE muted_until <= ?
 `
 
-func (q *Queries) GetUserByUsername(ctx context.Context, lower string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByUsername, lower)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Username,
-		&i.PasswordHash,
-		&i.CreatedAt,
-	)
-	return i, err
+func (q *Queries) DeleteExpiredRoomMutes(ctx context.Context, mutedUntil time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredRoomMutes, mutedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const getUsernameById = `-- name: GetUsernameById :one
-SELECT username
-FROM users
-WHERE id = ?
-LIMIT 1
+const deleteInviteCodeUsesForUser = `-- name: DeleteInviteCodeUsesForUser :exec
+DELETE FROM room_invite_code_uses
+WHERE user_id = ?1
+  OR code IN (SELECT c.code FROM room_invite_codes c WHERE c.created_by = ?1)
 `
 
-func (q *Queries) GetUsernameById(ctx context.Context, id string) (string, error) {
-	row := q.db.QueryRowContext(ctx, getUsernameById, id)
-	var username string
-	err := row.Scan(&username)
-	return username, err
+func (q *Queries) DeleteInviteCodeUsesForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteInviteCodeUsesForUser, userID)
+	return err
 }
 
-const isRefreshTokenValid = `-- name: IsRefreshTokenValid :one
-SELECT 1
+const deleteInviteCodesByCreator = `-- name: DeleteInviteCodesByCreator :exec
+DELETE FROM room_invite_codes
+WHERE created_by = ?
+`
+
+func (q *Queries) DeleteInviteCodesByCreator(ctx context.Context, createdBy string) error {
+	_, err := q.db.ExecContext(ctx, deleteInviteCodesByCreator, createdBy)
+	return err
+}
+
+const deleteLinkPreviewsBySender = `-- name: DeleteLinkPreviewsBySender :exec
+DELETE FROM message_link_previews
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?)
+`
+
+func (q *Queries) DeleteLinkPreviewsBySender(ctx context.Context, senderID string) error {
+	_, err := q.db.ExecContext(ctx, deleteLinkPreviewsBySender, senderID)
+	return err
+}
+
+const deleteMentionsForUser = `-- name: DeleteMentionsForUser :exec
+DELETE FROM mentions
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteMentionsForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteMentionsForUser, userID)
+	return err
+}
+
+const deleteMentionsOnMessagesBySender = `-- name: DeleteMentionsOnMessagesBySender :exec
+DELETE FROM mentions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?)
+`
+
+func (q *Queries) DeleteMentionsOnMessagesBySender(ctx context.Context, senderID string) error {
+	_, err := q.db.ExecContext(ctx, deleteMentionsOnMessagesBySender, senderID)
+	return err
+}
+
+const deleteMessage = `-- name: DeleteMessage :exec
+UPDATE messages
+SET msg = '',
+  deleted_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) DeleteMessage(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMessage, id)
+	return err
+}
+
+const deleteMessageEdits = `-- name: DeleteMessageEdits :exec
+DELETE FROM message_edits
+WHERE message_id = ?
+`
+
+func (q *Queries) DeleteMessageEdits(ctx context.Context, messageID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMessageEdits, messageID)
+	return err
+}
+
+const deleteMessageEditsBySender = `-- name: DeleteMessageEditsBySender :exec
+DELETE FROM message_edits
+WHERE edited_by = ?
+  OR message_id IN (SELECT id FROM messages WHERE sender_id = ?)
+`
+
+type DeleteMessageEditsBySenderParams struct {
+	EditedBy string
+	SenderID string
+}
+
+func (q *Queries) DeleteMessageEditsBySender(ctx context.Context, arg DeleteMessageEditsBySenderParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMessageEditsBySender, arg.EditedBy, arg.SenderID)
+	return err
+}
+
+const deleteMessageLinkPreviews = `-- name: DeleteMessageLinkPreviews :exec
+DELETE FROM message_link_previews
+WHERE message_id = ?
+`
+
+func (q *Queries) DeleteMessageLinkPreviews(ctx context.Context, messageID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMessageLinkPreviews, messageID)
+	return err
+}
+
+const deleteMessageMentions = `-- name: DeleteMessageMentions :exec
+DELETE FROM mentions
+WHERE message_id = ?
+`
+
+func (q *Queries) DeleteMessageMentions(ctx context.Context, messageID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMessageMentions, messageID)
+	return err
+}
+
+const deleteMessageReactions = `-- name: DeleteMessageReactions :exec
+DELETE FROM message_reactions
+WHERE message_id = ?
+`
+
+func (q *Queries) DeleteMessageReactions(ctx context.Context, messageID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteMessageReactions, messageID)
+	return err
+}
+
+const deleteMessagesBySender = `-- name: DeleteMessagesBySender :exec
+UPDATE messages
+SET sender_id = '',
+  sender_username = ?,
+  msg = '',
+  client_id = NULL,
+  action = FALSE,
+  deleted_at = CURRENT_TIMESTAMP
+WHERE sender_id = ?
+`
+
+type DeleteMessagesBySenderParams struct {
+	SenderUsername string
+	SenderID       string
+}
+
+// Rows are kept so message ids never get reused, with nothing left of the sender
+func (q *Queries) DeleteMessagesBySender(ctx context.Context, arg DeleteMessagesBySenderParams) error {
+	_, err := q.db.ExecContext(ctx, deleteMessagesBySender, arg.SenderUsername, arg.SenderID)
+	return err
+}
+
+const deleteOldLinkPreviews = `-- name: DeleteOldLinkPreviews :execrows
+DELETE FROM link_previews
+WHERE fetched_at < ?
+`
+
+func (q *Queries) DeleteOldLinkPreviews(ctx context.Context, fetchedAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldLinkPreviews, fetchedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOldRoomEvents = `-- name: DeleteOldRoomEvents :execrows
+DELETE FROM room_events
+WHERE created_at < ?
+`
+
+func (q *Queries) DeleteOldRoomEvents(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldRoomEvents, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePresence = `-- name: DeletePresence :exec
+DELETE FROM presence
+WHERE user_id = ?
+`
+
+func (q *Queries) DeletePresence(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deletePresence, userID)
+	return err
+}
+
+const deleteProfile = `-- name: DeleteProfile :exec
+DELETE FROM profiles
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteProfile(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteProfile, userID)
+	return err
+}
+
+const deleteReactionsByUser = `-- name: DeleteReactionsByUser :exec
+DELETE FROM message_reactions
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteReactionsByUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteReactionsByUser, userID)
+	return err
+}
+
+const deleteReactionsOnMessagesBySender = `-- name: DeleteReactionsOnMessagesBySender :exec
+DELETE FROM message_reactions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?)
+`
+
+func (q *Queries) DeleteReactionsOnMessagesBySender(ctx context.Context, senderID string) error {
+	_, err := q.db.ExecContext(ctx, deleteReactionsOnMessagesBySender, senderID)
+	return err
+}
+
+const deleteRoom = `-- name: DeleteRoom :execrows
+DELETE FROM rooms
+WHERE id = ?
+`
+
+func (q *Queries) DeleteRoom(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoom, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRoomEvents = `-- name: DeleteRoomEvents :exec
+DELETE FROM room_events
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomEvents(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomEvents, roomID)
+	return err
+}
+
+const deleteRoomEventsByUser = `-- name: DeleteRoomEventsByUser :exec
+DELETE FROM room_events
+WHERE user_id = ?1
+  OR message_id IN (SELECT m.id FROM messages m WHERE m.sender_id = ?1)
+`
+
+// Both the events the user caused and the ones about their messages
+func (q *Queries) DeleteRoomEventsByUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomEventsByUser, userID)
+	return err
+}
+
+const deleteRoomInvite = `-- name: DeleteRoomInvite :exec
+DELETE FROM room_invites
+WHERE room_id = ?
+  AND user_id = ?
+`
+
+type DeleteRoomInviteParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) DeleteRoomInvite(ctx context.Context, arg DeleteRoomInviteParams) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomInvite, arg.RoomID, arg.UserID)
+	return err
+}
+
+const deleteRoomInviteCodeUses = `-- name: DeleteRoomInviteCodeUses :exec
+DELETE FROM room_invite_code_uses
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomInviteCodeUses(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomInviteCodeUses, roomID)
+	return err
+}
+
+const deleteRoomInviteCodes = `-- name: DeleteRoomInviteCodes :exec
+DELETE FROM room_invite_codes
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomInviteCodes(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomInviteCodes, roomID)
+	return err
+}
+
+const deleteRoomInvites = `-- name: DeleteRoomInvites :exec
+DELETE FROM room_invites
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomInvites(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomInvites, roomID)
+	return err
+}
+
+const deleteRoomInvitesForUser = `-- name: DeleteRoomInvitesForUser :exec
+DELETE FROM room_invites
+WHERE user_id = ?1
+  OR inviter_id = ?1
+`
+
+func (q *Queries) DeleteRoomInvitesForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomInvitesForUser, userID)
+	return err
+}
+
+const deleteRoomLinkPreviews = `-- name: DeleteRoomLinkPreviews :exec
+DELETE FROM message_link_previews
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?)
+`
+
+func (q *Queries) DeleteRoomLinkPreviews(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomLinkPreviews, roomID)
+	return err
+}
+
+const deleteRoomMember = `-- name: DeleteRoomMember :execrows
+DELETE FROM room_members
+WHERE room_id = ?
+  AND user_id = ?
+`
+
+type DeleteRoomMemberParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) DeleteRoomMember(ctx context.Context, arg DeleteRoomMemberParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoomMember, arg.RoomID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRoomMembers = `-- name: DeleteRoomMembers :exec
+DELETE FROM room_members
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomMembers(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMembers, roomID)
+	return err
+}
+
+const deleteRoomMembershipsForUser = `-- name: DeleteRoomMembershipsForUser :exec
+DELETE FROM room_members
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRoomMembershipsForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMembershipsForUser, userID)
+	return err
+}
+
+const deleteRoomMentions = `-- name: DeleteRoomMentions :exec
+DELETE FROM mentions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?)
+`
+
+func (q *Queries) DeleteRoomMentions(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMentions, roomID)
+	return err
+}
+
+const deleteRoomMessageEdits = `-- name: DeleteRoomMessageEdits :exec
+DELETE FROM message_edits
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?)
+`
+
+func (q *Queries) DeleteRoomMessageEdits(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMessageEdits, roomID)
+	return err
+}
+
+const deleteRoomMessages = `-- name: DeleteRoomMessages :exec
+DELETE FROM messages
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomMessages(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMessages, roomID)
+	return err
+}
+
+const deleteRoomMute = `-- name: DeleteRoomMute :execrows
+DELETE FROM room_mutes
+WHERE room_id = ?
+  AND user_id = ?
+`
+
+type DeleteRoomMuteParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) DeleteRoomMute(ctx context.Context, arg DeleteRoomMuteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteRoomMute, arg.RoomID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteRoomMutes = `-- name: DeleteRoomMutes :exec
+DELETE FROM room_mutes
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomMutes(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMutes, roomID)
+	return err
+}
+
+const deleteRoomMutesForUser = `-- name: DeleteRoomMutesForUser :exec
+DELETE FROM room_mutes
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRoomMutesForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomMutesForUser, userID)
+	return err
+}
+
+const deleteRoomReactions = `-- name: DeleteRoomReactions :exec
+DELETE FROM message_reactions
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?)
+`
+
+func (q *Queries) DeleteRoomReactions(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomReactions, roomID)
+	return err
+}
+
+const deleteRoomReads = `-- name: DeleteRoomReads :exec
+DELETE FROM room_reads
+WHERE room_id = ?
+`
+
+func (q *Queries) DeleteRoomReads(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomReads, roomID)
+	return err
+}
+
+const deleteRoomReadsForUser = `-- name: DeleteRoomReadsForUser :exec
+DELETE FROM room_reads
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteRoomReadsForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteRoomReadsForUser, userID)
+	return err
+}
+
+const deleteTokensForUser = `-- name: DeleteTokensForUser :exec
+DELETE FROM refresh_tokens
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteTokensForUser(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, deleteTokensForUser, userID)
+	return err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id string) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const detachRoomAttachments = `-- name: DetachRoomAttachments :exec
+UPDATE attachments
+SET message_id = NULL
+WHERE message_id IN (SELECT m.id FROM messages m WHERE m.room_id = ?)
+`
+
+// Their files are removed with the other orphans, uploaded long before
+func (q *Queries) DetachRoomAttachments(ctx context.Context, roomID int64) error {
+	_, err := q.db.ExecContext(ctx, detachRoomAttachments, roomID)
+	return err
+}
+
+const getAttachment = `-- name: GetAttachment :one
+SELECT id, uploader_id, message_id, filename, mime, size, width, height, created_at
+FROM attachments
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetAttachment(ctx context.Context, id string) (Attachment, error) {
+	row := q.db.QueryRowContext(ctx, getAttachment, id)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.UploaderID,
+		&i.MessageID,
+		&i.Filename,
+		&i.Mime,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAttachmentThumbnail = `-- name: GetAttachmentThumbnail :one
+SELECT attachment_id, size, width, height, mime, bytes
+FROM attachment_thumbnails
+WHERE attachment_id = ?
+  AND size = ?
+LIMIT 1
+`
+
+type GetAttachmentThumbnailParams struct {
+	AttachmentID string
+	Size         int64
+}
+
+func (q *Queries) GetAttachmentThumbnail(ctx context.Context, arg GetAttachmentThumbnailParams) (AttachmentThumbnail, error) {
+	row := q.db.QueryRowContext(ctx, getAttachmentThumbnail, arg.AttachmentID, arg.Size)
+	var i AttachmentThumbnail
+	err := row.Scan(
+		&i.AttachmentID,
+		&i.Size,
+		&i.Width,
+		&i.Height,
+		&i.Mime,
+		&i.Bytes,
+	)
+	return i, err
+}
+
+const getConversationByMemberKey = `-- name: GetConversationByMemberKey :one
+SELECT id, member_key, created_at, updated_at
+FROM conversations
+WHERE member_key = ?
+LIMIT 1
+`
+
+func (q *Queries) GetConversationByMemberKey(ctx context.Context, memberKey string) (Conversation, error) {
+	row := q.db.QueryRowContext(ctx, getConversationByMemberKey, memberKey)
+	var i Conversation
+	err := row.Scan(
+		&i.ID,
+		&i.MemberKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getInviteCode = `-- name: GetInviteCode :one
+SELECT code, room_id, created_by, role, max_uses, uses, expires_at, revoked_at, created_at
+FROM room_invite_codes
+WHERE code = ?
+`
+
+func (q *Queries) GetInviteCode(ctx context.Context, code string) (RoomInviteCode, error) {
+	row := q.db.QueryRowContext(ctx, getInviteCode, code)
+	var i RoomInviteCode
+	err := row.Scan(
+		&i.Code,
+		&i.RoomID,
+		&i.CreatedBy,
+		&i.Role,
+		&i.MaxUses,
+		&i.Uses,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLinkPreview = `-- name: GetLinkPreview :one
+SELECT url, title, description, image_url, site_name, fetched_at
+FROM link_previews
+WHERE url = ?
+LIMIT 1
+`
+
+func (q *Queries) GetLinkPreview(ctx context.Context, url string) (LinkPreview, error) {
+	row := q.db.QueryRowContext(ctx, getLinkPreview, url)
+	var i LinkPreview
+	err := row.Scan(
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.ImageUrl,
+		&i.SiteName,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const getMessage = `-- name: GetMessage :one
+SELECT id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+FROM messages
+WHERE id = ?
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetMessage(ctx context.Context, id int64) (Message, error) {
+	row := q.db.QueryRowContext(ctx, getMessage, id)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.ParentID,
+		&i.SenderID,
+		&i.SenderUsername,
+		&i.Msg,
+		&i.ClientID,
+		&i.Action,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getMessageByClientId = `-- name: GetMessageByClientId :one
+SELECT id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+FROM messages
+WHERE sender_id = ?
+  AND client_id = ?
+LIMIT 1
+`
+
+type GetMessageByClientIdParams struct {
+	SenderID string
+	ClientID sql.NullString
+}
+
+func (q *Queries) GetMessageByClientId(ctx context.Context, arg GetMessageByClientIdParams) (Message, error) {
+	row := q.db.QueryRowContext(ctx, getMessageByClientId, arg.SenderID, arg.ClientID)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.ParentID,
+		&i.SenderID,
+		&i.SenderUsername,
+		&i.Msg,
+		&i.ClientID,
+		&i.Action,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getMessageRoomId = `-- name: GetMessageRoomId :one
+SELECT room_id
+FROM messages
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetMessageRoomId(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getMessageRoomId, id)
+	var room_id int64
+	err := row.Scan(&room_id)
+	return room_id, err
+}
+
+const getPresence = `-- name: GetPresence :one
+SELECT user_id, status, last_seen_at
+FROM presence
+WHERE user_id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetPresence(ctx context.Context, userID string) (Presence, error) {
+	row := q.db.QueryRowContext(ctx, getPresence, userID)
+	var i Presence
+	err := row.Scan(&i.UserID, &i.Status, &i.LastSeenAt)
+	return i, err
+}
+
+const getProfile = `-- name: GetProfile :one
+SELECT user_id, display_name, bio, status, avatar_mime, version, updated_at
+FROM profiles
+WHERE user_id = ?
+LIMIT 1
+`
+
+type GetProfileRow struct {
+	UserID      string
+	DisplayName string
+	Bio         string
+	Status      string
+	AvatarMime  string
+	Version     int64
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) GetProfile(ctx context.Context, userID string) (GetProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, getProfile, userID)
+	var i GetProfileRow
+	err := row.Scan(
+		&i.UserID,
+		&i.DisplayName,
+		&i.Bio,
+		&i.Status,
+		&i.AvatarMime,
+		&i.Version,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getProfileAvatar = `-- name: GetProfileAvatar :one
+SELECT avatar, avatar_mime
+FROM profiles
+WHERE user_id = ?
+  AND avatar IS NOT NULL
+LIMIT 1
+`
+
+type GetProfileAvatarRow struct {
+	Avatar     []byte
+	AvatarMime string
+}
+
+func (q *Queries) GetProfileAvatar(ctx context.Context, userID string) (GetProfileAvatarRow, error) {
+	row := q.db.QueryRowContext(ctx, getProfileAvatar, userID)
+	var i GetProfileAvatarRow
+	err := row.Scan(&i.Avatar, &i.AvatarMime)
+	return i, err
+}
+
+const getRoomInvite = `-- name: GetRoomInvite :one
+SELECT room_id, user_id, inviter_id, created_at
+FROM room_invites
+WHERE room_id = ?
+  AND user_id = ?
+`
+
+type GetRoomInviteParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) GetRoomInvite(ctx context.Context, arg GetRoomInviteParams) (RoomInvite, error) {
+	row := q.db.QueryRowContext(ctx, getRoomInvite, arg.RoomID, arg.UserID)
+	var i RoomInvite
+	err := row.Scan(
+		&i.RoomID,
+		&i.UserID,
+		&i.InviterID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRoomLastSeq = `-- name: GetRoomLastSeq :one
+SELECT last_seq
+FROM rooms
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetRoomLastSeq(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getRoomLastSeq, id)
+	var last_seq int64
+	err := row.Scan(&last_seq)
+	return last_seq, err
+}
+
+const getRoomMember = `-- name: GetRoomMember :one
+SELECT room_id, user_id, added_by, role, created_at
+FROM room_members
+WHERE room_id = ?
+  AND user_id = ?
+`
+
+type GetRoomMemberParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) GetRoomMember(ctx context.Context, arg GetRoomMemberParams) (RoomMember, error) {
+	row := q.db.QueryRowContext(ctx, getRoomMember, arg.RoomID, arg.UserID)
+	var i RoomMember
+	err := row.Scan(
+		&i.RoomID,
+		&i.UserID,
+		&i.AddedBy,
+		&i.Role,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRoomMute = `-- name: GetRoomMute :one
+SELECT room_id, user_id, muted_by, muted_until
+FROM room_mutes
+WHERE room_id = ?
+  AND user_id = ?
+  AND muted_until > ?
+LIMIT 1
+`
+
+type GetRoomMuteParams struct {
+	RoomID     int64
+	UserID     string
+	MutedUntil time.Time
+}
+
+func (q *Queries) GetRoomMute(ctx context.Context, arg GetRoomMuteParams) (RoomMute, error) {
+	row := q.db.QueryRowContext(ctx, getRoomMute, arg.RoomID, arg.UserID, arg.MutedUntil)
+	var i RoomMute
+	err := row.Scan(
+		&i.RoomID,
+		&i.UserID,
+		&i.MutedBy,
+		&i.MutedUntil,
+	)
+	return i, err
+}
+
+const getRoomSuccessor = `-- name: GetRoomSuccessor :one
+SELECT candidates.user_id
+FROM (
+  SELECT rm.user_id, rm.role AS priority, rm.created_at AS since
+  FROM room_members rm
+  WHERE rm.room_id = ?1
+  UNION ALL
+  SELECT m.sender_id AS user_id, -1 AS priority, MIN(m.created_at) AS since
+  FROM messages m
+  WHERE m.room_id = ?1
+    AND m.deleted_at IS NULL
+  GROUP BY m.sender_id
+) candidates
+JOIN users u ON u.id = candidates.user_id
+WHERE u.id != ?2
+  AND u.disabled_at IS NULL
+ORDER BY candidates.priority DESC, candidates.since
+LIMIT 1
+`
+
+type GetRoomSuccessorParams struct {
+	RoomID  int64
+	OwnerID string
+}
+
+// Who inherits the room when its owner leaves: its moderators, then its other
+// members, then whoever has been posting in it the longest
+func (q *Queries) GetRoomSuccessor(ctx context.Context, arg GetRoomSuccessorParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getRoomSuccessor, arg.RoomID, arg.OwnerID)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getUserById = `-- name: GetUserById :one
+SELECT id, username, username_key, password_hash, created_at, disabled_at
+FROM users
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetUserById(ctx context.Context, id string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserById, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.UsernameKey,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUserByUsername = `-- name: GetUserByUsername :one
+SELECT id, username, username_key, password_hash, created_at, disabled_at
+FROM users
+WHERE username_key = ?
+LIMIT 1
+`
+
+func (q *Queries) GetUserByUsername(ctx context.Context, usernameKey string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByUsername, usernameKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.UsernameKey,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUsernameById = `-- name: GetUsernameById :one
+SELECT username
+FROM users
+WHERE id = ?
+LIMIT 1
+`
+
+func (q *Queries) GetUsernameById(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getUsernameById, id)
+	var username string
+	err := row.Scan(&username)
+	return username, err
+}
+
+const isRefreshTokenValid = `-- name: IsRefreshTokenValid :one
+SELECT 1
+FROM refresh_tokens
+WHERE jti = ?
+  AND user_id = ?
+  AND revoked_at IS NULL
+  AND expire_at > CURRENT_TIMESTAMP
+LIMIT 1
+`
+
+type IsRefreshTokenValidParams struct {
+	Jti    string
+	UserID string
+}
+
+func (q *Queries) IsRefreshTokenValid(ctx context.Context, arg IsRefreshTokenValidParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isRefreshTokenValid, arg.Jti, arg.UserID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const isRoomMember = `-- name: IsRoomMember :one
+SELECT EXISTS (
+  SELECT 1
+  FROM room_members
+  WHERE room_id = ?
+    AND user_id = ?
+)
+`
+
+type IsRoomMemberParams struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) IsRoomMember(ctx context.Context, arg IsRoomMemberParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, isRoomMember, arg.RoomID, arg.UserID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listActiveTokensForUser = `-- name: ListActiveTokensForUser :many
+SELECT jti, user_id, created_at, expire_at, revoked_at
+FROM refresh_tokens
+WHERE user_id = ?
+`
+
+func (q *Queries) ListActiveTokensForUser(ctx context.Context, userID string) ([]RefreshToken, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveTokensForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefreshToken
+	for rows.Next() {
+		var i RefreshToken
+		if err := rows.Scan(
+			&i.Jti,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.ExpireAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAttachmentThumbnails = `-- name: ListAttachmentThumbnails :many
+SELECT attachment_id, size, width, height, mime, bytes
+FROM attachment_thumbnails
+WHERE attachment_id = ?
+ORDER BY size
+`
+
+func (q *Queries) ListAttachmentThumbnails(ctx context.Context, attachmentID string) ([]AttachmentThumbnail, error) {
+	rows, err := q.db.QueryContext(ctx, listAttachmentThumbnails, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AttachmentThumbnail
+	for rows.Next() {
+		var i AttachmentThumbnail
+		if err := rows.Scan(
+			&i.AttachmentID,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.Mime,
+			&i.Bytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAttachmentsByUploader = `-- name: ListAttachmentsByUploader :many
+SELECT id, uploader_id, message_id, filename, mime, size, width, height, created_at
+FROM attachments
+WHERE uploader_id = ?
+ORDER BY created_at
+`
+
+func (q *Queries) ListAttachmentsByUploader(ctx context.Context, uploaderID string) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, listAttachmentsByUploader, uploaderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploaderID,
+			&i.MessageID,
+			&i.Filename,
+			&i.Mime,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listConversationMembers = `-- name: ListConversationMembers :many
+SELECT m.user_id, u.username
+FROM conversation_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.conversation_id = ?
+ORDER BY u.username
+`
+
+type ListConversationMembersRow struct {
+	UserID   string
+	Username string
+}
+
+func (q *Queries) ListConversationMembers(ctx context.Context, conversationID int64) ([]ListConversationMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listConversationMembers, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListConversationMembersRow
+	for rows.Next() {
+		var i ListConversationMembersRow
+		if err := rows.Scan(&i.UserID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listConversationsForUser = `-- name: ListConversationsForUser :many
+SELECT c.id, c.member_key, c.created_at, c.updated_at
+FROM conversations c
+JOIN conversation_members m ON m.conversation_id = c.id
+WHERE m.user_id = ?
+ORDER BY c.updated_at DESC
+`
+
+func (q *Queries) ListConversationsForUser(ctx context.Context, userID string) ([]Conversation, error) {
+	rows, err := q.db.QueryContext(ctx, listConversationsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Conversation
+	for rows.Next() {
+		var i Conversation
+		if err := rows.Scan(
+			&i.ID,
+			&i.MemberKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDirectMessages = `-- name: ListDirectMessages :many
+SELECT d.id, d.conversation_id, d.sender_id, u.username AS sender_username, d.msg, d.created_at
+FROM direct_messages d
+LEFT JOIN users u ON u.id = d.sender_id
+WHERE d.conversation_id = ?
+  AND d.id < ?
+ORDER BY d.id DESC
+LIMIT ?
+`
+
+type ListDirectMessagesParams struct {
+	ConversationID int64
+	ID             int64
+	Limit          int64
+}
+
+type ListDirectMessagesRow struct {
+	ID             int64
+	ConversationID int64
+	SenderID       string
+	SenderUsername sql.NullString
+	Msg            string
+	CreatedAt      time.Time
+}
+
+func (q *Queries) ListDirectMessages(ctx context.Context, arg ListDirectMessagesParams) ([]ListDirectMessagesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDirectMessages, arg.ConversationID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDirectMessagesRow
+	for rows.Next() {
+		var i ListDirectMessagesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConversationID,
+			&i.SenderID,
+			&i.SenderUsername,
+			&i.Msg,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDirectMessagesBySender = `-- name: ListDirectMessagesBySender :many
+SELECT id, conversation_id, sender_id, msg, created_at
+FROM direct_messages
+WHERE sender_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListDirectMessagesBySender(ctx context.Context, senderID string) ([]DirectMessage, error) {
+	rows, err := q.db.QueryContext(ctx, listDirectMessagesBySender, senderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DirectMessage
+	for rows.Next() {
+		var i DirectMessage
+		if err := rows.Scan(
+			&i.ID,
+			&i.ConversationID,
+			&i.SenderID,
+			&i.Msg,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDisabledUserIds = `-- name: ListDisabledUserIds :many
+SELECT id
+FROM users
+WHERE disabled_at IS NOT NULL
+`
+
+func (q *Queries) ListDisabledUserIds(ctx context.Context) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDisabledUserIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInviteCodeUses = `-- name: ListInviteCodeUses :many
+SELECT cu.code, cu.user_id, u.username, cu.used_at
+FROM room_invite_code_uses cu
+JOIN users u ON u.id = cu.user_id
+WHERE cu.room_id = ?
+ORDER BY cu.used_at
+`
+
+type ListInviteCodeUsesRow struct {
+	Code     string
+	UserID   string
+	Username string
+	UsedAt   time.Time
+}
+
+func (q *Queries) ListInviteCodeUses(ctx context.Context, roomID int64) ([]ListInviteCodeUsesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listInviteCodeUses, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListInviteCodeUsesRow
+	for rows.Next() {
+		var i ListInviteCodeUsesRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.UserID,
+			&i.Username,
+			&i.UsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listInviteCodes = `-- name: ListInviteCodes :many
+SELECT code, room_id, created_by, role, max_uses, uses, expires_at, revoked_at, created_at
+FROM room_invite_codes
+WHERE room_id = ?
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListInviteCodes(ctx context.Context, roomID int64) ([]RoomInviteCode, error) {
+	rows, err := q.db.QueryContext(ctx, listInviteCodes, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomInviteCode
+	for rows.Next() {
+		var i RoomInviteCode
+		if err := rows.Scan(
+			&i.Code,
+			&i.RoomID,
+			&i.CreatedBy,
+			&i.Role,
+			&i.MaxUses,
+			&i.Uses,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMemberRoomIds = `-- name: ListMemberRoomIds :many
+SELECT room_id
+FROM room_members
+WHERE user_id = ?
+`
+
+func (q *Queries) ListMemberRoomIds(ctx context.Context, userID string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listMemberRoomIds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var room_id int64
+		if err := rows.Scan(&room_id); err != nil {
+			return nil, err
+		}
+		items = append(items, room_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageAttachments = `-- name: ListMessageAttachments :many
+SELECT id, uploader_id, message_id, filename, mime, size, width, height, created_at
+FROM attachments
+WHERE message_id = ?
+ORDER BY rowid
+`
+
+func (q *Queries) ListMessageAttachments(ctx context.Context, messageID sql.NullInt64) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageAttachments, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploaderID,
+			&i.MessageID,
+			&i.Filename,
+			&i.Mime,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageEdits = `-- name: ListMessageEdits :many
+SELECT id, message_id, previous_msg, edited_by, edited_at
+FROM message_edits
+WHERE message_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListMessageEdits(ctx context.Context, messageID int64) ([]MessageEdit, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageEdits, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageEdit
+	for rows.Next() {
+		var i MessageEdit
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.PreviousMsg,
+			&i.EditedBy,
+			&i.EditedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageLinkPreviews = `-- name: ListMessageLinkPreviews :many
+SELECT message_id, url, title, description, image_url, site_name, position
+FROM message_link_previews
+WHERE message_id = ?
+ORDER BY position
+`
+
+func (q *Queries) ListMessageLinkPreviews(ctx context.Context, messageID int64) ([]MessageLinkPreview, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageLinkPreviews, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageLinkPreview
+	for rows.Next() {
+		var i MessageLinkPreview
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ImageUrl,
+			&i.SiteName,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessageThumbnails = `-- name: ListMessageThumbnails :many
+SELECT t.attachment_id, t.size, t.width, t.height, t.mime, t.bytes
+FROM attachment_thumbnails t
+JOIN attachments a ON a.id = t.attachment_id
+WHERE a.message_id = ?
+ORDER BY t.attachment_id, t.size
+`
+
+func (q *Queries) ListMessageThumbnails(ctx context.Context, messageID sql.NullInt64) ([]AttachmentThumbnail, error) {
+	rows, err := q.db.QueryContext(ctx, listMessageThumbnails, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AttachmentThumbnail
+	for rows.Next() {
+		var i AttachmentThumbnail
+		if err := rows.Scan(
+			&i.AttachmentID,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.Mime,
+			&i.Bytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMessagesBySender = `-- name: ListMessagesBySender :many
+SELECT id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+FROM messages
+WHERE sender_id = ?
+  AND deleted_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListMessagesBySender(ctx context.Context, senderID string) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, listMessagesBySender, senderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.ParentID,
+			&i.SenderID,
+			&i.SenderUsername,
+			&i.Msg,
+			&i.ClientID,
+			&i.Action,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listModeratorMemberships = `-- name: ListModeratorMemberships :many
+SELECT room_id, user_id
+FROM room_members
+WHERE role >= 1
+`
+
+type ListModeratorMembershipsRow struct {
+	RoomID int64
+	UserID string
+}
+
+func (q *Queries) ListModeratorMemberships(ctx context.Context) ([]ListModeratorMembershipsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listModeratorMemberships)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListModeratorMembershipsRow
+	for rows.Next() {
+		var i ListModeratorMembershipsRow
+		if err := rows.Scan(&i.RoomID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrphanAttachments = `-- name: ListOrphanAttachments :many
+SELECT a.id, a.uploader_id, a.message_id, a.filename, a.mime, a.size, a.width, a.height, a.created_at
+FROM attachments a
+LEFT JOIN messages m ON m.id = a.message_id
+WHERE (a.message_id IS NULL AND a.created_at < ?)
+  OR (a.message_id IS NOT NULL AND m.id IS NULL)
+  OR m.deleted_at IS NOT NULL
+`
+
+func (q *Queries) ListOrphanAttachments(ctx context.Context, createdAt time.Time) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, listOrphanAttachments, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploaderID,
+			&i.MessageID,
+			&i.Filename,
+			&i.Mime,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReactions = `-- name: ListReactions :many
+SELECT emoji, user_id
+FROM message_reactions
+WHERE message_id = ?
+ORDER BY rowid
+`
+
+type ListReactionsRow struct {
+	Emoji  string
+	UserID string
+}
+
+func (q *Queries) ListReactions(ctx context.Context, messageID int64) ([]ListReactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listReactions, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListReactionsRow
+	for rows.Next() {
+		var i ListReactionsRow
+		if err := rows.Scan(&i.Emoji, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReactionsByUser = `-- name: ListReactionsByUser :many
+SELECT message_id, user_id, emoji, created_at
+FROM message_reactions
+WHERE user_id = ?
+ORDER BY created_at
+`
+
+func (q *Queries) ListReactionsByUser(ctx context.Context, userID string) ([]MessageReaction, error) {
+	rows, err := q.db.QueryContext(ctx, listReactionsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageReaction
+	for rows.Next() {
+		var i MessageReaction
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.UserID,
+			&i.Emoji,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomEvents = `-- name: ListRoomEvents :many
+SELECT room_id, seq, user_id, message_id, packet, created_at
+FROM room_events
+WHERE room_id = ?
+  AND seq > ?
+ORDER BY seq
+LIMIT ?
+`
+
+type ListRoomEventsParams struct {
+	RoomID int64
+	Seq    int64
+	Limit  int64
+}
+
+func (q *Queries) ListRoomEvents(ctx context.Context, arg ListRoomEventsParams) ([]RoomEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomEvents, arg.RoomID, arg.Seq, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomEvent
+	for rows.Next() {
+		var i RoomEvent
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.Seq,
+			&i.UserID,
+			&i.MessageID,
+			&i.Packet,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomEventsForMessage = `-- name: ListRoomEventsForMessage :many
+SELECT room_id, seq, user_id, message_id, packet, created_at
+FROM room_events
+WHERE message_id = ?
+`
+
+func (q *Queries) ListRoomEventsForMessage(ctx context.Context, messageID sql.NullInt64) ([]RoomEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomEventsForMessage, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomEvent
+	for rows.Next() {
+		var i RoomEvent
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.Seq,
+			&i.UserID,
+			&i.MessageID,
+			&i.Packet,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomIdsByOwner = `-- name: ListRoomIdsByOwner :many
+SELECT id
+FROM rooms
+WHERE owner_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListRoomIdsByOwner(ctx context.Context, ownerID string) ([]int64, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomIdsByOwner, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomInvitesForUser = `-- name: ListRoomInvitesForUser :many
+SELECT i.room_id, r.name AS room_name, i.inviter_id, u.username AS inviter_username
+FROM room_invites i
+JOIN rooms r ON r.id = i.room_id
+JOIN users u ON u.id = i.inviter_id
+WHERE i.user_id = ?
+ORDER BY i.created_at
+`
+
+type ListRoomInvitesForUserRow struct {
+	RoomID          int64
+	RoomName        string
+	InviterID       string
+	InviterUsername string
+}
+
+func (q *Queries) ListRoomInvitesForUser(ctx context.Context, userID string) ([]ListRoomInvitesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomInvitesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRoomInvitesForUserRow
+	for rows.Next() {
+		var i ListRoomInvitesForUserRow
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.RoomName,
+			&i.InviterID,
+			&i.InviterUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomReactions = `-- name: ListRoomReactions :many
+SELECT r.message_id, r.emoji, r.user_id
+FROM message_reactions r
+JOIN messages m ON m.id = r.message_id
+WHERE m.room_id = ?
+  AND r.message_id >= ?
+ORDER BY r.message_id, r.rowid
+`
+
+type ListRoomReactionsParams struct {
+	RoomID    int64
+	MessageID int64
+}
+
+type ListRoomReactionsRow struct {
+	MessageID int64
+	Emoji     string
+	UserID    string
+}
+
+func (q *Queries) ListRoomReactions(ctx context.Context, arg ListRoomReactionsParams) ([]ListRoomReactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomReactions, arg.RoomID, arg.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRoomReactionsRow
+	for rows.Next() {
+		var i ListRoomReactionsRow
+		if err := rows.Scan(&i.MessageID, &i.Emoji, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomReadsForUser = `-- name: ListRoomReadsForUser :many
+SELECT user_id, room_id, last_read_id, updated_at
+FROM room_reads
+WHERE user_id = ?
+ORDER BY room_id
+`
+
+func (q *Queries) ListRoomReadsForUser(ctx context.Context, userID string) ([]RoomRead, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomReadsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RoomRead
+	for rows.Next() {
+		var i RoomRead
+		if err := rows.Scan(
+			&i.UserID,
+			&i.RoomID,
+			&i.LastReadID,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomReplyCounts = `-- name: ListRoomReplyCounts :many
+SELECT parent_id, COUNT(*) AS reply_count
+FROM messages
+WHERE room_id = ?
+  AND parent_id >= ?
+  AND deleted_at IS NULL
+GROUP BY parent_id
+`
+
+type ListRoomReplyCountsParams struct {
+	RoomID   int64
+	ParentID sql.NullInt64
+}
+
+type ListRoomReplyCountsRow struct {
+	ParentID   sql.NullInt64
+	ReplyCount int64
+}
+
+func (q *Queries) ListRoomReplyCounts(ctx context.Context, arg ListRoomReplyCountsParams) ([]ListRoomReplyCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRoomReplyCounts, arg.RoomID, arg.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRoomReplyCountsRow
+	for rows.Next() {
+		var i ListRoomReplyCountsRow
+		if err := rows.Scan(&i.ParentID, &i.ReplyCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRooms = `-- name: ListRooms :many
+SELECT id, owner_id, name, last_seq, slow_mode_seconds, topic, description, visibility, created_at
+FROM rooms
+ORDER BY id
+`
+
+func (q *Queries) ListRooms(ctx context.Context) ([]Room, error) {
+	rows, err := q.db.QueryContext(ctx, listRooms)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Room
+	for rows.Next() {
+		var i Room
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Name,
+			&i.LastSeq,
+			&i.SlowModeSeconds,
+			&i.Topic,
+			&i.Description,
+			&i.Visibility,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadAttachments = `-- name: ListThreadAttachments :many
+SELECT a.id, a.uploader_id, a.message_id, a.filename, a.mime, a.size, a.width, a.height, a.created_at
+FROM attachments a
+JOIN messages m ON m.id = a.message_id
+WHERE m.id = ?1
+  OR m.parent_id = ?1
+ORDER BY a.message_id, a.rowid
+`
+
+func (q *Queries) ListThreadAttachments(ctx context.Context, parentID int64) ([]Attachment, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadAttachments, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.UploaderID,
+			&i.MessageID,
+			&i.Filename,
+			&i.Mime,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadLinkPreviews = `-- name: ListThreadLinkPreviews :many
+SELECT mp.message_id, mp.url, mp.title, mp.description, mp.image_url, mp.site_name, mp.position
+FROM message_link_previews mp
+JOIN messages m ON m.id = mp.message_id
+WHERE m.id = ?1
+  OR m.parent_id = ?1
+ORDER BY mp.message_id, mp.position
+`
+
+func (q *Queries) ListThreadLinkPreviews(ctx context.Context, parentID int64) ([]MessageLinkPreview, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadLinkPreviews, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MessageLinkPreview
+	for rows.Next() {
+		var i MessageLinkPreview
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.ImageUrl,
+			&i.SiteName,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadReactions = `-- name: ListThreadReactions :many
+SELECT r.message_id, r.emoji, r.user_id
+FROM message_reactions r
+JOIN messages m ON m.id = r.message_id
+WHERE m.id = ?1
+  OR m.parent_id = ?1
+ORDER BY r.message_id, r.rowid
+`
+
+type ListThreadReactionsRow struct {
+	MessageID int64
+	Emoji     string
+	UserID    string
+}
+
+func (q *Queries) ListThreadReactions(ctx context.Context, parentID int64) ([]ListThreadReactionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadReactions, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListThreadReactionsRow
+	for rows.Next() {
+		var i ListThreadReactionsRow
+		if err := rows.Scan(&i.MessageID, &i.Emoji, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadReplies = `-- name: ListThreadReplies :many
+SELECT id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+FROM messages
+WHERE parent_id = ?
+  AND id > ?
+  AND deleted_at IS NULL
+ORDER BY id
+LIMIT ?
+`
+
+type ListThreadRepliesParams struct {
+	ParentID sql.NullInt64
+	ID       int64
+	Limit    int64
+}
+
+func (q *Queries) ListThreadReplies(ctx context.Context, arg ListThreadRepliesParams) ([]Message, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadReplies, arg.ParentID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Message
+	for rows.Next() {
+		var i Message
+		if err := rows.Scan(
+			&i.ID,
+			&i.RoomID,
+			&i.ParentID,
+			&i.SenderID,
+			&i.SenderUsername,
+			&i.Msg,
+			&i.ClientID,
+			&i.Action,
+			&i.CreatedAt,
+			&i.EditedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadThumbnails = `-- name: ListThreadThumbnails :many
+SELECT t.attachment_id, t.size, t.width, t.height, t.mime, t.bytes
+FROM attachment_thumbnails t
+JOIN attachments a ON a.id = t.attachment_id
+JOIN messages m ON m.id = a.message_id
+WHERE m.id = ?1
+  OR m.parent_id = ?1
+ORDER BY t.attachment_id, t.size
+`
+
+func (q *Queries) ListThreadThumbnails(ctx context.Context, parentID int64) ([]AttachmentThumbnail, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadThumbnails, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AttachmentThumbnail
+	for rows.Next() {
+		var i AttachmentThumbnail
+		if err := rows.Scan(
+			&i.AttachmentID,
+			&i.Size,
+			&i.Width,
+			&i.Height,
+			&i.Mime,
+			&i.Bytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTokens = `-- name: ListTokens :many
+SELECT jti, user_id, created_at, expire_at, revoked_at
 FROM refresh_tokens
-WHERE jti = ?
-  AND user_id = ?
-  AND revoked_at IS NULL
-  AND expire_at > CURRENT_TIMESTAMP
-LIMIT 1
+ORDER BY created_at
 `
 
-type IsRefreshTokenValidParams struct {
-	Jti    string
+func (q *Queries) ListTokens(ctx context.Context) ([]RefreshToken, error) {
+	rows, err := q.db.QueryContext(ctx, listTokens)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefreshToken
+	for rows.Next() {
+		var i RefreshToken
+		if err := rows.Scan(
+			&i.Jti,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.ExpireAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnreadCounts = `-- name: ListUnreadCounts :many
+SELECT r.id AS room_id, COUNT(*) AS unread_count
+FROM rooms r
+LEFT JOIN room_reads rr ON rr.room_id = r.id AND rr.user_id = ?1
+CROSS JOIN messages m ON m.room_id = r.id AND m.id > COALESCE(rr.last_read_id, 0)
+WHERE m.sender_id != ?1
+  AND m.deleted_at IS NULL
+  AND (
+    r.visibility != 1
+    OR r.owner_id = ?1
+    OR EXISTS (
+      SELECT 1 FROM room_members rm WHERE rm.room_id = r.id AND rm.user_id = ?1
+    )
+  )
+GROUP BY r.id
+`
+
+type ListUnreadCountsRow struct {
+	RoomID      int64
+	UnreadCount int64
+}
+
+// Only the rooms the user can see, each counted from its read position on
+// the messages(room_id, id) index. CROSS JOIN keeps SQLite from scanning
+// all the messages first
+func (q *Queries) ListUnreadCounts(ctx context.Context, userID string) ([]ListUnreadCountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnreadCounts, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnreadCountsRow
+	for rows.Next() {
+		var i ListUnreadCountsRow
+		if err := rows.Scan(&i.RoomID, &i.UnreadCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnseenMentions = `-- name: ListUnseenMentions :many
+SELECT mn.id, mn.message_id, m.room_id, m.sender_id, m.sender_username, m.msg, m.created_at
+FROM mentions mn
+JOIN messages m ON m.id = mn.message_id
+JOIN rooms r ON r.id = m.room_id
+WHERE mn.user_id = ?1
+  AND mn.seen_at IS NULL
+  AND m.deleted_at IS NULL
+  AND (
+    r.visibility != 1
+    OR r.owner_id = ?1
+    OR EXISTS (
+      SELECT 1 FROM room_members rm WHERE rm.room_id = r.id AND rm.user_id = ?1
+    )
+  )
+ORDER BY mn.id DESC
+LIMIT ?2
+`
+
+type ListUnseenMentionsParams struct {
 	UserID string
+	Limit  int64
 }
 
-func (q *Queries) IsRefreshTokenValid(ctx context.Context, arg IsRefreshTokenValidParams) (int64, error) {
-	row := q.db.QueryRowContext(ctx, isRefreshTokenValid, arg.Jti, arg.UserID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+type ListUnseenMentionsRow struct {
+	ID             int64
+	MessageID      int64
+	RoomID         int64
+	SenderID       string
+	SenderUsername string
+	Msg            string
+	CreatedAt      time.Time
+}
+
+// Mentions in private rooms (visibility 1) are only listed to their owner and members
+func (q *Queries) ListUnseenMentions(ctx context.Context, arg ListUnseenMentionsParams) ([]ListUnseenMentionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUnseenMentions, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUnseenMentionsRow
+	for rows.Next() {
+		var i ListUnseenMentionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MessageID,
+			&i.RoomID,
+			&i.SenderID,
+			&i.SenderUsername,
+			&i.Msg,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const listActiveTokensForUser = `-- name: ListActiveTokensForUser :many
-SELECT jti, user_id, created_at, expire_at, revoked_at
-FROM refresh_tokens
-WHERE user_id = ?
+const listUsers = `-- name: ListUsers :many
+SELECT id, username, username_key, password_hash, created_at, disabled_at
+FROM users
+ORDER BY created_at
 `
 
-func (q *Queries) ListActiveTokensForUser(ctx context.Context, userID string) ([]RefreshToken, error) {
-	rows, err := q.db.QueryContext(ctx, listActiveTokensForUser, userID)
+func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []RefreshToken
+	var items []User
 	for rows.Next() {
-		var i RefreshToken
+		var i User
 		if err := rows.Scan(
-			&i.Jti,
-			&i.UserID,
+			&i.ID,
+			&i.Username,
+			&i.UsernameKey,
+			&i.PasswordHash,
 			&i.CreatedAt,
-			&i.ExpireAt,
-			&i.RevokedAt,
+			&i.DisabledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -141,6 +3064,128 @@ func (q *Queries) ListActiveTokensForUser(ctx context.Context, userID string) ([
 	return items, nil
 }
 
+const markMentionsSeen = `-- name: MarkMentionsSeen :exec
+UPDATE mentions
+SET seen_at = CURRENT_TIMESTAMP
+WHERE user_id = ?
+  AND seen_at IS NULL
+  AND message_id IN (
+    SELECT m.id FROM messages m WHERE m.room_id = ? AND m.id <= ?3
+  )
+`
+
+type MarkMentionsSeenParams struct {
+	UserID     string
+	RoomID     int64
+	LastReadID int64
+}
+
+func (q *Queries) MarkMentionsSeen(ctx context.Context, arg MarkMentionsSeenParams) error {
+	_, err := q.db.ExecContext(ctx, markMentionsSeen, arg.UserID, arg.RoomID, arg.LastReadID)
+	return err
+}
+
+const markRoomRead = `-- name: MarkRoomRead :execrows
+INSERT INTO room_reads (
+  user_id, room_id, last_read_id
+) VALUES (
+  ?, ?, ?
+)
+ON CONFLICT (user_id, room_id) DO UPDATE
+SET last_read_id = excluded.last_read_id,
+  updated_at = CURRENT_TIMESTAMP
+WHERE excluded.last_read_id > room_reads.last_read_id
+`
+
+type MarkRoomReadParams struct {
+	UserID     string
+	RoomID     int64
+	LastReadID int64
+}
+
+func (q *Queries) MarkRoomRead(ctx context.Context, arg MarkRoomReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markRoomRead, arg.UserID, arg.RoomID, arg.LastReadID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const nextRoomSeq = `-- name: NextRoomSeq :one
+UPDATE rooms
+SET last_seq = last_seq + 1
+WHERE id = ?
+RETURNING last_seq
+`
+
+func (q *Queries) NextRoomSeq(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextRoomSeq, id)
+	var last_seq int64
+	err := row.Scan(&last_seq)
+	return last_seq, err
+}
+
+const removeReaction = `-- name: RemoveReaction :exec
+DELETE FROM message_reactions
+WHERE message_id = ?
+  AND user_id = ?
+  AND emoji = ?
+`
+
+type RemoveReactionParams struct {
+	MessageID int64
+	UserID    string
+	Emoji     string
+}
+
+func (q *Queries) RemoveReaction(ctx context.Context, arg RemoveReactionParams) error {
+	_, err := q.db.ExecContext(ctx, removeReaction, arg.MessageID, arg.UserID, arg.Emoji)
+	return err
+}
+
+const removeUserFromConversations = `-- name: RemoveUserFromConversations :exec
+DELETE FROM conversation_members
+WHERE user_id = ?
+`
+
+func (q *Queries) RemoveUserFromConversations(ctx context.Context, userID string) error {
+	_, err := q.db.ExecContext(ctx, removeUserFromConversations, userID)
+	return err
+}
+
+const renameRoom = `-- name: RenameRoom :exec
+UPDATE rooms
+SET name = ?
+WHERE id = ?
+`
+
+type RenameRoomParams struct {
+	Name string
+	ID   int64
+}
+
+func (q *Queries) RenameRoom(ctx context.Context, arg RenameRoomParams) error {
+	_, err := q.db.ExecContext(ctx, renameRoom, arg.Name, arg.ID)
+	return err
+}
+
+const revokeInviteCode = `-- name: RevokeInviteCode :exec
+UPDATE room_invite_codes
+SET revoked_at = ?
+WHERE code = ?
+  AND revoked_at IS NULL
+`
+
+type RevokeInviteCodeParams struct {
+	RevokedAt sql.NullTime
+	Code      string
+}
+
+func (q *Queries) RevokeInviteCode(ctx context.Context, arg RevokeInviteCodeParams) error {
+	_, err := q.db.ExecContext(ctx, revokeInviteCode, arg.RevokedAt, arg.Code)
+	return err
+}
+
 const revokeToken = `-- name: RevokeToken :exec
 UPDATE refresh_tokens
 SET revoked_at = CURRENT_TIMESTAMP
@@ -184,3 +3229,426 @@ func (q *Queries) SaveRefreshToken(ctx context.Context, arg SaveRefreshTokenPara
 	_, err := q.db.ExecContext(ctx, saveRefreshToken, arg.Jti, arg.UserID, arg.ExpireAt)
 	return err
 }
+
+const setLastSeen = `-- name: SetLastSeen :exec
+INSERT INTO presence (
+  user_id, last_seen_at
+)
+SELECT u.id, ?1
+FROM users u
+WHERE u.id = ?2
+ON CONFLICT (user_id) DO UPDATE
+SET last_seen_at = excluded.last_seen_at
+`
+
+type SetLastSeenParams struct {
+	LastSeenAt sql.NullTime
+	UserID     string
+}
+
+// Nothing is saved for users whose account was deleted while they were online
+func (q *Queries) SetLastSeen(ctx context.Context, arg SetLastSeenParams) error {
+	_, err := q.db.ExecContext(ctx, setLastSeen, arg.LastSeenAt, arg.UserID)
+	return err
+}
+
+const setPresenceStatus = `-- name: SetPresenceStatus :exec
+INSERT INTO presence (
+  user_id, status
+) VALUES (
+  ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET status = excluded.status
+`
+
+type SetPresenceStatusParams struct {
+	UserID string
+	Status int64
+}
+
+func (q *Queries) SetPresenceStatus(ctx context.Context, arg SetPresenceStatusParams) error {
+	_, err := q.db.ExecContext(ctx, setPresenceStatus, arg.UserID, arg.Status)
+	return err
+}
+
+const setProfileAvatar = `-- name: SetProfileAvatar :exec
+UPDATE profiles
+SET avatar = ?,
+  avatar_mime = ?
+WHERE user_id = ?
+`
+
+type SetProfileAvatarParams struct {
+	Avatar     []byte
+	AvatarMime string
+	UserID     string
+}
+
+// Saved along with UpsertProfile, which counts the change in the version
+func (q *Queries) SetProfileAvatar(ctx context.Context, arg SetProfileAvatarParams) error {
+	_, err := q.db.ExecContext(ctx, setProfileAvatar, arg.Avatar, arg.AvatarMime, arg.UserID)
+	return err
+}
+
+const setProfileDisplayName = `-- name: SetProfileDisplayName :exec
+INSERT INTO profiles (
+  user_id, display_name
+) VALUES (
+  ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET display_name = excluded.display_name,
+  version = version + 1,
+  updated_at = CURRENT_TIMESTAMP
+`
+
+type SetProfileDisplayNameParams struct {
+	UserID      string
+	DisplayName string
+}
+
+func (q *Queries) SetProfileDisplayName(ctx context.Context, arg SetProfileDisplayNameParams) error {
+	_, err := q.db.ExecContext(ctx, setProfileDisplayName, arg.UserID, arg.DisplayName)
+	return err
+}
+
+const setRoomDetails = `-- name: SetRoomDetails :exec
+UPDATE rooms
+SET topic = ?,
+  description = ?
+WHERE id = ?
+`
+
+type SetRoomDetailsParams struct {
+	Topic       string
+	Description string
+	ID          int64
+}
+
+func (q *Queries) SetRoomDetails(ctx context.Context, arg SetRoomDetailsParams) error {
+	_, err := q.db.ExecContext(ctx, setRoomDetails, arg.Topic, arg.Description, arg.ID)
+	return err
+}
+
+const setRoomSlowMode = `-- name: SetRoomSlowMode :exec
+UPDATE rooms
+SET slow_mode_seconds = ?
+WHERE id = ?
+`
+
+type SetRoomSlowModeParams struct {
+	SlowModeSeconds int64
+	ID              int64
+}
+
+func (q *Queries) SetRoomSlowMode(ctx context.Context, arg SetRoomSlowModeParams) error {
+	_, err := q.db.ExecContext(ctx, setRoomSlowMode, arg.SlowModeSeconds, arg.ID)
+	return err
+}
+
+const setRoomVisibility = `-- name: SetRoomVisibility :exec
+UPDATE rooms
+SET visibility = ?
+WHERE id = ?
+`
+
+type SetRoomVisibilityParams struct {
+	Visibility int64
+	ID         int64
+}
+
+func (q *Queries) SetRoomVisibility(ctx context.Context, arg SetRoomVisibilityParams) error {
+	_, err := q.db.ExecContext(ctx, setRoomVisibility, arg.Visibility, arg.ID)
+	return err
+}
+
+const setUserDisabled = `-- name: SetUserDisabled :execrows
+UPDATE users
+SET disabled_at = ?
+WHERE id = ?
+`
+
+type SetUserDisabledParams struct {
+	DisabledAt sql.NullTime
+	ID         string
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserDisabled, arg.DisabledAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const touchConversation = `-- name: TouchConversation :exec
+UPDATE conversations
+SET updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) TouchConversation(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, touchConversation, id)
+	return err
+}
+
+const updateMessage = `-- name: UpdateMessage :one
+UPDATE messages
+SET msg = ?,
+  edited_at = ?
+WHERE id = ?
+RETURNING id, room_id, parent_id, sender_id, sender_username, msg, client_id, "action", created_at, edited_at, deleted_at
+`
+
+type UpdateMessageParams struct {
+	Msg      string
+	EditedAt sql.NullTime
+	ID       int64
+}
+
+func (q *Queries) UpdateMessage(ctx context.Context, arg UpdateMessageParams) (Message, error) {
+	row := q.db.QueryRowContext(ctx, updateMessage, arg.Msg, arg.EditedAt, arg.ID)
+	var i Message
+	err := row.Scan(
+		&i.ID,
+		&i.RoomID,
+		&i.ParentID,
+		&i.SenderID,
+		&i.SenderUsername,
+		&i.Msg,
+		&i.ClientID,
+		&i.Action,
+		&i.CreatedAt,
+		&i.EditedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updatePassword = `-- name: UpdatePassword :execrows
+UPDATE users
+SET password_hash = ?
+WHERE id = ?
+`
+
+type UpdatePasswordParams struct {
+	PasswordHash string
+	ID           string
+}
+
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updatePassword, arg.PasswordHash, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateRoomEventPacket = `-- name: UpdateRoomEventPacket :exec
+UPDATE room_events
+SET packet = ?
+WHERE room_id = ?
+  AND seq = ?
+`
+
+type UpdateRoomEventPacketParams struct {
+	Packet []byte
+	RoomID int64
+	Seq    int64
+}
+
+func (q *Queries) UpdateRoomEventPacket(ctx context.Context, arg UpdateRoomEventPacketParams) error {
+	_, err := q.db.ExecContext(ctx, updateRoomEventPacket, arg.Packet, arg.RoomID, arg.Seq)
+	return err
+}
+
+const updateRoomOwner = `-- name: UpdateRoomOwner :exec
+UPDATE rooms
+SET owner_id = ?
+WHERE id = ?
+`
+
+type UpdateRoomOwnerParams struct {
+	OwnerID string
+	ID      int64
+}
+
+func (q *Queries) UpdateRoomOwner(ctx context.Context, arg UpdateRoomOwnerParams) error {
+	_, err := q.db.ExecContext(ctx, updateRoomOwner, arg.OwnerID, arg.ID)
+	return err
+}
+
+const upsertLinkPreview = `-- name: UpsertLinkPreview :one
+INSERT INTO link_previews (
+  url, title, description, image_url, site_name, fetched_at
+) VALUES (
+  ?, ?, ?, ?, ?, ?
+)
+ON CONFLICT (url) DO UPDATE SET
+  title = excluded.title,
+  description = excluded.description,
+  image_url = excluded.image_url,
+  site_name = excluded.site_name,
+  fetched_at = excluded.fetched_at
+RETURNING url, title, description, image_url, site_name, fetched_at
+`
+
+type UpsertLinkPreviewParams struct {
+	Url         string
+	Title       string
+	Description string
+	ImageUrl    string
+	SiteName    string
+	FetchedAt   time.Time
+}
+
+func (q *Queries) UpsertLinkPreview(ctx context.Context, arg UpsertLinkPreviewParams) (LinkPreview, error) {
+	row := q.db.QueryRowContext(ctx, upsertLinkPreview,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.ImageUrl,
+		arg.SiteName,
+		arg.FetchedAt,
+	)
+	var i LinkPreview
+	err := row.Scan(
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.ImageUrl,
+		&i.SiteName,
+		&i.FetchedAt,
+	)
+	return i, err
+}
+
+const upsertProfile = `-- name: UpsertProfile :exec
+INSERT INTO profiles (
+  user_id, display_name, bio, status
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (user_id) DO UPDATE
+SET display_name = excluded.display_name,
+  bio = excluded.bio,
+  status = excluded.status,
+  version = version + 1,
+  updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertProfileParams struct {
+	UserID      string
+	DisplayName string
+	Bio         string
+	Status      string
+}
+
+func (q *Queries) UpsertProfile(ctx context.Context, arg UpsertProfileParams) error {
+	_, err := q.db.ExecContext(ctx, upsertProfile,
+		arg.UserID,
+		arg.DisplayName,
+		arg.Bio,
+		arg.Status,
+	)
+	return err
+}
+
+const upsertRoomInvite = `-- name: UpsertRoomInvite :exec
+INSERT INTO room_invites (
+  room_id, user_id, inviter_id, created_at
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET inviter_id = excluded.inviter_id,
+  created_at = excluded.created_at
+`
+
+type UpsertRoomInviteParams struct {
+	RoomID    int64
+	UserID    string
+	InviterID string
+	CreatedAt time.Time
+}
+
+func (q *Queries) UpsertRoomInvite(ctx context.Context, arg UpsertRoomInviteParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRoomInvite,
+		arg.RoomID,
+		arg.UserID,
+		arg.InviterID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const upsertRoomMemberRole = `-- name: UpsertRoomMemberRole :exec
+INSERT INTO room_members (
+  room_id, user_id, added_by, role
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET role = max(room_members.role, excluded.role)
+`
+
+type UpsertRoomMemberRoleParams struct {
+	RoomID  int64
+	UserID  string
+	AddedBy string
+	Role    int64
+}
+
+func (q *Queries) UpsertRoomMemberRole(ctx context.Context, arg UpsertRoomMemberRoleParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRoomMemberRole,
+		arg.RoomID,
+		arg.UserID,
+		arg.AddedBy,
+		arg.Role,
+	)
+	return err
+}
+
+const upsertRoomMute = `-- name: UpsertRoomMute :exec
+INSERT INTO room_mutes (
+  room_id, user_id, muted_by, muted_until
+) VALUES (
+  ?, ?, ?, ?
+)
+ON CONFLICT (room_id, user_id) DO UPDATE
+SET muted_by = excluded.muted_by,
+  muted_until = excluded.muted_until
+`
+
+type UpsertRoomMuteParams struct {
+	RoomID     int64
+	UserID     string
+	MutedBy    string
+	MutedUntil time.Time
+}
+
+func (q *Queries) UpsertRoomMute(ctx context.Context, arg UpsertRoomMuteParams) error {
+	_, err := q.db.ExecContext(ctx, upsertRoomMute,
+		arg.RoomID,
+		arg.UserID,
+		arg.MutedBy,
+		arg.MutedUntil,
+	)
+	return err
+}
+
+const useInviteCode = `-- name: UseInviteCode :execrows
+UPDATE room_invite_codes
+SET uses = uses + 1
+WHERE code = ?
+  AND (max_uses = 0 OR uses < max_uses)
+`
+
+func (q *Queries) UseInviteCode(ctx context.Context, code string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useInviteCode, code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
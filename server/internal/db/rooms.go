@@ -0,0 +1,30 @@
+package db
+
+import "context"
+
+// Deletes the room with its messages and everything that refers to them.
+// Attachments are left to the orphan cleanup, which also removes their files.
+// Returns how many rooms were deleted, zero when it didn't exist
+func (q *Queries) PurgeRoom(ctx context.Context, id int64) (int64, error) {
+	purges := []func(context.Context, int64) error{
+		q.DeleteRoomMessageEdits,
+		q.DeleteRoomReactions,
+		q.DeleteRoomMentions,
+		q.DeleteRoomLinkPreviews,
+		q.DetachRoomAttachments,
+		q.DeleteRoomMessages,
+		q.DeleteRoomReads,
+		q.DeleteRoomEvents,
+		q.DeleteRoomMutes,
+		q.DeleteRoomMembers,
+		q.DeleteRoomInvites,
+		q.DeleteRoomInviteCodeUses,
+		q.DeleteRoomInviteCodes,
+	}
+	for _, purge := range purges {
+		if err := purge(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+	return q.DeleteRoom(ctx, id)
+}
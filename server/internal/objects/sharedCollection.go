@@ -41,6 +41,32 @@ func (s *SharedCollection[T]) Add(obj T, id ...uint64) uint64 {
 	return thisId
 }
 
+// Replaces the object with the given ID, or adds it if it doesn't exist
+// Unlike Add, the next available ID is left untouched
+func (s *SharedCollection[T]) Set(id uint64, obj T) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.objectMap[id] = obj
+}
+
+// Changes the object with the given ID in place while holding the lock, so
+// concurrent updates of different fields don't overwrite each other. Does
+// nothing if it doesn't exist. Returns the updated object and whether it was found
+func (s *SharedCollection[T]) Update(id uint64, update func(obj *T)) (T, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	obj, found := s.objectMap[id]
+	if !found {
+		return obj, false
+	}
+
+	update(&obj)
+	s.objectMap[id] = obj
+	return obj, true
+}
+
 // Removes an object from the map by ID, if it exists
 func (s *SharedCollection[T]) Remove(id uint64) {
 	s.Lock()
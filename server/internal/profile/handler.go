@@ -0,0 +1,161 @@
+package profile
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"server/internal/jwt"
+	"server/pkg/packets"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type Handler struct {
+	Service Service
+}
+
+func NewHandler(s Service) *Handler {
+	return &Handler{
+		Service: s,
+	}
+}
+
+func (h *Handler) GetProfile(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_ProfileRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// An empty user id means the caller is asking for their own profile
+	userId := pktMessage.ProfileRequest.UserId
+	if userId == "" {
+		userId = accessToken.Subject
+	}
+
+	profileMessage, err := h.Service.GetProfile(request.Context(), userId)
+	if err != nil {
+		log.Printf("An error occured when trying to get profile: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(profileMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) UpdateProfile(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(writer, request.Body, int64(maxAvatarBytes)+4096))
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_UpdateProfile)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	profileMessage, err := h.Service.UpdateProfile(request.Context(), accessToken.Subject, pktMessage.UpdateProfile)
+	if err != nil {
+		log.Printf("An error occured when trying to update profile: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(profileMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+// Serves the raw avatar image. The token comes in the query string so the
+// avatar can be used directly as an image source
+func (h *Handler) GetAvatar(writer http.ResponseWriter, request *http.Request) {
+	token := request.URL.Query().Get("token")
+	_, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	userId := request.URL.Query().Get("user")
+	avatar, mime, err := h.Service.GetAvatar(request.Context(), userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(writer, "Avatar not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("An error occured when trying to get avatar: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	// Only accepted images are stored, keep browsers from treating them as anything else
+	writer.Header().Set("Content-Type", mime)
+	writer.Header().Set("Content-Disposition", "inline")
+	writer.Header().Set("X-Content-Type-Options", "nosniff")
+	writer.Header().Set("Cache-Control", "private, max-age=86400")
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(avatar)
+}
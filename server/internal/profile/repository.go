@@ -0,0 +1,31 @@
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"server/internal/db"
+)
+
+type Repository struct {
+	dbPool  *sql.DB
+	queries *db.Queries
+}
+
+func NewRepository(dbPool *sql.DB) Repository {
+	return Repository{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+	}
+}
+
+func (r *Repository) GetProfile(ctx context.Context, userId string) (db.GetProfileRow, error) {
+	return r.queries.GetProfile(ctx, userId)
+}
+
+func (r *Repository) GetProfileAvatar(ctx context.Context, userId string) (db.GetProfileAvatarRow, error) {
+	return r.queries.GetProfileAvatar(ctx, userId)
+}
+
+func (r *Repository) GetUsernameById(ctx context.Context, userId string) (string, error) {
+	return r.queries.GetUsernameById(ctx, userId)
+}
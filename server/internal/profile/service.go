@@ -0,0 +1,210 @@
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"server/internal/db"
+	"server/internal/usernames"
+	"server/internal/ws"
+	"server/pkg/packets"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	maxBioChars    = 280
+	maxStatusChars = 64
+	maxAvatarBytes = 256 * 1024
+
+	allowedAvatarMimes = map[string]bool{
+		"image/png":  true,
+		"image/jpeg": true,
+		"image/gif":  true,
+		"image/webp": true,
+	}
+)
+
+type Service struct {
+	repo Repository
+	hub  *ws.Hub
+}
+
+func NewService(repository Repository, hub *ws.Hub) Service {
+	return Service{
+		repo: repository,
+		hub:  hub,
+	}
+}
+
+func (s *Service) GetProfile(c context.Context, userId string) (*packets.Message, error) {
+	if _, err := s.repo.GetUsernameById(c, userId); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			reasonMessage := &packets.Message{
+				Type: packets.NewDenyResponseMsg("User not found"),
+			}
+			return reasonMessage, nil
+		}
+		reason := fmt.Sprintf("error getting user: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	profile, err := s.getProfile(c, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	profileMessage := &packets.Message{
+		Type: packets.NewProfileMsg(profile),
+	}
+	return profileMessage, nil
+}
+
+// Changes the fields of the profile the update sets, leaving the others as
+// they are. The changes are saved together and count as one new version
+func (s *Service) UpdateProfile(c context.Context, userId string, update *packets.UpdateProfileRequestMessage) (*packets.Message, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	current, err := queries.GetProfile(c, userId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		reason := fmt.Sprintf("error getting profile: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	displayName, bio, status := current.DisplayName, current.Bio, current.Status
+	if update.DisplayName != nil {
+		displayName, err = usernames.CleanDisplayName(*update.DisplayName)
+	}
+	if update.Bio != nil {
+		bio = strings.TrimSpace(*update.Bio)
+	}
+	if update.Status != nil {
+		status = strings.TrimSpace(*update.Status)
+	}
+	if err == nil {
+		err = validateProfileText(bio, status)
+	}
+	if err != nil {
+		reason := fmt.Sprintf("Invalid profile: %v", err)
+		reasonMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg(reason),
+		}
+		return reasonMessage, nil
+	}
+
+	avatarMime := ""
+	if len(update.Avatar) > 0 {
+		mime, err := validateAvatar(update.Avatar)
+		if err != nil {
+			reason := fmt.Sprintf("Invalid avatar: %v", err)
+			reasonMessage := &packets.Message{
+				Type: packets.NewDenyResponseMsg(reason),
+			}
+			return reasonMessage, nil
+		}
+		avatarMime = mime
+	}
+
+	err = queries.UpsertProfile(c, db.UpsertProfileParams{
+		UserID:      userId,
+		DisplayName: displayName,
+		Bio:         bio,
+		Status:      status,
+	})
+	if err != nil {
+		reason := fmt.Sprintf("error saving profile: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	if len(update.Avatar) > 0 || update.RemoveAvatar {
+		avatar := update.Avatar
+		if update.RemoveAvatar {
+			avatar, avatarMime = nil, ""
+		}
+
+		err = queries.SetProfileAvatar(c, db.SetProfileAvatarParams{
+			Avatar:     avatar,
+			AvatarMime: avatarMime,
+			UserID:     userId,
+		})
+		if err != nil {
+			reason := fmt.Sprintf("error saving avatar: %v", err)
+			return nil, errors.New(reason)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		reason := fmt.Sprintf("error saving profile: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	profile, err := s.getProfile(c, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.hub.PropagateProfile(profile)
+
+	profileMessage := &packets.Message{
+		Type: packets.NewProfileMsg(profile),
+	}
+	return profileMessage, nil
+}
+
+// Returns the avatar image of the user and its MIME type
+func (s *Service) GetAvatar(c context.Context, userId string) ([]byte, string, error) {
+	avatar, err := s.repo.GetProfileAvatar(c, userId)
+	if err != nil {
+		return nil, "", err
+	}
+	return avatar.Avatar, avatar.AvatarMime, nil
+}
+
+func (s *Service) getProfile(c context.Context, userId string) (*packets.ProfileMessage, error) {
+	profile, err := s.repo.GetProfile(c, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Users that never edited their profile have an empty one
+			return packets.NewProfile(userId, "", "", "", false, 0), nil
+		}
+		reason := fmt.Sprintf("error getting profile: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	return packets.NewProfile(profile.UserID, profile.DisplayName, profile.Bio, profile.Status, profile.AvatarMime != "", profile.Version), nil
+}
+
+func validateProfileText(bio string, status string) error {
+	if utf8.RuneCountInString(bio) > maxBioChars {
+		return errors.New("bio too long")
+	}
+	if utf8.RuneCountInString(status) > maxStatusChars {
+		return errors.New("status too long")
+	}
+	if strings.ContainsAny(status, "\r\n\t") {
+		return errors.New("status must be a single line")
+	}
+	return nil
+}
+
+// Returns the MIME type of the avatar if it is an accepted image
+func validateAvatar(avatar []byte) (string, error) {
+	if len(avatar) > maxAvatarBytes {
+		return "", errors.New("too large")
+	}
+
+	mime := http.DetectContentType(avatar)
+	if !allowedAvatarMimes[mime] {
+		reason := fmt.Sprintf("unsupported type %s", mime)
+		return "", errors.New(reason)
+	}
+
+	return mime, nil
+}
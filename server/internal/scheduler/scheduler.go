@@ -0,0 +1,154 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// A task that runs periodically. Run returns how many items it processed
+type Job struct {
+	Name     string
+	Interval time.Duration
+
+	// Random delay added to every interval so jobs don't run in lockstep
+	Jitter time.Duration
+
+	Run func(c context.Context) (int64, error)
+}
+
+// Counters about the runs of a job
+type JobMetrics struct {
+	Name         string
+	Runs         uint64
+	Failures     uint64
+	Processed    int64
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    string
+}
+
+// Runs jobs in the background, each one on its own goroutine
+type Scheduler struct {
+	jobs    []Job
+	metrics map[string]*JobMetrics
+	logger  *log.Logger
+	sync.Mutex
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		metrics: make(map[string]*JobMetrics),
+		logger:  log.New(log.Writer(), "Scheduler: ", log.LstdFlags),
+	}
+}
+
+// Registers a job. Jobs must be added before calling Run
+func (s *Scheduler) Add(job Job) {
+	s.Lock()
+	defer s.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	s.metrics[job.Name] = &JobMetrics{Name: job.Name}
+}
+
+// Starts every job and blocks until the context is cancelled
+func (s *Scheduler) Run(c context.Context) {
+	s.Lock()
+	jobs := append([]Job(nil), s.jobs...)
+	s.Unlock()
+
+	s.logger.Printf("Starting %d jobs", len(jobs))
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(c, job)
+		}()
+	}
+	wg.Wait()
+}
+
+// Returns a copy of the metrics of every job
+func (s *Scheduler) Metrics() []JobMetrics {
+	s.Lock()
+	defer s.Unlock()
+
+	metrics := make([]JobMetrics, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		metrics = append(metrics, *s.metrics[job.Name])
+	}
+	return metrics
+}
+
+// Logs the metrics of every job that has run, so they can be followed without
+// a debugger. Returns how many jobs were reported
+func (s *Scheduler) LogMetrics() int64 {
+	reported := int64(0)
+	for _, metrics := range s.Metrics() {
+		if metrics.Runs == 0 {
+			continue
+		}
+
+		s.logger.Printf("Job %s ran %d times, failed %d, processed %d items, last run at %s took %v",
+			metrics.Name, metrics.Runs, metrics.Failures, metrics.Processed, metrics.LastRun.Format(time.RFC3339), metrics.LastDuration)
+		if metrics.LastError != "" {
+			s.logger.Printf("Job %s failed on its last run: %s", metrics.Name, metrics.LastError)
+		}
+		reported++
+	}
+	return reported
+}
+
+func (s *Scheduler) loop(c context.Context, job Job) {
+	timer := time.NewTimer(nextDelay(job))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.Done():
+			return
+		case <-timer.C:
+			s.runOnce(c, job)
+			timer.Reset(nextDelay(job))
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(c context.Context, job Job) {
+	start := time.Now()
+	processed, err := job.Run(c)
+	duration := time.Since(start)
+
+	s.Lock()
+	metrics := s.metrics[job.Name]
+	metrics.Runs++
+	metrics.Processed += processed
+	metrics.LastRun = start
+	metrics.LastDuration = duration
+	metrics.LastError = ""
+	if err != nil {
+		metrics.Failures++
+		metrics.LastError = err.Error()
+	}
+	s.Unlock()
+
+	if err != nil {
+		s.logger.Printf("Job %s failed after %v: %v", job.Name, duration, err)
+		return
+	}
+	if processed > 0 {
+		s.logger.Printf("Job %s processed %d items in %v", job.Name, processed, duration)
+	}
+}
+
+func nextDelay(job Job) time.Duration {
+	if job.Jitter <= 0 {
+		return job.Interval
+	}
+	return job.Interval + rand.N(job.Jitter)
+}
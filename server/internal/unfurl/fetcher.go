@@ -0,0 +1,170 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+var (
+	unfurlTimeout  = flag.Duration("unfurl-timeout", 5*time.Second, "How long fetching the page behind a link can take, redirects included")
+	maxUnfurlBytes = flag.Int64("max-unfurl-bytes", 1024*1024, "How much of a linked page is read looking for its metadata, in bytes")
+
+	maxRedirects = 5
+	userAgent    = "ChatLinkPreview/1.0"
+
+	// Ranges that aren't reachable on the public internet, on top of the ones
+	// netip already knows about. Links can't be used to probe the server's network
+	blockedPrefixes = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+		netip.MustParsePrefix("64:ff9b::/96"),
+		netip.MustParsePrefix("2002::/16"),
+	}
+)
+
+// What a link points to, as the page describes itself
+type Preview struct {
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// Gets the preview of a link. Implementations are used from many goroutines at once
+type Fetcher interface {
+	Fetch(ctx context.Context, link string) (Preview, error)
+}
+
+// Fetches previews over HTTP from public addresses only
+type HTTPFetcher struct {
+	client *http.Client
+}
+
+func NewHTTPFetcher() *HTTPFetcher {
+	dialer := &net.Dialer{
+		Timeout: *unfurlTimeout,
+		// Checked on the resolved address, so DNS can't point a public name at a private one
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublic(ip) {
+				reason := fmt.Sprintf("address %s is not public", ip)
+				return errors.New(reason)
+			}
+			return nil
+		},
+	}
+
+	return &HTTPFetcher{
+		client: &http.Client{
+			Timeout: *unfurlTimeout,
+			Transport: &http.Transport{
+				// A proxy would make the connections, bypassing the address check
+				Proxy:                 nil,
+				DialContext:           dialer.DialContext,
+				TLSHandshakeTimeout:   *unfurlTimeout,
+				ResponseHeaderTimeout: *unfurlTimeout,
+				MaxIdleConns:          10,
+				IdleConnTimeout:       30 * time.Second,
+			},
+			CheckRedirect: func(request *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return errors.New("too many redirects")
+				}
+				return checkLink(request.URL)
+			},
+		},
+	}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, link string) (Preview, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return Preview{}, err
+	}
+	if err := checkLink(parsed); err != nil {
+		return Preview{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return Preview{}, err
+	}
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	response, err := f.client.Do(request)
+	if err != nil {
+		return Preview{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		reason := fmt.Sprintf("unexpected status %s", response.Status)
+		return Preview{}, errors.New(reason)
+	}
+	contentType := response.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || (mediaType != "text/html" && mediaType != "application/xhtml+xml") {
+		reason := fmt.Sprintf("unexpected content type %q", contentType)
+		return Preview{}, errors.New(reason)
+	}
+
+	body, err := charset.NewReader(io.LimitReader(response.Body, *maxUnfurlBytes), contentType)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	// Relative image links are resolved against the page the redirects ended on
+	preview := parsePreview(body, response.Request.URL)
+	preview.URL = link
+	return preview, nil
+}
+
+// Only plain http and https links to a host, without credentials, are fetched
+func checkLink(link *url.URL) error {
+	if link.Scheme != "http" && link.Scheme != "https" {
+		reason := fmt.Sprintf("scheme %q is not allowed", link.Scheme)
+		return errors.New(reason)
+	}
+	if link.Hostname() == "" || link.User != nil {
+		return errors.New("invalid host")
+	}
+	return nil
+}
+
+func isPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package unfurl
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	maxLinksPerMessage = 3
+	maxLinkChars       = 2048
+
+	linkPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+)
+
+// Returns the http and https links written in the message, in order and without
+// repeats. Punctuation ending a sentence isn't part of the link
+func FindLinks(msg string) []string {
+	links := []string{}
+	for _, link := range linkPattern.FindAllString(msg, -1) {
+		link = trimLink(link)
+		if len(link) > maxLinkChars || slices.Contains(links, link) {
+			continue
+		}
+		links = append(links, link)
+		if len(links) == maxLinksPerMessage {
+			break
+		}
+	}
+	return links
+}
+
+func trimLink(link string) string {
+	for link != "" {
+		last := link[len(link)-1]
+		switch {
+		case strings.IndexByte(".,:;!?'*", last) >= 0:
+		case last == ')' && strings.Count(link, "(") < strings.Count(link, ")"):
+		case last == ']' && strings.Count(link, "[") < strings.Count(link, "]"):
+		default:
+			return link
+		}
+		link = link[:len(link)-1]
+	}
+	return link
+}
@@ -0,0 +1,36 @@
+package unfurl
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindLinks(t *testing.T) {
+	long := "https://example.com/" + strings.Repeat("a", maxLinkChars)
+	tests := []struct {
+		name string
+		msg  string
+		want []string
+	}{
+		{"no links", "nothing to see here", []string{}},
+		{"one link", "see https://example.com/page", []string{"https://example.com/page"}},
+		{"http and case", "HTTP://Example.com and http://b.org", []string{"HTTP://Example.com", "http://b.org"}},
+		{"other schemes", "ftp://example.com javascript:alert(1) www.example.com", []string{}},
+		{"sentence punctuation", "go to https://example.com/a. Or https://b.org/c?!", []string{"https://example.com/a", "https://b.org/c"}},
+		{"balanced parentheses kept", "https://en.wikipedia.org/wiki/Go_(language)", []string{"https://en.wikipedia.org/wiki/Go_(language)"}},
+		{"closing parenthesis trimmed", "(see https://example.com/page)", []string{"https://example.com/page"}},
+		{"angle brackets and quotes", `<https://example.com/a> "https://b.org"`, []string{"https://example.com/a", "https://b.org"}},
+		{"repeats", "https://example.com https://example.com.", []string{"https://example.com"}},
+		{"at most three", "http://a.com http://b.com http://c.com http://d.com", []string{"http://a.com", "http://b.com", "http://c.com"}},
+		{"too long", long + " https://example.com", []string{"https://example.com"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := FindLinks(test.msg); !slices.Equal(got, test.want) {
+				t.Errorf("FindLinks(%q) = %q, want %q", test.msg, got, test.want)
+			}
+		})
+	}
+}
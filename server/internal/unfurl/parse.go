@@ -0,0 +1,142 @@
+package unfurl
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	maxTitleChars       = 300
+	maxDescriptionChars = 1000
+	maxSiteNameChars    = 100
+	maxImageURLChars    = 2048
+)
+
+// Reads the OpenGraph metadata of the page, falling back to its title and
+// description tags. Only the head is read
+func parsePreview(page io.Reader, pageURL *url.URL) Preview {
+	var preview Preview
+	var title, description string
+
+	tokenizer := html.NewTokenizer(page)
+	inTitle := false
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finishPreview(preview, title, description, pageURL)
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				return finishPreview(preview, title, description, pageURL)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttributes := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Body:
+				return finishPreview(preview, title, description, pageURL)
+			case atom.Title:
+				inTitle = true
+			case atom.Meta:
+				if !hasAttributes {
+					continue
+				}
+				key, content := metaAttributes(tokenizer)
+				switch key {
+				case "og:title", "twitter:title":
+					if preview.Title == "" {
+						preview.Title = content
+					}
+				case "og:description", "twitter:description":
+					if preview.Description == "" {
+						preview.Description = content
+					}
+				case "description":
+					description = content
+				case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "og:site_name":
+					preview.SiteName = content
+				}
+			}
+		}
+	}
+}
+
+// Returns what the meta tag describes, from its property or name, and its content
+func metaAttributes(tokenizer *html.Tokenizer) (string, string) {
+	var key, content string
+	for {
+		name, value, more := tokenizer.TagAttr()
+		switch string(name) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(string(value))
+			}
+		case "content":
+			content = string(value)
+		}
+		if !more {
+			return key, content
+		}
+	}
+}
+
+func finishPreview(preview Preview, title string, description string, pageURL *url.URL) Preview {
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	if preview.Description == "" {
+		preview.Description = description
+	}
+
+	preview.Title = cleanText(preview.Title, maxTitleChars)
+	preview.Description = cleanText(preview.Description, maxDescriptionChars)
+	preview.SiteName = cleanText(preview.SiteName, maxSiteNameChars)
+	preview.ImageURL = resolveImage(preview.ImageURL, pageURL)
+	return preview
+}
+
+// Collapses whitespace and cuts the text to at most limit characters
+func cleanText(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "")
+	}
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// Makes the image link absolute. Images that aren't plain http or https are dropped
+func resolveImage(image string, pageURL *url.URL) string {
+	image = strings.TrimSpace(image)
+	if image == "" || len(image) > maxImageURLChars {
+		return ""
+	}
+	parsed, err := url.Parse(image)
+	if err != nil {
+		return ""
+	}
+
+	resolved := pageURL.ResolveReference(parsed)
+	if checkLink(resolved) != nil {
+		return ""
+	}
+	return resolved.String()
+}
@@ -0,0 +1,32 @@
+package unfurl
+
+import (
+	"context"
+	"database/sql"
+	"server/internal/db"
+	"time"
+)
+
+type Repository struct {
+	dbPool  *sql.DB
+	queries *db.Queries
+}
+
+func NewRepository(dbPool *sql.DB) Repository {
+	return Repository{
+		dbPool:  dbPool,
+		queries: db.New(dbPool),
+	}
+}
+
+func (r *Repository) GetLinkPreview(ctx context.Context, url string) (db.LinkPreview, error) {
+	return r.queries.GetLinkPreview(ctx, url)
+}
+
+func (r *Repository) UpsertLinkPreview(ctx context.Context, params db.UpsertLinkPreviewParams) (db.LinkPreview, error) {
+	return r.queries.UpsertLinkPreview(ctx, params)
+}
+
+func (r *Repository) DeleteOldLinkPreviews(ctx context.Context, fetchedBefore time.Time) (int64, error) {
+	return r.queries.DeleteOldLinkPreviews(ctx, fetchedBefore)
+}
@@ -0,0 +1,107 @@
+package unfurl
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"log"
+	"server/internal/db"
+	"time"
+)
+
+var (
+	linkPreviewTTL        = flag.Duration("link-preview-ttl", 24*time.Hour, "How long link previews are cached before their pages are fetched again")
+	linkPreviewFailureTTL = flag.Duration("link-preview-failure-ttl", 10*time.Minute, "How long a link that couldn't be previewed waits before its page is fetched again")
+	maxConcurrentUnfurls  = flag.Int("max-concurrent-unfurls", 8, "How many linked pages can be fetched at the same time")
+)
+
+type Service struct {
+	repo    Repository
+	fetcher Fetcher
+
+	// Limits how many pages are fetched at once, whatever the number of messages
+	slots chan struct{}
+}
+
+func NewService(repository Repository, fetcher Fetcher) Service {
+	return Service{
+		repo:    repository,
+		fetcher: fetcher,
+		slots:   make(chan struct{}, max(1, *maxConcurrentUnfurls)),
+	}
+}
+
+// Returns the previews of the links, in the same order. Links fetched recently
+// come from the cache, and links that can't be previewed are left out
+func (s *Service) Previews(c context.Context, links []string) []Preview {
+	previews := make([]Preview, 0, len(links))
+	for _, link := range links {
+		cached, err := s.preview(c, link)
+		if err != nil {
+			log.Printf("Error getting preview of %s: %v", link, err)
+			continue
+		}
+		if cached.Title == "" && cached.Description == "" {
+			continue
+		}
+		previews = append(previews, Preview{
+			URL:         cached.Url,
+			Title:       cached.Title,
+			Description: cached.Description,
+			ImageURL:    cached.ImageUrl,
+			SiteName:    cached.SiteName,
+		})
+	}
+	return previews
+}
+
+func (s *Service) preview(c context.Context, link string) (db.LinkPreview, error) {
+	cached, err := s.repo.GetLinkPreview(c, link)
+	if err == nil && time.Since(cached.FetchedAt) < cacheTTL(cached) {
+		return cached, nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return db.LinkPreview{}, err
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-c.Done():
+		return db.LinkPreview{}, c.Err()
+	}
+	preview, err := s.fetcher.Fetch(c, link)
+	<-s.slots
+	if err != nil {
+		if c.Err() != nil {
+			// Cut short, the page itself may be fine
+			return db.LinkPreview{}, err
+		}
+		// Cached empty, so a broken link isn't fetched for every message with it
+		log.Printf("Unable to unfurl %s: %v", link, err)
+		preview = Preview{}
+	}
+
+	return s.repo.UpsertLinkPreview(c, db.UpsertLinkPreviewParams{
+		Url:         link,
+		Title:       preview.Title,
+		Description: preview.Description,
+		ImageUrl:    preview.ImageURL,
+		SiteName:    preview.SiteName,
+		FetchedAt:   time.Now().UTC(),
+	})
+}
+
+// Failed fetches are cached empty, and tried again sooner than real previews
+func cacheTTL(cached db.LinkPreview) time.Duration {
+	if cached.Title == "" && cached.Description == "" {
+		return min(*linkPreviewFailureTTL, *linkPreviewTTL)
+	}
+	return *linkPreviewTTL
+}
+
+// Deletes the cached previews older than their TTL and returns how many. The
+// previews already shown with messages are kept
+func (s *Service) RemoveOldPreviews(c context.Context) (int64, error) {
+	return s.repo.DeleteOldLinkPreviews(c, time.Now().Add(-*linkPreviewTTL).UTC())
+}
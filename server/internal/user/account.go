@@ -0,0 +1,541 @@
+package user
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"mime"
+	"server/internal/db"
+	"server/internal/ws"
+	"server/pkg/packets"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	deletedMessagesPolicy = flag.String("deleted-messages", "anonymize", "What happens to messages of deleted accounts: anonymize or delete")
+	deletedRoomsPolicy    = flag.String("deleted-rooms", "transfer", "What happens to rooms owned by deleted accounts: transfer to a moderator or the oldest member, or delete")
+
+	deletedUsername = "Deleted user"
+)
+
+type exportedAccount struct {
+	Id        string    `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportedProfile struct {
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	Status      string    `json:"status"`
+	Avatar      string    `json:"avatar,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type exportedSession struct {
+	Id        string     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpireAt  time.Time  `json:"expire_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type exportedRoom struct {
+	Id   uint64 `json:"id"`
+	Name string `json:"name"`
+}
+
+type exportedMessage struct {
+	Id        int64      `json:"id"`
+	RoomId    int64      `json:"room_id"`
+	ParentId  *int64     `json:"parent_id,omitempty"`
+	Timestamp time.Time  `json:"timestamp"`
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	Msg       string     `json:"msg"`
+}
+
+type exportedAttachment struct {
+	Id        string    `json:"id"`
+	MessageId *int64    `json:"message_id,omitempty"`
+	Filename  string    `json:"filename"`
+	Mime      string    `json:"mime"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportedReaction struct {
+	MessageId int64     `json:"message_id"`
+	Emoji     string    `json:"emoji"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportedReadPosition struct {
+	RoomId     int64     `json:"room_id"`
+	LastReadId int64     `json:"last_read_id"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type exportedDirectMessage struct {
+	ConversationId int64     `json:"conversation_id"`
+	Timestamp      time.Time `json:"timestamp"`
+	Msg            string    `json:"msg"`
+}
+
+// Builds a zip archive with everything the server knows about the user
+func (s *Service) ExportData(c context.Context, userId string) ([]byte, error) {
+	user, err := s.repo.queries.GetUserById(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error getting user: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	archive := &bytes.Buffer{}
+	zipWriter := zip.NewWriter(archive)
+
+	files := map[string]any{
+		"account.json": exportedAccount{
+			Id:        user.ID,
+			Username:  user.Username,
+			CreatedAt: user.CreatedAt,
+		},
+	}
+
+	profile, err := s.repo.queries.GetProfile(c, userId)
+	switch {
+	case err == nil:
+		exported := exportedProfile{
+			DisplayName: profile.DisplayName,
+			Bio:         profile.Bio,
+			Status:      profile.Status,
+			UpdatedAt:   profile.UpdatedAt,
+		}
+
+		avatar, err := s.repo.queries.GetProfileAvatar(c, userId)
+		if err == nil {
+			exported.Avatar = "avatar" + extensionForMime(avatar.AvatarMime)
+			if err := writeZipFile(zipWriter, exported.Avatar, avatar.Avatar); err != nil {
+				return nil, err
+			}
+		}
+
+		files["profile.json"] = exported
+	case !errors.Is(err, sql.ErrNoRows):
+		reason := fmt.Sprintf("error getting profile: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	tokens, err := s.repo.queries.ListActiveTokensForUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing sessions: %v", err)
+		return nil, errors.New(reason)
+	}
+	sessions := make([]exportedSession, 0, len(tokens))
+	for _, token := range tokens {
+		session := exportedSession{
+			Id:        token.Jti,
+			CreatedAt: token.CreatedAt,
+			ExpireAt:  token.ExpireAt,
+		}
+		if token.RevokedAt.Valid {
+			session.RevokedAt = &token.RevokedAt.Time
+		}
+		sessions = append(sessions, session)
+	}
+	files["sessions.json"] = sessions
+
+	rooms := []exportedRoom{}
+	s.hub.Rooms.ForEach(func(roomId uint64, room ws.Room) {
+		if room.OwnerId == userId {
+			rooms = append(rooms, exportedRoom{Id: roomId, Name: room.Name})
+		}
+	})
+	files["rooms_owned.json"] = rooms
+
+	sentMessages, err := s.repo.queries.ListMessagesBySender(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing messages: %v", err)
+		return nil, errors.New(reason)
+	}
+	messages := make([]exportedMessage, 0, len(sentMessages))
+	for _, message := range sentMessages {
+		exported := exportedMessage{
+			Id:        message.ID,
+			RoomId:    message.RoomID,
+			Timestamp: message.CreatedAt,
+			Msg:       message.Msg,
+		}
+		if message.ParentID.Valid {
+			exported.ParentId = &message.ParentID.Int64
+		}
+		if message.EditedAt.Valid {
+			exported.EditedAt = &message.EditedAt.Time
+		}
+		messages = append(messages, exported)
+	}
+	files["messages.json"] = messages
+
+	uploads, err := s.repo.queries.ListAttachmentsByUploader(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing attachments: %v", err)
+		return nil, errors.New(reason)
+	}
+	attachments := make([]exportedAttachment, 0, len(uploads))
+	for _, upload := range uploads {
+		exported := exportedAttachment{
+			Id:        upload.ID,
+			Filename:  upload.Filename,
+			Mime:      upload.Mime,
+			Size:      upload.Size,
+			CreatedAt: upload.CreatedAt,
+		}
+		if upload.MessageID.Valid {
+			exported.MessageId = &upload.MessageID.Int64
+		}
+		attachments = append(attachments, exported)
+	}
+	files["attachments.json"] = attachments
+
+	userReactions, err := s.repo.queries.ListReactionsByUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing reactions: %v", err)
+		return nil, errors.New(reason)
+	}
+	reactions := make([]exportedReaction, 0, len(userReactions))
+	for _, reaction := range userReactions {
+		reactions = append(reactions, exportedReaction{
+			MessageId: reaction.MessageID,
+			Emoji:     reaction.Emoji,
+			CreatedAt: reaction.CreatedAt,
+		})
+	}
+	files["reactions.json"] = reactions
+
+	reads, err := s.repo.queries.ListRoomReadsForUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing read positions: %v", err)
+		return nil, errors.New(reason)
+	}
+	readPositions := make([]exportedReadPosition, 0, len(reads))
+	for _, read := range reads {
+		readPositions = append(readPositions, exportedReadPosition{
+			RoomId:     read.RoomID,
+			LastReadId: read.LastReadID,
+			UpdatedAt:  read.UpdatedAt,
+		})
+	}
+	files["read_positions.json"] = readPositions
+
+	sentDirectMessages, err := s.repo.queries.ListDirectMessagesBySender(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing direct messages: %v", err)
+		return nil, errors.New(reason)
+	}
+	directMessages := make([]exportedDirectMessage, 0, len(sentDirectMessages))
+	for _, dm := range sentDirectMessages {
+		directMessages = append(directMessages, exportedDirectMessage{
+			ConversationId: dm.ConversationID,
+			Timestamp:      dm.CreatedAt,
+			Msg:            dm.Msg,
+		})
+	}
+	files["direct_messages.json"] = directMessages
+
+	for name, content := range files {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			reason := fmt.Sprintf("error encoding %s: %v", name, err)
+			return nil, errors.New(reason)
+		}
+		if err := writeZipFile(zipWriter, name, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		reason := fmt.Sprintf("error closing archive: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	return archive.Bytes(), nil
+}
+
+// Deletes the account after confirming the password. Sessions are revoked,
+// open connections are closed, and the user's messages and owned rooms are
+// handled according to the configured policies
+func (s *Service) DeleteAccount(c context.Context, userId string, password string) (*packets.Message, error) {
+	user, err := s.repo.queries.GetUserById(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error getting user: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if err != nil {
+		log.Printf("Incorrect password when deleting user %s", user.Username)
+		reasonMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg("Incorrect password"),
+		}
+		return reasonMessage, nil
+	}
+
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		reason := fmt.Sprintf("error starting transaction: %v", err)
+		return nil, errors.New(reason)
+	}
+	defer tx.Rollback()
+
+	queries := s.repo.queries.WithTx(tx)
+
+	transfers, purged, err := applyRoomPolicy(c, queries, userId)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMessagePolicy(c, queries, userId); err != nil {
+		return nil, err
+	}
+	if err := queries.DeleteReactionsByUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting reactions: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteMentionsForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting mentions: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeletePresence(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting presence: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteRoomReadsForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting read positions: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteRoomMutesForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting room mutes: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteRoomMembershipsForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting room memberships: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteRoomInvitesForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting room invites: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteInviteCodeUsesForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting invite code uses: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteInviteCodesByCreator(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting invite codes: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.RemoveUserFromConversations(c, userId); err != nil {
+		reason := fmt.Sprintf("error leaving conversations: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteProfile(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting profile: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteTokensForUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting tokens: %v", err)
+		return nil, errors.New(reason)
+	}
+	if err := queries.DeleteUser(c, userId); err != nil {
+		reason := fmt.Sprintf("error deleting user: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	if err := tx.Commit(); err != nil {
+		reason := fmt.Sprintf("error committing account deletion: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	log.Printf("Deleted account of user %s", userId)
+	s.removeUserFromRooms(userId, transfers, purged)
+
+	okMessage := &packets.Message{
+		Type: packets.NewOkResponseMsg(),
+	}
+	return okMessage, nil
+}
+
+func applyMessagePolicy(c context.Context, queries *db.Queries, userId string) error {
+	// Either way the logged events still show what the user sent, so resuming
+	// clients get the recent messages again instead
+	err := queries.DeleteRoomEventsByUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error removing room events: %v", err)
+		return errors.New(reason)
+	}
+
+	if *deletedMessagesPolicy == "delete" {
+		err = queries.DeleteMessageEditsBySender(c, db.DeleteMessageEditsBySenderParams{
+			EditedBy: userId,
+			SenderID: userId,
+		})
+		if err != nil {
+			reason := fmt.Sprintf("error removing message edits: %v", err)
+			return errors.New(reason)
+		}
+		if err := queries.DeleteLinkPreviewsBySender(c, userId); err != nil {
+			reason := fmt.Sprintf("error removing link previews: %v", err)
+			return errors.New(reason)
+		}
+		if err := queries.DeleteReactionsOnMessagesBySender(c, userId); err != nil {
+			reason := fmt.Sprintf("error removing reactions to messages: %v", err)
+			return errors.New(reason)
+		}
+		if err := queries.DeleteMentionsOnMessagesBySender(c, userId); err != nil {
+			reason := fmt.Sprintf("error removing mentions in messages: %v", err)
+			return errors.New(reason)
+		}
+		err = queries.DeleteMessagesBySender(c, db.DeleteMessagesBySenderParams{
+			SenderUsername: deletedUsername,
+			SenderID:       userId,
+		})
+		if err != nil {
+			reason := fmt.Sprintf("error removing messages: %v", err)
+			return errors.New(reason)
+		}
+		if err := queries.DeleteDirectMessagesBySender(c, userId); err != nil {
+			reason := fmt.Sprintf("error removing direct messages: %v", err)
+			return errors.New(reason)
+		}
+	} else {
+		err = queries.AnonymizeMessagesBySender(c, db.AnonymizeMessagesBySenderParams{
+			SenderUsername: deletedUsername,
+			SenderID:       userId,
+		})
+		if err != nil {
+			reason := fmt.Sprintf("error anonymizing messages: %v", err)
+			return errors.New(reason)
+		}
+		if err := queries.AnonymizeDirectMessagesBySender(c, userId); err != nil {
+			reason := fmt.Sprintf("error anonymizing direct messages: %v", err)
+			return errors.New(reason)
+		}
+	}
+
+	if err := queries.AnonymizeAttachmentsByUploader(c, userId); err != nil {
+		reason := fmt.Sprintf("error anonymizing attachments: %v", err)
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// Transfers or deletes the rooms the user owns. Returns the new owner of each
+// transferred room and the ids of the deleted ones
+func applyRoomPolicy(c context.Context, queries *db.Queries, userId string) (map[uint64]string, []uint64, error) {
+	roomIds, err := queries.ListRoomIdsByOwner(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing owned rooms: %v", err)
+		return nil, nil, errors.New(reason)
+	}
+
+	transfers := make(map[uint64]string)
+	purged := []uint64{}
+	for _, roomId := range roomIds {
+		newOwner := ""
+		if *deletedRoomsPolicy == "transfer" {
+			newOwner, err = queries.GetRoomSuccessor(c, db.GetRoomSuccessorParams{
+				RoomID:  roomId,
+				OwnerID: userId,
+			})
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				reason := fmt.Sprintf("error finding a new owner for room %d: %v", roomId, err)
+				return nil, nil, errors.New(reason)
+			}
+		}
+
+		if newOwner != "" {
+			err := queries.UpdateRoomOwner(c, db.UpdateRoomOwnerParams{
+				OwnerID: newOwner,
+				ID:      roomId,
+			})
+			if err != nil {
+				reason := fmt.Sprintf("error transferring room %d: %v", roomId, err)
+				return nil, nil, errors.New(reason)
+			}
+			transfers[uint64(roomId)] = newOwner
+			continue
+		}
+
+		if _, err := queries.PurgeRoom(c, roomId); err != nil {
+			reason := fmt.Sprintf("error deleting room %d: %v", roomId, err)
+			return nil, nil, errors.New(reason)
+		}
+		purged = append(purged, uint64(roomId))
+	}
+	return transfers, purged, nil
+}
+
+// Brings the hub in line with the deleted account: disconnects the user
+// everywhere, applies the message policy to the recent messages and hands over
+// or removes the rooms the user owned
+func (s *Service) removeUserFromRooms(userId string, transfers map[uint64]string, purged []uint64) {
+	for _, roomId := range purged {
+		s.hub.RemoveRoom(roomId)
+		log.Printf("Deleted room %d", roomId)
+	}
+
+	s.hub.DisconnectUser(userId, "Account deleted")
+
+	s.hub.Rooms.ForEach(func(roomId uint64, room ws.Room) {
+		room.LastMessages.ForEach(func(id uint64, sm ws.StoragedMessage) {
+			if sm.SenderUserId != userId {
+				return
+			}
+
+			if *deletedMessagesPolicy == "delete" {
+				room.LastMessages.Remove(id)
+				return
+			}
+
+			chat := proto.Clone(sm.Msg.Chat).(*packets.ChatMessage)
+			chat.SenderUsername = deletedUsername
+			chat.SenderUserId = ""
+			sm.Msg = &packets.Packet_Chat{Chat: chat}
+			sm.SenderUserId = ""
+			sm.SenderUsername = deletedUsername
+			room.LastMessages.Set(id, sm)
+		})
+
+		if newOwner, found := transfers[roomId]; found {
+			s.hub.Rooms.Update(roomId, func(room *ws.Room) {
+				room.OwnerId = newOwner
+			})
+			log.Printf("Transferred room %d to user %s", roomId, newOwner)
+		}
+	})
+}
+
+func writeZipFile(zipWriter *zip.Writer, name string, data []byte) error {
+	file, err := zipWriter.Create(name)
+	if err != nil {
+		reason := fmt.Sprintf("error adding %s to archive: %v", name, err)
+		return errors.New(reason)
+	}
+	if _, err := file.Write(data); err != nil {
+		reason := fmt.Sprintf("error writing %s to archive: %v", name, err)
+		return errors.New(reason)
+	}
+	return nil
+}
+
+func extensionForMime(mimeType string) string {
+	extensions, err := mime.ExtensionsByType(mimeType)
+	if err != nil || len(extensions) == 0 {
+		return ""
+	}
+	return extensions[0]
+}
@@ -0,0 +1,155 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/db"
+	"server/internal/usernames"
+	"server/internal/ws"
+	"server/pkg/packets"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Operations used by the admin command. They act directly on the database, so a
+// running server only notices them when it next reads the affected rows
+
+func (s *Service) ListUsers(c context.Context) ([]db.User, error) {
+	return s.repo.queries.ListUsers(c)
+}
+
+func (s *Service) GetUserByUsername(c context.Context, username string) (db.User, error) {
+	user, err := s.repo.queries.GetUserByUsername(c, usernames.Key(username))
+	if errors.Is(err, sql.ErrNoRows) {
+		reason := fmt.Sprintf("user %s not found", username)
+		return user, errors.New(reason)
+	}
+	return user, err
+}
+
+// Disabled users can't log in or use their access tokens, and have all their
+// refresh tokens revoked. Their connections are closed here when the service has
+// a hub, and otherwise by the server's next check for disabled users
+func (s *Service) SetUserDisabled(c context.Context, username string, disabled bool) error {
+	user, err := s.GetUserByUsername(c, username)
+	if err != nil {
+		return err
+	}
+
+	disabledAt := sql.NullTime{}
+	if disabled {
+		disabledAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	}
+
+	_, err = s.repo.queries.SetUserDisabled(c, db.SetUserDisabledParams{
+		DisabledAt: disabledAt,
+		ID:         user.ID,
+	})
+	if err != nil {
+		reason := fmt.Sprintf("error updating user: %v", err)
+		return errors.New(reason)
+	}
+
+	if disabled {
+		if _, err := s.repo.RevokeTokensForUser(c, user.ID); err != nil {
+			reason := fmt.Sprintf("error revoking tokens: %v", err)
+			return errors.New(reason)
+		}
+		if s.hub != nil {
+			s.hub.DisconnectUser(user.ID, "Account disabled")
+		}
+	}
+
+	return nil
+}
+
+// Sets a new password for the user and revokes their refresh tokens so every
+// session has to log in again
+func (s *Service) ResetPassword(c context.Context, username string, password string) (*packets.Message, error) {
+	user, err := s.GetUserByUsername(c, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePassword(password); err != nil {
+		reason := fmt.Sprintf("Invalid password: %v", err)
+		reasonMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg(reason),
+		}
+		return reasonMessage, nil
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		reason := fmt.Sprintf("failed to hash password: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	_, err = s.repo.queries.UpdatePassword(c, db.UpdatePasswordParams{
+		PasswordHash: string(passwordHash),
+		ID:           user.ID,
+	})
+	if err != nil {
+		reason := fmt.Sprintf("error updating password: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	if _, err := s.repo.RevokeTokensForUser(c, user.ID); err != nil {
+		reason := fmt.Sprintf("error revoking tokens: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	successMessage := &packets.Message{
+		Type: packets.NewOkResponseMsg(),
+	}
+	return successMessage, nil
+}
+
+// Lists the refresh tokens of the user, or of everyone if username is empty
+func (s *Service) ListTokens(c context.Context, username string) ([]db.RefreshToken, error) {
+	if username == "" {
+		return s.repo.queries.ListTokens(c)
+	}
+
+	user, err := s.GetUserByUsername(c, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.queries.ListActiveTokensForUser(c, user.ID)
+}
+
+func (s *Service) RevokeToken(c context.Context, jti string) error {
+	return s.repo.RevokeToken(c, jti)
+}
+
+func (s *Service) RevokeTokensForUsername(c context.Context, username string) (int64, error) {
+	user, err := s.GetUserByUsername(c, username)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.RevokeTokensForUser(c, user.ID)
+}
+
+func (s *Service) PurgeTokens(c context.Context) (int64, error) {
+	return s.repo.queries.DeleteExpiredOrRevokedTokens(c)
+}
+
+func (s *Service) ListRooms(c context.Context) ([]db.Room, error) {
+	return s.repo.queries.ListRooms(c)
+}
+
+func (s *Service) DeleteRoom(c context.Context, id int64) error {
+	rooms := ws.NewRepository(s.repo.dbPool)
+	deleted, err := rooms.PurgeRoom(c, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		reason := fmt.Sprintf("room %d not found", id)
+		return errors.New(reason)
+	}
+	return nil
+}
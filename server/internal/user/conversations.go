@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"server/internal/db"
+	"server/pkg/packets"
+	"slices"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 200
+)
+
+// Lists the direct conversations of the user, most recently active first
+func (s *Service) GetConversations(c context.Context, userId string) (*packets.Message, error) {
+	conversations, err := s.repo.queries.ListConversationsForUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error listing conversations: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	conversationMessages := make([]*packets.ConversationMessage, 0, len(conversations))
+	for _, conversation := range conversations {
+		members, err := s.repo.queries.ListConversationMembers(c, conversation.ID)
+		if err != nil {
+			reason := fmt.Sprintf("error listing conversation members: %v", err)
+			return nil, errors.New(reason)
+		}
+
+		conversationMessage := &packets.ConversationMessage{
+			Id:        uint64(conversation.ID),
+			UpdatedAt: timestamppb.New(conversation.UpdatedAt),
+		}
+		for _, member := range members {
+			conversationMessage.MemberIds = append(conversationMessage.MemberIds, member.UserID)
+			conversationMessage.MemberUsernames = append(conversationMessage.MemberUsernames, member.Username)
+		}
+
+		last, err := s.getDirectMessages(c, uint64(conversation.ID), 0, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(last) > 0 {
+			conversationMessage.LastMessage = last[0]
+		}
+
+		conversationMessages = append(conversationMessages, conversationMessage)
+	}
+
+	conversationsMessage := &packets.Message{
+		Type: packets.NewConversationsResponseMsg(conversationMessages),
+	}
+	return conversationsMessage, nil
+}
+
+// Returns a page of the conversation history, oldest first, with messages sent
+// before beforeId. A zero beforeId starts from the most recent message
+func (s *Service) GetConversationHistory(c context.Context, userId string, conversationId uint64, beforeId uint64, limit uint32) (*packets.Message, error) {
+	members, err := s.repo.queries.ListConversationMembers(c, int64(conversationId))
+	if err != nil {
+		reason := fmt.Sprintf("error listing conversation members: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	isMember := slices.ContainsFunc(members, func(member db.ListConversationMembersRow) bool {
+		return member.UserID == userId
+	})
+	if !isMember {
+		reasonMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg("Conversation not found"),
+		}
+		return reasonMessage, nil
+	}
+
+	pageSize := defaultHistoryLimit
+	if limit > 0 {
+		pageSize = min(int(limit), maxHistoryLimit)
+	}
+
+	messages, err := s.getDirectMessages(c, conversationId, beforeId, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(messages)
+
+	historyMessage := &packets.Message{
+		Type: packets.NewConversationHistoryResponseMsg(conversationId, messages),
+	}
+	return historyMessage, nil
+}
+
+// Returns the newest messages of the conversation sent before beforeId, newest first
+func (s *Service) getDirectMessages(c context.Context, conversationId uint64, beforeId uint64, limit int) ([]*packets.DirectMessage, error) {
+	if beforeId == 0 {
+		beforeId = math.MaxInt64
+	}
+
+	rows, err := s.repo.queries.ListDirectMessages(c, db.ListDirectMessagesParams{
+		ConversationID: int64(conversationId),
+		ID:             int64(beforeId),
+		Limit:          int64(limit),
+	})
+	if err != nil {
+		reason := fmt.Sprintf("error listing direct messages: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	messages := make([]*packets.DirectMessage, 0, len(rows))
+	for _, row := range rows {
+		senderUsername := deletedUsername
+		if row.SenderUsername.Valid {
+			senderUsername = row.SenderUsername.String
+		}
+		messages = append(messages, packets.NewDirectMessage(uint64(row.ID), uint64(row.ConversationID), row.SenderID, senderUsername, row.Msg, row.CreatedAt))
+	}
+	return messages, nil
+}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 	"server/internal/jwt"
-	"server/internal/ws"
 	"server/pkg/packets"
 
 	"google.golang.org/protobuf/proto"
@@ -240,7 +239,7 @@ func (h *Handler) CreateRoom(writer http.ResponseWriter, request *http.Request)
 		return
 	}
 
-	successMessage, err := h.Service.CreateRoom(accessToken.Subject, pktMessage.NewRoom.Name)
+	successMessage, err := h.Service.CreateRoom(request.Context(), accessToken.Subject, pktMessage.NewRoom.Name, pktMessage.NewRoom.Visibility)
 	if err != nil {
 		log.Printf("An error occured when trying to create a room: %v", err)
 		http.Error(writer, "An error occured", http.StatusInternalServerError)
@@ -283,25 +282,20 @@ func (h *Handler) GetRooms(writer http.ResponseWriter, request *http.Request) {
 	}
 
 	token := request.Header.Get("Authorization")
-	_, err = jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
 	if err != nil {
 		log.Printf("token revoked or expired: %v", err)
 		writer.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	rooms := make([]*packets.NewRoomResponseMessage, 0, h.Service.hub.Rooms.Len())
-	h.Service.hub.Rooms.ForEach(func(id uint64, room ws.Room) {
-		rooms = append(rooms, &packets.NewRoomResponseMessage{
-			RoomId:  id,
-			OwnerId: room.OwnerId,
-			Name:    room.Name,
-		})
-	})
-
-	roomsMessage := &packets.Message{
-		Type: packets.NewRoomsResponseMsg(rooms),
+	roomsMessage, err := h.Service.GetRooms(request.Context(), accessToken.Subject)
+	if err != nil {
+		log.Printf("Error getting rooms: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
 	}
+
 	roomsData, err := proto.Marshal(roomsMessage)
 	if err != nil {
 		log.Printf("Failed to marshal success packet: %v", err)
@@ -312,3 +306,287 @@ func (h *Handler) GetRooms(writer http.ResponseWriter, request *http.Request) {
 	writer.WriteHeader(http.StatusOK)
 	writer.Write(roomsData)
 }
+
+func (h *Handler) ExportData(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	_, ok := message.Type.(*packets.Message_ExportData)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	archive, err := h.Service.ExportData(request.Context(), accessToken.Subject)
+	if err != nil {
+		log.Printf("An error occured when trying to export user data: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/zip")
+	writer.Header().Set("Content-Disposition", `attachment; filename="go-chat-export.zip"`)
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(archive)
+}
+
+func (h *Handler) DeleteAccount(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_DeleteAccount)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	deleteRespMsg, err := h.Service.DeleteAccount(request.Context(), accessToken.Subject, pktMessage.DeleteAccount.Password)
+	if err != nil {
+		log.Printf("An error occured when trying to delete account: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(deleteRespMsg)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) GetConversations(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	_, ok := message.Type.(*packets.Message_ConversationsRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	conversationsMessage, err := h.Service.GetConversations(request.Context(), accessToken.Subject)
+	if err != nil {
+		log.Printf("An error occured when trying to list conversations: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(conversationsMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) GetMentions(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	_, ok := message.Type.(*packets.Message_MentionsRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	mentionsMessage, err := h.Service.GetMentions(request.Context(), accessToken.Subject)
+	if err != nil {
+		log.Printf("An error occured when trying to list mentions: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(mentionsMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) GetConversationHistory(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_ConversationHistoryRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	historyMessage, err := h.Service.GetConversationHistory(
+		request.Context(),
+		accessToken.Subject,
+		pktMessage.ConversationHistoryRequest.ConversationId,
+		pktMessage.ConversationHistoryRequest.BeforeId,
+		pktMessage.ConversationHistoryRequest.Limit,
+	)
+	if err != nil {
+		log.Printf("An error occured when trying to get conversation history: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(historyMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+// Rejects requests made with the access token of a disabled or deleted user.
+// Access tokens stay valid until they expire, so the account is checked on
+// every request. Requests without one are left to the handlers
+func (h *Handler) RejectDisabledUsers(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token := request.Header.Get("Authorization")
+		if token == "" {
+			// Avatars, attachments and sockets take it from the query string
+			token = request.URL.Query().Get("token")
+		}
+
+		accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+		if err != nil {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		enabled, err := h.Service.IsUserEnabled(request.Context(), accessToken.Subject)
+		if err != nil {
+			log.Printf("An error occured when trying to check user %s: %v", accessToken.Subject, err)
+			http.Error(writer, "An error occured", http.StatusInternalServerError)
+			return
+		}
+		if !enabled {
+			log.Printf("Rejected request to %s from disabled user %s", request.URL.Path, accessToken.Subject)
+			writer.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(writer, request)
+	})
+}
@@ -0,0 +1,49 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"server/internal/db"
+	"server/pkg/packets"
+)
+
+var (
+	maxUnseenMentions = 100
+)
+
+// Lists the mentions of the user they haven't read past yet, newest first
+func (s *Service) GetMentions(c context.Context, userId string) (*packets.Message, error) {
+	mentions, err := s.repo.queries.ListUnseenMentions(c, db.ListUnseenMentionsParams{
+		UserID: userId,
+		Limit:  int64(maxUnseenMentions),
+	})
+	if err != nil {
+		reason := fmt.Sprintf("error listing mentions: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	mentionMessages := make([]*packets.MentionMessage, 0, len(mentions))
+	for _, mention := range mentions {
+		roomName := ""
+		if room, found := s.hub.Rooms.Get(uint64(mention.RoomID)); found {
+			roomName = room.Name
+		}
+
+		mentionMessages = append(mentionMessages, packets.NewMention(
+			uint64(mention.ID),
+			uint64(mention.MessageID),
+			uint64(mention.RoomID),
+			roomName,
+			mention.SenderID,
+			mention.SenderUsername,
+			mention.Msg,
+			mention.CreatedAt,
+		))
+	}
+
+	mentionsMessage := &packets.Message{
+		Type: packets.NewMentionsResponseMsg(mentionMessages),
+	}
+	return mentionsMessage, nil
+}
@@ -22,8 +22,8 @@ func (r *Repository) CreateUser(ctx context.Context, user db.CreateUserParams) (
 	return r.queries.CreateUser(ctx, user)
 }
 
-func (r *Repository) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
-	return r.queries.GetUserByUsername(ctx, username)
+func (r *Repository) GetUserByUsername(ctx context.Context, usernameKey string) (db.User, error) {
+	return r.queries.GetUserByUsername(ctx, usernameKey)
 }
 
 func (r *Repository) SaveRefreshToken(ctx context.Context, params db.SaveRefreshTokenParams) error {
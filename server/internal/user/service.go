@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"server/internal/db"
 	"server/internal/jwt"
+	"server/internal/usernames"
 	"server/internal/ws"
 	"server/pkg/packets"
 	"strings"
@@ -39,7 +40,7 @@ func (s *Service) Login(c context.Context, username string, password string) (*p
 		Type: packets.NewDenyResponseMsg("Incorrect username or password"),
 	}
 
-	user, err := s.repo.queries.GetUserByUsername(c, username)
+	user, err := s.repo.queries.GetUserByUsername(c, usernames.Key(username))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("Username not found: %v", err)
@@ -56,6 +57,14 @@ func (s *Service) Login(c context.Context, username string, password string) (*p
 		return genericFailMessage, nil
 	}
 
+	if user.DisabledAt.Valid {
+		log.Printf("Disabled user %s tried to log in", username)
+		disabledMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg("Account disabled"),
+		}
+		return disabledMessage, nil
+	}
+
 	// Generate access and refresh tokens
 	accessToken, refreshToken, err := s.generateNewAccessAndRefreshTokensForUser(c, user.ID)
 	if err != nil {
@@ -71,7 +80,8 @@ func (s *Service) Login(c context.Context, username string, password string) (*p
 }
 
 func (s *Service) Register(c context.Context, username string, password string) (*packets.Message, error) {
-	err := validateUsername(username)
+	username = usernames.Normalize(username)
+	err := usernames.Validate(username)
 	if err != nil {
 		reason := fmt.Sprintf("Invalid username: %v", err)
 		reasonMessage := &packets.Message{
@@ -89,7 +99,7 @@ func (s *Service) Register(c context.Context, username string, password string)
 		return reasonMessage, nil
 	}
 
-	if _, err := s.repo.queries.GetUserByUsername(c, username); err == nil {
+	if _, err := s.repo.queries.GetUserByUsername(c, usernames.Key(username)); err == nil {
 		reasonMessage := &packets.Message{
 			Type: packets.NewDenyResponseMsg("User already exists"),
 		}
@@ -105,6 +115,7 @@ func (s *Service) Register(c context.Context, username string, password string)
 	_, err = s.repo.queries.CreateUser(c, db.CreateUserParams{
 		ID:           ksuid.New().String(),
 		Username:     username,
+		UsernameKey:  usernames.Key(username),
 		PasswordHash: string(passwordHash),
 	})
 	if err != nil {
@@ -118,6 +129,18 @@ func (s *Service) Register(c context.Context, username string, password string)
 	return successMessage, nil
 }
 
+// Whether the user still exists and isn't disabled
+func (s *Service) IsUserEnabled(c context.Context, userId string) (bool, error) {
+	user, err := s.repo.queries.GetUserById(c, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !user.DisabledAt.Valid, nil
+}
+
 func (s *Service) RefreshToken(c context.Context, jti string, userId string) (*packets.Message, error) {
 	_, err := s.repo.queries.IsRefreshTokenValid(c, db.IsRefreshTokenValidParams{
 		Jti:    jti,
@@ -128,6 +151,20 @@ func (s *Service) RefreshToken(c context.Context, jti string, userId string) (*p
 		return nil, errors.New(reason)
 	}
 
+	// A login racing with the admin disabling the user may have saved a token
+	// after they were revoked
+	enabled, err := s.IsUserEnabled(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error getting user: %v", err)
+		return nil, errors.New(reason)
+	}
+	if !enabled {
+		disabledMessage := &packets.Message{
+			Type: packets.NewDenyResponseMsg("Account disabled"),
+		}
+		return disabledMessage, nil
+	}
+
 	newAccessToken, newRefreshToken, err := s.generateNewAccessAndRefreshTokensForUser(c, userId)
 	if err != nil {
 		reason := fmt.Sprintf("error generating tokens: %v", err)
@@ -153,10 +190,29 @@ func (s *Service) Logout(c context.Context, jti string) (*packets.Message, error
 	return okMessage, nil
 }
 
-func (s *Service) CreateRoom(ownerId string, roomName string) (*packets.Message, error) {
-	id := uint64(s.hub.Rooms.Len())
-	room := ws.NewRoom(id, ownerId, roomName)
-	s.hub.Rooms.Add(*room)
+func (s *Service) CreateRoom(c context.Context, ownerId string, roomName string, visibility packets.RoomVisibility) (*packets.Message, error) {
+	if _, valid := packets.RoomVisibility_name[int32(visibility)]; !valid {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Unknown visibility")}, nil
+	}
+	roomName, err := ws.ValidateRoomName(roomName)
+	if err != nil {
+		return &packets.Message{Type: packets.NewDenyResponseMsg(err.Error())}, nil
+	}
+
+	dbRoom, err := s.repo.queries.CreateRoom(c, db.CreateRoomParams{
+		OwnerID:    ownerId,
+		Name:       roomName,
+		Visibility: int64(visibility),
+	})
+	if err != nil {
+		reason := fmt.Sprintf("failed to create room: %v", err)
+		return nil, errors.New(reason)
+	}
+
+	id := uint64(dbRoom.ID)
+	room := ws.NewRoom(id, dbRoom.OwnerID, dbRoom.Name)
+	room.Visibility = visibility
+	s.hub.Rooms.Add(*room, id)
 
 	successMessage := &packets.Message{
 		Type: packets.NewOkResponseMsg(),
@@ -165,6 +221,63 @@ func (s *Service) CreateRoom(ownerId string, roomName string) (*packets.Message,
 	return successMessage, nil
 }
 
+// Lists the public rooms and those the user is a member of, with how many
+// messages from others the user hasn't read yet
+func (s *Service) GetRooms(c context.Context, userId string) (*packets.Message, error) {
+	memberRoomIds, err := s.repo.queries.ListMemberRoomIds(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error getting room memberships: %v", err)
+		return nil, errors.New(reason)
+	}
+	member := make(map[uint64]bool, len(memberRoomIds))
+	for _, roomId := range memberRoomIds {
+		member[uint64(roomId)] = true
+	}
+
+	unreadCounts, err := s.repo.queries.ListUnreadCounts(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error counting unread messages: %v", err)
+		return nil, errors.New(reason)
+	}
+	unread := make(map[uint64]uint32, len(unreadCounts))
+	for _, count := range unreadCounts {
+		unread[uint64(count.RoomID)] = uint32(count.UnreadCount)
+	}
+
+	reads, err := s.repo.queries.ListRoomReadsForUser(c, userId)
+	if err != nil {
+		reason := fmt.Sprintf("error getting read positions: %v", err)
+		return nil, errors.New(reason)
+	}
+	lastRead := make(map[uint64]uint64, len(reads))
+	for _, read := range reads {
+		lastRead[uint64(read.RoomID)] = uint64(read.LastReadID)
+	}
+
+	rooms := make([]*packets.NewRoomResponseMessage, 0, s.hub.Rooms.Len())
+	s.hub.Rooms.ForEach(func(id uint64, room ws.Room) {
+		if room.Visibility != packets.RoomVisibility_ROOM_PUBLIC && room.OwnerId != userId && !member[id] {
+			return
+		}
+
+		rooms = append(rooms, &packets.NewRoomResponseMessage{
+			RoomId:      id,
+			OwnerId:     room.OwnerId,
+			Name:        room.Name,
+			UnreadCount: unread[id],
+			LastReadId:  lastRead[id],
+			Topic:       room.Topic,
+			Description: room.Description,
+			Visibility:  room.Visibility,
+		})
+	})
+
+	roomsMessage := &packets.Message{
+		Type: packets.NewRoomsResponseMsg(rooms),
+	}
+	return roomsMessage, nil
+}
+
 func (s *Service) GetUsernameById(c context.Context, id string) (string, error) {
 	return s.repo.queries.GetUsernameById(c, id)
 }
@@ -200,19 +313,6 @@ func (s *Service) generateNewAccessAndRefreshTokensForUser(c context.Context, us
 	return accessToken, refreshToken, nil
 }
 
-func validateUsername(username string) error {
-	if len(username) <= 0 {
-		return errors.New("empty")
-	}
-	if len(username) > 20 {
-		return errors.New("too long")
-	}
-	if username != strings.TrimSpace(username) {
-		return errors.New("leading or trailing whitespace")
-	}
-	return nil
-}
-
 func validatePassword(password string) error {
 	if len(password) < minPasswordChars {
 		return errors.New("lenght less than minimum")
@@ -0,0 +1,177 @@
+// Rules for choosing usernames and telling apart the users behind them
+package usernames
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	MaxChars            = 20
+	MaxDisplayNameChars = 32
+	reservedUsernames   = flag.String(
+		"reserved-usernames",
+		"admin,administrator,root,system,server,moderator,mod,support,staff,bot,chatbot,go-chat",
+		"Comma-separated list of usernames nobody can register",
+	)
+
+	usernameFolder = cases.Fold()
+
+	// Characters allowed besides letters and digits
+	usernameSeparators = []rune{'_', '-', '.'}
+
+	// Scripts that are commonly written together and shouldn't count as mixed
+	compatibleScripts = map[string]string{
+		"Hiragana": "Han",
+		"Katakana": "Han",
+	}
+
+	// Look-alike characters mapped to the Latin letters they imitate. Case is already
+	// folded when this is applied, so only lowercase forms are listed
+	confusables = map[rune]string{
+		// Cyrillic
+		'а': "a", 'в': "b", 'г': "r", 'е': "e", 'ё': "e", 'з': "3", 'і': "i", 'ї': "i",
+		'ј': "j", 'к': "k", 'м': "m", 'н': "h", 'о': "o", 'п': "n", 'р': "p", 'с': "c",
+		'т': "t", 'у': "y", 'х': "x", 'ѕ': "s", 'ԁ': "d", 'ԛ': "q", 'ԝ': "w", 'һ': "h",
+		'ӏ': "l", 'ь': "b",
+		// Greek
+		'α': "a", 'β': "b", 'γ': "y", 'ε': "e", 'η': "n", 'ι': "i", 'κ': "k", 'ν': "v",
+		'ο': "o", 'ρ': "p", 'τ': "t", 'υ': "u", 'χ': "x", 'ω': "w",
+		// Latin variants
+		'ı': "i", 'ɑ': "a", 'ɡ': "g", 'ɩ': "i", 'ʀ': "r", 'ꞵ': "b",
+		// Digits and multi-letter look-alikes
+		'0': "o", '1': "l", 'm': "rn",
+	}
+)
+
+// Returns the username in the form it is stored and displayed
+func Normalize(username string) string {
+	return norm.NFKC.String(username)
+}
+
+// Returns the key used to compare usernames. Two usernames with the same key
+// are considered the same user, so the key folds case and maps look-alike
+// characters to a single representative
+func Key(username string) string {
+	folded := norm.NFKC.String(usernameFolder.String(Normalize(username)))
+
+	var key strings.Builder
+	for _, r := range folded {
+		if skeleton, ok := confusables[r]; ok {
+			key.WriteString(skeleton)
+			continue
+		}
+		key.WriteRune(r)
+	}
+	return key.String()
+}
+
+func Validate(username string) error {
+	if len(username) <= 0 {
+		return errors.New("empty")
+	}
+	if username != strings.TrimSpace(username) {
+		return errors.New("leading or trailing whitespace")
+	}
+	if utf8.RuneCountInString(username) > MaxChars {
+		return errors.New("too long")
+	}
+
+	for _, r := range username {
+		if !IsAllowedRune(r) {
+			return errors.New("contains characters that are not allowed")
+		}
+	}
+
+	if hasMixedScripts(username) {
+		return errors.New("mixes characters from different alphabets")
+	}
+
+	if isReservedUsername(username) {
+		return errors.New("reserved")
+	}
+
+	return nil
+}
+
+// Returns the display name in the form it is stored. Display names are free
+// text, unlike usernames, but must fit on a single line
+func CleanDisplayName(displayName string) (string, error) {
+	displayName = strings.TrimSpace(norm.NFKC.String(displayName))
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameChars {
+		return "", errors.New("display name too long")
+	}
+	if strings.ContainsAny(displayName, "\r\n\t") {
+		return "", errors.New("display name must be a single line")
+	}
+	return displayName, nil
+}
+
+func IsAllowedRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return true
+	}
+
+	for _, separator := range usernameSeparators {
+		if r == separator {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasMixedScripts(username string) bool {
+	found := ""
+	for _, r := range username {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		script := scriptOf(r)
+		if compatible, ok := compatibleScripts[script]; ok {
+			script = compatible
+		}
+
+		if found == "" {
+			found = script
+			continue
+		}
+		if script != found {
+			return true
+		}
+	}
+	return false
+}
+
+func scriptOf(r rune) string {
+	for name, table := range unicode.Scripts {
+		if name == "Common" || name == "Inherited" {
+			continue
+		}
+		if unicode.Is(table, r) {
+			return name
+		}
+	}
+	return "Common"
+}
+
+func isReservedUsername(username string) bool {
+	key := Key(username)
+	for _, reserved := range strings.Split(*reservedUsernames, ",") {
+		reserved = strings.TrimSpace(reserved)
+		if reserved == "" {
+			continue
+		}
+		if Key(reserved) == key {
+			return true
+		}
+	}
+	return false
+}
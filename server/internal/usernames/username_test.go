@@ -0,0 +1,85 @@
+package usernames
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		want     string
+	}{
+		{"plain", "alice", "alice"},
+		{"case kept", "Alice", "Alice"},
+		{"fullwidth letters", "ａｌｉｃｅ", "alice"},
+		{"composed accent", "josé", "josé"},
+		{"ligature", "ﬁona", "fiona"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Normalize(test.username); got != test.want {
+				t.Errorf("Normalize(%q) = %q, want %q", test.username, got, test.want)
+			}
+		})
+	}
+}
+
+func TestKeyMatchesLookAlikes(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     string
+		b     string
+		equal bool
+	}{
+		{"same", "alice", "alice", true},
+		{"case", "Alice", "aLICE", true},
+		{"fullwidth", "ａｌｉｃｅ", "alice", true},
+		{"cyrillic a", "аlice", "alice", true},
+		{"cyrillic o and e", "bоbе", "bobe", true},
+		{"greek omicron", "bοb", "bob", true},
+		{"zero for o", "b0b", "bob", true},
+		{"one for l", "a1ice", "alice", true},
+		{"rn for m", "rnartin", "martin", true},
+		{"dotless i", "ıvan", "ivan", true},
+		{"different names", "alice", "alicia", false},
+		{"separators matter", "bob_smith", "bob.smith", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if equal := Key(test.a) == Key(test.b); equal != test.equal {
+				t.Errorf("Key(%q) = %q, Key(%q) = %q, equal %v, want %v", test.a, Key(test.a), test.b, Key(test.b), equal, test.equal)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		valid    bool
+	}{
+		{"plain", "alice", true},
+		{"separators", "bob_smith-2.0", true},
+		{"single script", "алиса", true},
+		{"kana with kanji", "ひら漢字", true},
+		{"empty", "", false},
+		{"leading space", " alice", false},
+		{"too long", "abcdefghijklmnopqrstu", false},
+		{"space inside", "al ice", false},
+		{"symbol", "alice!", false},
+		{"mixed scripts", "аlice", false},
+		{"reserved", "admin", false},
+		{"reserved look-alike", "Adrnin", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.username)
+			if valid := err == nil; valid != test.valid {
+				t.Errorf("Validate(%q) = %v, want valid %v", test.username, err, test.valid)
+			}
+		})
+	}
+}
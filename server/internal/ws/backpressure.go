@@ -0,0 +1,288 @@
+package ws
+
+import (
+	"flag"
+	"log"
+	"server/internal/client"
+	"server/pkg/packets"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	sendBufferSize   = flag.Int("send-buffer", 256, "How many packets can wait to be written to each client")
+	slowClientPolicy = flag.String("slow-client-policy", string(policyDropNewest), "What happens when a client's send buffer is full: drop-newest, drop-oldest, disconnect or block")
+	clientPolicies   = flag.String("client-slow-policies", "", "Comma separated policies clients may pick for their own connection with the policy parameter of /ws. Empty lets only the server pick")
+	sendTimeout      = flag.Duration("send-timeout", 500*time.Millisecond, "How long the block policy waits for room in a client's send buffer before dropping the packet")
+	broadcastTimeout = flag.Duration("broadcast-timeout", 100*time.Millisecond, "How long a broadcast waits for room in the hub before being dropped")
+)
+
+// Close code sent to clients disconnected for not reading their packets fast enough
+const CloseSlowConsumer = 4008
+
+// What happens to a packet for a client whose send buffer is full. Block waits
+// a little for room, but only for packets the client's own goroutines send it,
+// like the state of a room it joins. Packets from the hub, which can't be held
+// up by one client, are dropped instead
+type backpressurePolicy string
+
+const (
+	policyDropNewest backpressurePolicy = "drop-newest"
+	policyDropOldest backpressurePolicy = "drop-oldest"
+	policyDisconnect backpressurePolicy = "disconnect"
+	policyBlock      backpressurePolicy = "block"
+)
+
+func parseBackpressurePolicy(policy string) (backpressurePolicy, bool) {
+	switch p := backpressurePolicy(policy); p {
+	case policyDropNewest, policyDropOldest, policyDisconnect, policyBlock:
+		return p, true
+	}
+	return "", false
+}
+
+// The policy of a new connection. Clients can ask for one of the policies the
+// server config lets them pick, and get the server's own otherwise
+func connectionBackpressurePolicy(requested string) (backpressurePolicy, bool) {
+	if requested != "" {
+		policy, ok := parseBackpressurePolicy(requested)
+		if !ok || !slices.Contains(strings.Split(*clientPolicies, ","), requested) {
+			return "", false
+		}
+		return policy, true
+	}
+
+	policy, ok := parseBackpressurePolicy(*slowClientPolicy)
+	if !ok {
+		log.Printf("Unknown slow client policy %q, using %s", *slowClientPolicy, policyDropNewest)
+		return policyDropNewest, true
+	}
+	return policy, true
+}
+
+// Counts of packets lost because someone couldn't keep up, since the server started
+type dropCounters struct {
+	clientPackets    atomic.Uint64
+	broadcasts       atomic.Uint64
+	slowDisconnects  atomic.Uint64
+	lastReportedDrop atomic.Uint64
+}
+
+// Packets dropped by the hub or for clients since the server started, and how
+// many clients were disconnected for being too slow
+func (h *Hub) DroppedPackets() (clientPackets uint64, broadcasts uint64, slowDisconnects uint64) {
+	return h.drops.clientPackets.Load(), h.drops.broadcasts.Load(), h.drops.slowDisconnects.Load()
+}
+
+// Logs the drop counters when packets were dropped since the last report.
+// Returns how many were
+func (h *Hub) ReportDroppedPackets() int64 {
+	clientPackets, broadcasts, slowDisconnects := h.DroppedPackets()
+	total := clientPackets + broadcasts
+	dropped := total - h.drops.lastReportedDrop.Swap(total)
+	if dropped > 0 {
+		log.Printf("Dropped %d client packets and %d broadcasts so far, disconnected %d slow clients", clientPackets, broadcasts, slowDisconnects)
+	}
+	return int64(dropped)
+}
+
+// Called when a broadcast couldn't get into the hub. Nobody in the room got it,
+// so they are all told to resync
+func (h *Hub) broadcastDropped(packet *packets.Packet) {
+	h.drops.broadcasts.Add(1)
+	log.Printf("Broadcast channel full, dropping %T for room %d", packet.Msg, packet.RoomId)
+
+	if room, found := h.Rooms.Get(packet.RoomId); found {
+		room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+			if clientId != packet.SenderId {
+				client.MissedPacket(packet.RoomId)
+			}
+		})
+	}
+}
+
+// Packets dropped for a client that it hasn't been told about yet, keyed by room
+// id. Zero is for packets outside of any room
+type missedPackets struct {
+	mutex  sync.Mutex
+	counts map[uint64]uint32
+}
+
+func (m *missedPackets) add(roomId uint64) (first bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.counts == nil {
+		m.counts = make(map[uint64]uint32)
+	}
+	first = len(m.counts) == 0
+	m.counts[roomId]++
+	return first
+}
+
+// Returns the notice telling the client what it missed, or nil if it missed
+// nothing, and resets the counts
+func (m *missedPackets) take() packets.Pkt {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(m.counts) == 0 {
+		return nil
+	}
+
+	count := uint32(0)
+	roomIds := []uint64{}
+	for roomId, roomCount := range m.counts {
+		count += roomCount
+		if roomId != 0 {
+			roomIds = append(roomIds, roomId)
+		}
+	}
+	slices.Sort(roomIds)
+	clear(m.counts)
+	return packets.NewMissedPackets(count, roomIds)
+}
+
+// Queues the packet to be written to the client, applying the client's policy
+// when its send buffer is full
+func (c *WebSocketClient) SocketSendPacket(packet *packets.Packet) {
+	select {
+	case <-c.done:
+		return
+	case c.sendChan <- packet:
+		return
+	default:
+	}
+
+	switch c.policy {
+	case policyDropOldest:
+		select {
+		case oldest := <-c.sendChan:
+			c.dropPacket(oldest)
+		default:
+		}
+		select {
+		case c.sendChan <- packet:
+		default:
+			c.dropPacket(packet)
+		}
+	case policyDisconnect:
+		c.dropPacket(packet)
+		c.closeSlowConsumer()
+	default:
+		// Drop newest, or block, which can't wait here since the sender may be the hub
+		c.dropPacket(packet)
+	}
+}
+
+// Queues a packet sent from one of the client's own goroutines, waiting a little
+// for room in the send buffer when the client's policy is block. Never used by the hub
+func (c *WebSocketClient) sendPacketWaiting(packet *packets.Packet) {
+	if c.policy != policyBlock {
+		c.SocketSendPacket(packet)
+		return
+	}
+
+	timer := time.NewTimer(*sendTimeout)
+	defer timer.Stop()
+	select {
+	case <-c.done:
+	case c.sendChan <- packet:
+	case <-timer.C:
+		c.dropPacket(packet)
+	}
+}
+
+func (c *WebSocketClient) sendAsWaiting(message packets.Pkt, senderId uint64, roomId uint64) {
+	c.sendPacketWaiting(&packets.Packet{SenderId: senderId, RoomId: roomId, Msg: message})
+}
+
+func (c *WebSocketClient) MissedPacket(roomId uint64) {
+	if c.missed.add(roomId) {
+		c.logger.Printf("Missed a packet for room %d", roomId)
+	}
+}
+
+func (c *WebSocketClient) dropPacket(packet *packets.Packet) {
+	c.dropped.Add(1)
+	c.hub.drops.clientPackets.Add(1)
+	if c.missed.add(packet.RoomId) {
+		// Only the first drop until the client is told, they tend to come in bursts
+		c.logger.Printf("Send buffer full, dropping %T", packet.Msg)
+	}
+}
+
+// Hands the packet to the hub without waiting, dropping it when the hub is
+// behind. The hub calls this itself, so it can't wait on its own channel
+func (c *WebSocketClient) tryBroadcastPacket(packet *packets.Packet) {
+	select {
+	case c.hub.BroadcastChan <- packet:
+	default:
+		c.hub.broadcastDropped(packet)
+	}
+}
+
+// Waits a little for room in the hub, so a burst of broadcasts doesn't get
+// dropped right away. Only for the client's own goroutines, never the hub
+func (c *WebSocketClient) broadcastPacket(packet *packets.Packet) {
+	select {
+	case c.hub.BroadcastChan <- packet:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(*broadcastTimeout)
+	defer timer.Stop()
+	select {
+	case c.hub.BroadcastChan <- packet:
+	case <-timer.C:
+		c.hub.broadcastDropped(packet)
+	}
+}
+
+// Hands the room event to the hub, waiting a little like broadcastPacket. When
+// it can't get in, everyone in the room is told to resync, the sender included
+func (h *Hub) publishEvent(event roomEvent) {
+	select {
+	case h.EventChan <- event:
+		return
+	default:
+	}
+
+	timer := time.NewTimer(*broadcastTimeout)
+	defer timer.Stop()
+	select {
+	case h.EventChan <- event:
+	case <-timer.C:
+		h.drops.broadcasts.Add(1)
+		log.Printf("Event channel full, dropping %T for room %d", event.packet.Msg, event.packet.RoomId)
+		if room, found := h.Rooms.Get(event.packet.RoomId); found {
+			room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+				client.MissedPacket(event.packet.RoomId)
+			})
+		}
+	}
+}
+
+// Only signals WritePump to send the close frame, so it doesn't wait on the
+// connection of a client that already isn't keeping up
+func (c *WebSocketClient) closeSlowConsumer() {
+	if c.isClosed() {
+		return
+	}
+
+	c.hub.drops.slowDisconnects.Add(1)
+	c.CloseWithCode(CloseSlowConsumer, "Too slow to read packets", "too slow to read packets")
+}
+
+func (c *WebSocketClient) isClosed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
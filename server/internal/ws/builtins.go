@@ -0,0 +1,258 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/db"
+	"server/internal/usernames"
+	"server/pkg/packets"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func init() {
+	commands.register(command{
+		name:        "help",
+		usage:       "[command]",
+		description: "Lists the commands you can use, or explains one of them",
+		role:        RoleMember,
+		run:         runHelp,
+	})
+	commands.register(command{
+		name:        "me",
+		usage:       "<action>",
+		description: "Sends a message describing what you are doing",
+		role:        RoleMember,
+		minArgs:     1,
+		run:         runMe,
+	})
+	commands.register(command{
+		name:        "topic",
+		usage:       "[topic]",
+		description: "Shows the topic of the room. Moderators can change it",
+		role:        RoleMember,
+		run:         runTopic,
+	})
+	commands.register(command{
+		name:        "nick",
+		usage:       "[display name]",
+		description: "Changes your display name, or removes it when none is given",
+		role:        RoleMember,
+		run:         runNick,
+	})
+	commands.register(command{
+		name:        "invite",
+		usage:       "<username>",
+		description: "Invites a user to join the room, private rooms included",
+		role:        RoleMember,
+		minArgs:     1,
+		run:         runInvite,
+	})
+	commands.register(command{
+		name:        "kick",
+		usage:       "<username> [reason]",
+		description: "Removes a member from the room, and from its members when it is private",
+		role:        RoleModerator,
+		minArgs:     1,
+		run:         runKick,
+	})
+	commands.register(command{
+		name:        "mute",
+		usage:       "<username> [duration] [reason]",
+		description: fmt.Sprintf("Stops a member from sending messages and reacting, for %s unless a duration like 30m or 2d is given", formatDuration(defaultMuteDuration)),
+		role:        RoleModerator,
+		minArgs:     1,
+		run:         runMute,
+	})
+	commands.register(command{
+		name:        "unmute",
+		usage:       "<username>",
+		description: "Lets a muted member send messages again",
+		role:        RoleModerator,
+		minArgs:     1,
+		run:         runUnmute,
+	})
+}
+
+func runHelp(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	role := room.Role(c.userId)
+	if len(args.fields) > 0 {
+		name := strings.ToLower(strings.TrimPrefix(args.fields[0], "/"))
+		cmd, found := commands.get(name)
+		if !found || role < cmd.role {
+			reason := fmt.Sprintf("Unknown command /%s", name)
+			return &ChatMessageError{reason}
+		}
+		c.reply(room.Id, cmd.usageText()+"\n"+cmd.description)
+		return nil
+	}
+
+	lines := []string{"Commands:"}
+	for _, cmd := range commands.available(role) {
+		lines = append(lines, strings.TrimPrefix(cmd.usageText(), "Usage: ")+" - "+cmd.description)
+	}
+	c.reply(room.Id, strings.Join(lines, "\n"))
+	return nil
+}
+
+func runMe(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	chat := proto.Clone(args.chat).(*packets.ChatMessage)
+	chat.Msg = args.rest(0)
+	chat.Action = true
+	c.postChat(ctx, room, chat)
+	return nil
+}
+
+func runTopic(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	if len(args.fields) == 0 {
+		if room.Topic == "" {
+			c.reply(room.Id, "No topic is set")
+		} else {
+			c.reply(room.Id, "Topic: "+room.Topic)
+		}
+		return nil
+	}
+
+	updated, err := c.updateRoomDetails(ctx, room.Id, args.rest(0), room.Description)
+	if err != nil {
+		return err
+	}
+
+	c.announce(ctx, room.Id, fmt.Sprintf("%s changed the topic to: %s", c.username, updated.Topic))
+	return nil
+}
+
+func runNick(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	profile, err := c.service.SetDisplayName(ctx, c.userId, args.rest(0))
+	if err != nil {
+		return err
+	}
+	c.hub.PropagateProfile(profile)
+
+	if profile.DisplayName == "" {
+		c.reply(room.Id, "Your display name was removed")
+	} else {
+		c.reply(room.Id, "Your display name is now "+profile.DisplayName)
+	}
+	return nil
+}
+
+func runInvite(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	user, err := c.service.inviteUser(ctx, c.hub, room, c.userId, c.username, args.fields[0])
+	if err != nil {
+		return err
+	}
+	c.reply(room.Id, fmt.Sprintf("Invited %s to %s", user.Username, room.Name))
+	return nil
+}
+
+func runKick(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	user, err := c.moderationTarget(ctx, room, args.fields[0])
+	if err != nil {
+		return err
+	}
+
+	// Members of private rooms lose their membership, so they can't join again
+	wasMember := false
+	if room.Visibility == packets.RoomVisibility_ROOM_PRIVATE {
+		if wasMember, err = c.service.removeRoomMember(ctx, c.hub, room, user.ID); err != nil {
+			return err
+		}
+	}
+
+	notice := withReason(fmt.Sprintf("You were kicked from %s by %s", room.Name, c.username), args.rest(1))
+	if c.kickFromRoom(room, user.ID, notice) == 0 && !wasMember {
+		reason := fmt.Sprintf("%s is not in the room", user.Username)
+		return &ChatMessageError{reason}
+	}
+
+	c.announce(ctx, room.Id, withReason(fmt.Sprintf("%s was kicked by %s", user.Username, c.username), args.rest(1)))
+	return nil
+}
+
+func runMute(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	user, err := c.moderationTarget(ctx, room, args.fields[0])
+	if err != nil {
+		return err
+	}
+
+	duration, reasonStart := defaultMuteDuration, 1
+	if len(args.fields) > 1 && unicode.IsDigit(rune(args.fields[1][0])) {
+		// Reasons don't start with a digit, so this is meant as a duration
+		parsed, ok := parseMuteDuration(args.fields[1])
+		if !ok {
+			reason := fmt.Sprintf("Invalid duration %s, use something like 30m or 2d", args.fields[1])
+			return &ChatMessageError{reason}
+		}
+		duration, reasonStart = parsed, 2
+	}
+	if err := c.service.MuteUser(ctx, room, c.userId, user.ID, duration); err != nil {
+		return err
+	}
+
+	c.announce(ctx, room.Id, withReason(fmt.Sprintf("%s was muted by %s for %s", user.Username, c.username, formatDuration(duration)), args.rest(reasonStart)))
+	return nil
+}
+
+func runUnmute(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error {
+	user, err := c.moderationTarget(ctx, room, args.fields[0])
+	if err != nil {
+		return err
+	}
+
+	unmuted, err := c.service.UnmuteUser(ctx, room, user.ID)
+	if err != nil {
+		return err
+	}
+	if !unmuted {
+		reason := fmt.Sprintf("%s is not muted", user.Username)
+		return &ChatMessageError{reason}
+	}
+
+	c.announce(ctx, room.Id, fmt.Sprintf("%s was unmuted by %s", user.Username, c.username))
+	return nil
+}
+
+func (s *Service) findUser(c context.Context, username string) (db.User, error) {
+	user, err := s.repo.GetUserByUsername(c, usernames.Key(strings.TrimPrefix(username, "@")))
+	if errors.Is(err, sql.ErrNoRows) {
+		reason := fmt.Sprintf("User %s not found", username)
+		return db.User{}, &ChatMessageError{reason}
+	}
+	return user, err
+}
+
+// Finds the user a moderation command acts on. Moderators can only act on
+// members with a lower role than theirs
+func (c *WebSocketClient) moderationTarget(ctx context.Context, room Room, username string) (db.User, error) {
+	user, err := c.service.findUser(ctx, username)
+	if err != nil {
+		return db.User{}, err
+	}
+	if room.Role(user.ID) >= room.Role(c.userId) {
+		reason := fmt.Sprintf("You can't do that to %s", user.Username)
+		return db.User{}, &ChatMessageError{reason}
+	}
+	return user, nil
+}
+
+// Sends a notice only to this client
+func (c *WebSocketClient) reply(roomId uint64, text string) {
+	c.SocketSendAs(packets.NewSystem(text), c.id, roomId)
+}
+
+// Sends a notice to everyone in the room, this client included
+func (c *WebSocketClient) announce(ctx context.Context, roomId uint64, text string) {
+	c.broadcastEvent(ctx, packets.NewSystem(text), roomId)
+}
+
+func withReason(text string, reason string) string {
+	if reason == "" {
+		return text
+	}
+	return text + ": " + reason
+}
@@ -0,0 +1,162 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"server/pkg/packets"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A slash command members can run by sending a chat message starting with /
+type command struct {
+	name string
+
+	// Arguments shown in the usage, like <username> [reason]
+	usage       string
+	description string
+
+	// Lowest room role allowed to run it
+	role RoomRole
+
+	// Fewest arguments the command needs, it fails with its usage otherwise
+	minArgs int
+
+	run func(ctx context.Context, c *WebSocketClient, room Room, args commandArgs) error
+}
+
+// What was written after the command name
+type commandArgs struct {
+	raw    string
+	fields []string
+
+	// Where each field ends in raw
+	ends []int
+
+	// The message the command was sent in, for commands that send one
+	chat *packets.ChatMessage
+}
+
+func parseArgs(raw string) commandArgs {
+	args := commandArgs{raw: raw}
+	start := -1
+	for i, r := range raw {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			args.fields = append(args.fields, raw[start:i])
+			args.ends = append(args.ends, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		args.fields = append(args.fields, raw[start:])
+		args.ends = append(args.ends, len(raw))
+	}
+	return args
+}
+
+// Returns the text after the first n fields as it was written, spacing included
+func (a commandArgs) rest(n int) string {
+	if n == 0 {
+		return strings.TrimSpace(a.raw)
+	}
+	if n > len(a.fields) {
+		return ""
+	}
+	return strings.TrimSpace(a.raw[a.ends[n-1]:])
+}
+
+type commandRegistry struct {
+	commands map[string]command
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{
+		commands: make(map[string]command),
+	}
+}
+
+func (r *commandRegistry) register(cmd command) {
+	if _, exists := r.commands[cmd.name]; exists {
+		panic(fmt.Sprintf("command /%s registered twice", cmd.name))
+	}
+	r.commands[cmd.name] = cmd
+}
+
+func (r *commandRegistry) get(name string) (command, bool) {
+	cmd, found := r.commands[name]
+	return cmd, found
+}
+
+// Returns the commands someone with the role can run, sorted by name
+func (r *commandRegistry) available(role RoomRole) []command {
+	available := make([]command, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		if role >= cmd.role {
+			available = append(available, cmd)
+		}
+	}
+	slices.SortFunc(available, func(a, b command) int {
+		return strings.Compare(a.name, b.name)
+	})
+	return available
+}
+
+var commands = newCommandRegistry()
+
+// Splits a chat message into the command it runs and its arguments. Messages
+// starting with // aren't commands, they are sent with the first / removed
+func parseCommand(msg string) (string, commandArgs, bool) {
+	if !strings.HasPrefix(msg, "/") {
+		return "", commandArgs{}, false
+	}
+	first, _ := utf8.DecodeRuneInString(msg[1:])
+	if !unicode.IsLetter(first) {
+		return "", commandArgs{}, false
+	}
+
+	end := strings.IndexFunc(msg, unicode.IsSpace)
+	if end < 0 {
+		end = len(msg)
+	}
+	return strings.ToLower(msg[1:end]), parseArgs(msg[end:]), true
+}
+
+// Runs the command with the permissions the client has in the room. Failures
+// are denied with the reason, so the member can correct the command
+func (c *WebSocketClient) runCommand(ctx context.Context, room Room, name string, args commandArgs) {
+	cmd, found := commands.get(name)
+	if !found {
+		reason := fmt.Sprintf("Unknown command /%s, send /help to list commands or start with // to send a message beginning with /", name)
+		c.denyChatChange(&ChatMessageError{reason}, "")
+		return
+	}
+
+	if room.Role(c.userId) < cmd.role {
+		reason := fmt.Sprintf("Only %ss can use /%s", cmd.role, cmd.name)
+		c.denyChatChange(&ChatMessageError{reason}, "")
+		return
+	}
+	if len(args.fields) < cmd.minArgs {
+		c.denyChatChange(&ChatMessageError{cmd.usageText()}, "")
+		return
+	}
+
+	if err := cmd.run(ctx, c, room, args); err != nil {
+		c.denyChatChange(err, fmt.Sprintf("Unable to run /%s", cmd.name))
+	}
+}
+
+func (cmd command) usageText() string {
+	if cmd.usage == "" {
+		return fmt.Sprintf("Usage: /%s", cmd.name)
+	}
+	return fmt.Sprintf("Usage: /%s %s", cmd.name, cmd.usage)
+}
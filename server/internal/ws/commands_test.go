@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		msg        string
+		wantOk     bool
+		wantName   string
+		wantFields []string
+		// Text after the first field, as given to /kick and /mute reasons
+		wantRest string
+	}{
+		{"plain message", "hello there", false, "", nil, ""},
+		{"escaped slash", "//me is not a command", false, "", nil, ""},
+		{"space after slash", "/ me", false, "", nil, ""},
+		{"digit after slash", "/2 apples", false, "", nil, ""},
+		{"no arguments", "/help", true, "help", nil, ""},
+		{"one argument", "/kick bob", true, "kick", []string{"bob"}, ""},
+		{"name lowercased", "/ME waves", true, "me", []string{"waves"}, ""},
+		{"reason keeps its spacing", "/kick bob  being   rude ", true, "kick", []string{"bob", "being", "rude"}, "being   rude"},
+		{"tabs separate", "/nick\tAlice", true, "nick", []string{"Alice"}, ""},
+		{"non latin name", "/été chaud", true, "été", []string{"chaud"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, args, ok := parseCommand(test.msg)
+			if ok != test.wantOk {
+				t.Fatalf("parseCommand(%q) ok = %v, want %v", test.msg, ok, test.wantOk)
+			}
+			if name != test.wantName {
+				t.Errorf("parseCommand(%q) name = %q, want %q", test.msg, name, test.wantName)
+			}
+			if !slices.Equal(args.fields, test.wantFields) {
+				t.Errorf("parseCommand(%q) fields = %q, want %q", test.msg, args.fields, test.wantFields)
+			}
+			if rest := args.rest(1); rest != test.wantRest {
+				t.Errorf("parseCommand(%q) rest(1) = %q, want %q", test.msg, rest, test.wantRest)
+			}
+		})
+	}
+}
+
+func TestCommandArgsRest(t *testing.T) {
+	args := parseArgs("  new topic:  the  plan ")
+	tests := []struct {
+		skip int
+		want string
+	}{
+		{0, "new topic:  the  plan"},
+		{1, "topic:  the  plan"},
+		{2, "the  plan"},
+		{4, ""},
+		{5, ""},
+	}
+
+	for _, test := range tests {
+		if got := args.rest(test.skip); got != test.want {
+			t.Errorf("rest(%d) = %q, want %q", test.skip, got, test.want)
+		}
+	}
+}
+
+func TestAvailableCommands(t *testing.T) {
+	tests := []struct {
+		role RoomRole
+		want []string
+	}{
+		{RoleMember, []string{"help", "invite", "me", "nick", "topic"}},
+		{RoleModerator, []string{"help", "invite", "kick", "me", "mute", "nick", "topic", "unmute"}},
+		{RoleOwner, []string{"help", "invite", "kick", "me", "mute", "nick", "topic", "unmute"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.role.String(), func(t *testing.T) {
+			names := []string{}
+			for _, cmd := range commands.available(test.role) {
+				names = append(names, cmd.name)
+			}
+			if !slices.Equal(names, test.want) {
+				t.Errorf("available(%s) = %q, want %q", test.role, names, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"server/internal/db"
+	"server/pkg/packets"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	maxRoomNameChars    = 64
+	maxTopicChars       = 250
+	maxDescriptionChars = 2000
+)
+
+// Changes the topic and description of the room, which show to its members.
+// Only the room moderators can change them
+func (s *Service) UpdateRoomDetails(c context.Context, hub *Hub, room Room, userId string, topic string, description string) (Room, error) {
+	if !room.IsModerator(userId) {
+		return Room{}, &ChatMessageError{"Only moderators can change the topic and description"}
+	}
+
+	topic = strings.TrimSpace(topic)
+	if utf8.RuneCountInString(topic) > maxTopicChars {
+		reason := fmt.Sprintf("Topics can have at most %d characters", maxTopicChars)
+		return Room{}, &ChatMessageError{reason}
+	}
+	if strings.ContainsAny(topic, "\r\n") {
+		return Room{}, &ChatMessageError{"Topics must be a single line"}
+	}
+	description = strings.TrimSpace(description)
+	if utf8.RuneCountInString(description) > maxDescriptionChars {
+		reason := fmt.Sprintf("Descriptions can have at most %d characters", maxDescriptionChars)
+		return Room{}, &ChatMessageError{reason}
+	}
+
+	err := s.repo.queries.SetRoomDetails(c, db.SetRoomDetailsParams{
+		Topic:       topic,
+		Description: description,
+		ID:          int64(room.Id),
+	})
+	if err != nil {
+		return Room{}, err
+	}
+
+	room, found := hub.Rooms.Update(room.Id, func(room *Room) {
+		room.Topic = topic
+		room.Description = description
+	})
+	if !found {
+		return Room{}, &ChatMessageError{"Room not found"}
+	}
+	return room, nil
+}
+
+// Changes the room details for a request made over HTTP, and tells the members
+// connected to the room
+func (s *Service) UpdateRoom(c context.Context, hub *Hub, userId string, request *packets.UpdateRoomRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+
+	updated, err := s.UpdateRoomDetails(c, hub, room, userId, request.Topic, request.Description)
+	if err == nil {
+		s.broadcastRoomEvent(c, hub, room.Id, userId, roomUpdated(updated, userId))
+	}
+	return roomChangeResponse(err)
+}
+
+// Renames the room and tells the members connected to it. Only the owner can
+// rename the room
+func (s *Service) RenameRoom(c context.Context, hub *Hub, userId string, request *packets.RenameRoomRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+
+	updated, err := s.renameRoom(c, hub, room, userId, request.Name)
+	if err == nil {
+		s.broadcastRoomEvent(c, hub, room.Id, userId, roomUpdated(updated, userId))
+	}
+	return roomChangeResponse(err)
+}
+
+// Returns the room name without surrounding spaces, or why it can't be used.
+// The reason can be shown to the user
+func ValidateRoomName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxRoomNameChars {
+		reason := fmt.Sprintf("Room names have between 1 and %d characters", maxRoomNameChars)
+		return "", errors.New(reason)
+	}
+	if strings.ContainsAny(name, "\r\n") {
+		return "", errors.New("Room names must be a single line")
+	}
+	return name, nil
+}
+
+func (s *Service) renameRoom(c context.Context, hub *Hub, room Room, userId string, name string) (Room, error) {
+	if room.Role(userId) != RoleOwner {
+		return Room{}, &ChatMessageError{"Only the owner can rename the room"}
+	}
+
+	name, err := ValidateRoomName(name)
+	if err != nil {
+		return Room{}, &ChatMessageError{err.Error()}
+	}
+
+	err = s.repo.queries.RenameRoom(c, db.RenameRoomParams{
+		Name: name,
+		ID:   int64(room.Id),
+	})
+	if err != nil {
+		return Room{}, err
+	}
+
+	room, found := hub.Rooms.Update(room.Id, func(room *Room) {
+		room.Name = name
+	})
+	if !found {
+		return Room{}, &ChatMessageError{"Room not found"}
+	}
+	return room, nil
+}
+
+// Deletes the room with its messages and takes its members out of it. Only the
+// owner can delete the room
+func (s *Service) DeleteRoom(c context.Context, hub *Hub, userId string, request *packets.DeleteRoomRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+	if room.Role(userId) != RoleOwner {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Only the owner can delete the room")}, nil
+	}
+
+	if _, err := s.repo.PurgeRoom(c, int64(room.Id)); err != nil {
+		return nil, err
+	}
+
+	hub.RemoveRoom(room.Id)
+	log.Printf("Room %d deleted by its owner", room.Id)
+	return &packets.Message{Type: packets.NewOkResponseMsg()}, nil
+}
+
+// Denies room changes that failed for a reason the user can fix
+func roomChangeResponse(err error) (*packets.Message, error) {
+	var chatErr *ChatMessageError
+	if errors.As(err, &chatErr) {
+		return &packets.Message{Type: packets.NewDenyResponseMsg(chatErr.reason)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &packets.Message{Type: packets.NewOkResponseMsg()}, nil
+}
+
+// Logs a room event that doesn't come from a connection and sends it to
+// everyone in the room
+func (s *Service) broadcastRoomEvent(c context.Context, hub *Hub, roomId uint64, userId string, message packets.Pkt) {
+	s.publishRoomEvent(c, hub, roomId, nil, userId, message, nil)
+}
+
+func roomUpdated(room Room, updaterId string) packets.Pkt {
+	return packets.NewRoomUpdated(room.Id, room.Name, room.Topic, room.Description, room.Visibility, updaterId)
+}
+
+// Changes the room details for a moderator connected to the room
+func (c *WebSocketClient) updateRoomDetails(ctx context.Context, roomId uint64, topic string, description string) (Room, error) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return Room{}, &ChatMessageError{"Room not found"}
+	}
+
+	updated, err := c.service.UpdateRoomDetails(ctx, c.hub, room, c.userId, topic, description)
+	if err != nil {
+		return Room{}, err
+	}
+
+	c.broadcastEvent(ctx, roomUpdated(updated, c.userId), roomId)
+	return updated, nil
+}
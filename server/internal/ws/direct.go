@@ -0,0 +1,172 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// Members of a direct conversation, including the sender
+	maxConversationMembers = 8
+	maxDirectMessageChars  = 2000
+)
+
+// Error shown to the sender when their direct message can't be delivered
+type DirectMessageError struct {
+	reason string
+}
+
+func (e *DirectMessageError) Error() string {
+	return e.reason
+}
+
+// Persists a direct message and returns it with the ids of every member of the
+// conversation. The conversation is found from its id or, when it is zero, from
+// the set of recipients, and is created the first time those users talk
+func (s *Service) SaveDirectMessage(c context.Context, senderId string, conversationId uint64, recipientIds []string, msg string) (*packets.DirectMessage, []string, error) {
+	if strings.TrimSpace(msg) == "" {
+		return nil, nil, &DirectMessageError{"Message is empty"}
+	}
+	if utf8.RuneCountInString(msg) > maxDirectMessageChars {
+		return nil, nil, &DirectMessageError{"Message is too long"}
+	}
+
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	if conversationId == 0 {
+		conversationId, err = findOrCreateConversation(c, queries, senderId, recipientIds)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	members, err := queries.ListConversationMembers(c, int64(conversationId))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	memberIds := make([]string, 0, len(members))
+	senderUsername := ""
+	for _, member := range members {
+		memberIds = append(memberIds, member.UserID)
+		if member.UserID == senderId {
+			senderUsername = member.Username
+		}
+	}
+	if !slices.Contains(memberIds, senderId) {
+		return nil, nil, &DirectMessageError{"Conversation not found"}
+	}
+
+	saved, err := queries.CreateDirectMessage(c, db.CreateDirectMessageParams{
+		ConversationID: int64(conversationId),
+		SenderID:       senderId,
+		Msg:            msg,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := queries.TouchConversation(c, int64(conversationId)); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	message := packets.NewDirectMessage(uint64(saved.ID), conversationId, senderId, senderUsername, saved.Msg, saved.CreatedAt)
+	message.RecipientIds = memberIds
+	return message, memberIds, nil
+}
+
+func findOrCreateConversation(c context.Context, queries *db.Queries, senderId string, recipientIds []string) (uint64, error) {
+	memberIds := []string{senderId}
+	for _, recipientId := range recipientIds {
+		if !slices.Contains(memberIds, recipientId) {
+			memberIds = append(memberIds, recipientId)
+		}
+	}
+
+	if len(memberIds) < 2 {
+		return 0, &DirectMessageError{"No recipients"}
+	}
+	if len(memberIds) > maxConversationMembers {
+		reason := fmt.Sprintf("Conversations can have at most %d members", maxConversationMembers)
+		return 0, &DirectMessageError{reason}
+	}
+
+	// The same set of users always maps to the same conversation
+	slices.Sort(memberIds)
+	memberKey := strings.Join(memberIds, ",")
+
+	conversation, err := queries.GetConversationByMemberKey(c, memberKey)
+	if err == nil {
+		return uint64(conversation.ID), nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	for _, memberId := range memberIds {
+		if _, err := queries.GetUsernameById(c, memberId); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, &DirectMessageError{"Recipient not found"}
+			}
+			return 0, err
+		}
+	}
+
+	conversation, err = queries.CreateConversation(c, memberKey)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, memberId := range memberIds {
+		err := queries.AddConversationMember(c, db.AddConversationMemberParams{
+			ConversationID: conversation.ID,
+			UserID:         memberId,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return uint64(conversation.ID), nil
+}
+
+// Saves the direct message sent by this client and delivers it to every live
+// connection of the conversation members, including the sender's own
+func (c *WebSocketClient) sendDirectMessage(ctx context.Context, message *packets.DirectMessage) {
+	saved, memberIds, err := c.service.SaveDirectMessage(ctx, c.userId, message.ConversationId, message.RecipientIds, message.Msg)
+	if err != nil {
+		var dmErr *DirectMessageError
+		if errors.As(err, &dmErr) {
+			c.SocketSend(packets.NewDenyResponsePkt(dmErr.reason))
+			return
+		}
+
+		c.logger.Printf("error saving direct message: %v", err)
+		c.SocketSend(packets.NewDenyResponsePkt("Unable to send message"))
+		return
+	}
+
+	packet := packets.NewDirectMessagePkt(saved)
+	c.hub.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if slices.Contains(memberIds, client.UserId()) {
+			client.SocketSendAs(packet, c.id, 0)
+		}
+	})
+}
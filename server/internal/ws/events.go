@@ -0,0 +1,258 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	eventRetention = flag.Duration("event-retention", 24*time.Hour, "How long room events are kept for clients resuming after a reconnect")
+
+	// Resuming further back than this replays the recent messages instead
+	maxResumeEvents = 200
+)
+
+// Appends the message to the room's event log and returns the packet to send,
+// carrying the message's sequence number in the room
+func (s *Service) AppendRoomEvent(c context.Context, roomId uint64, senderId uint64, userId string, message packets.Pkt) (*packets.Packet, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	seq, err := queries.NextRoomSeq(c, int64(roomId))
+	if err != nil {
+		return nil, err
+	}
+
+	packet := &packets.Packet{SenderId: senderId, RoomId: roomId, Seq: uint64(seq), Msg: message}
+	data, err := proto.Marshal(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = queries.CreateRoomEvent(c, db.CreateRoomEventParams{
+		RoomID:    int64(roomId),
+		Seq:       seq,
+		UserID:    userId,
+		MessageID: eventMessageId(message),
+		Packet:    data,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return packet, tx.Commit()
+}
+
+// The message an event is about, so the events can be found when the message
+// or its sender goes away
+func eventMessageId(message packets.Pkt) sql.NullInt64 {
+	var messageId uint64
+	switch msg := message.(type) {
+	case *packets.Packet_Chat:
+		messageId = msg.Chat.Id
+	case *packets.Packet_EditChat:
+		messageId = msg.EditChat.MessageId
+	case *packets.Packet_DeleteChat:
+		messageId = msg.DeleteChat.MessageId
+	case *packets.Packet_MessageUpdated:
+		messageId = msg.MessageUpdated.MessageId
+	case *packets.Packet_Reactions:
+		messageId = msg.Reactions.MessageId
+	case *packets.Packet_ThreadUpdated:
+		messageId = msg.ThreadUpdated.MessageId
+	}
+	return sql.NullInt64{Int64: int64(messageId), Valid: messageId != 0}
+}
+
+// Rewrites the logged events about the message with its current text, so
+// clients resuming after an edit or deletion don't get back what was removed.
+// A deleted message loses its attachments and previews too
+func redactMessageEvents(c context.Context, queries *db.Queries, messageId int64, msg string, deleted bool) error {
+	events, err := queries.ListRoomEventsForMessage(c, sql.NullInt64{Int64: messageId, Valid: true})
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		packet := &packets.Packet{}
+		if err := proto.Unmarshal(event.Packet, packet); err != nil {
+			return err
+		}
+
+		switch m := packet.Msg.(type) {
+		case *packets.Packet_Chat:
+			m.Chat.Msg = msg
+			if deleted {
+				m.Chat.Attachments = nil
+				m.Chat.Previews = nil
+			}
+		case *packets.Packet_EditChat:
+			m.EditChat.Msg = msg
+		case *packets.Packet_MessageUpdated:
+			if !deleted {
+				continue
+			}
+			m.MessageUpdated.Previews = nil
+		default:
+			continue
+		}
+
+		data, err := proto.Marshal(packet)
+		if err != nil {
+			return err
+		}
+		err = queries.UpdateRoomEventPacket(c, db.UpdateRoomEventPacketParams{
+			Packet: data,
+			RoomID: event.RoomID,
+			Seq:    event.Seq,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) GetRoomLastSeq(c context.Context, roomId uint64) (uint64, error) {
+	seq, err := s.repo.queries.GetRoomLastSeq(c, int64(roomId))
+	return uint64(seq), err
+}
+
+// Returns the events of the room after lastSeq, oldest first. Returns false when
+// they can't all be replayed, because some were removed or there are too many
+func (s *Service) GetRoomEvents(c context.Context, roomId uint64, lastSeq uint64) ([]*packets.Packet, bool, error) {
+	currentSeq, err := s.GetRoomLastSeq(c, roomId)
+	if err != nil {
+		return nil, false, err
+	}
+	if lastSeq > currentSeq {
+		return nil, false, nil
+	}
+	if currentSeq-lastSeq > uint64(maxResumeEvents) {
+		return nil, false, nil
+	}
+
+	events, err := s.repo.queries.ListRoomEvents(c, db.ListRoomEventsParams{
+		RoomID: int64(roomId),
+		Seq:    int64(lastSeq),
+		Limit:  int64(maxResumeEvents),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	eventPackets := make([]*packets.Packet, 0, len(events))
+	for i, event := range events {
+		seq := uint64(event.Seq)
+		if seq > currentSeq {
+			// Appended since, the client gets it live
+			break
+		}
+		if seq != lastSeq+uint64(i)+1 {
+			return nil, false, nil
+		}
+
+		packet := &packets.Packet{}
+		if err := proto.Unmarshal(event.Packet, packet); err != nil {
+			return nil, false, err
+		}
+		eventPackets = append(eventPackets, packet)
+	}
+	if lastSeq+uint64(len(eventPackets)) != currentSeq {
+		return nil, false, nil
+	}
+	return eventPackets, true, nil
+}
+
+// Deletes the events older than the retention period and returns how many
+func (s *Service) RemoveOldRoomEvents(c context.Context) (int64, error) {
+	return s.repo.queries.DeleteOldRoomEvents(c, time.Now().Add(-*eventRetention).UTC())
+}
+
+// Logs the message as a room event and hands it to the hub to send to the room.
+// The room's events are logged and handed over one at a time, so the hub gets
+// them in the order of their sequence numbers. The sender, nil when the event
+// doesn't come from a connection, gets what echo returns for it instead
+func (s *Service) publishRoomEvent(c context.Context, hub *Hub, roomId uint64, sender client.ClientInterfacer, userId string, message packets.Pkt, echo func(event *packets.Packet) *packets.Packet) {
+	room, found := hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+	room.events.Lock()
+	defer room.events.Unlock()
+
+	senderId := uint64(0)
+	if sender != nil {
+		senderId = sender.Id()
+	}
+	packet, err := s.AppendRoomEvent(c, roomId, senderId, userId, message)
+	if err != nil {
+		// Still deliver it live, it just can't be resumed
+		log.Printf("Error logging event of room %d: %v", roomId, err)
+		packet = &packets.Packet{SenderId: senderId, RoomId: roomId, Msg: message}
+	}
+
+	event := roomEvent{packet: packet, sender: sender}
+	if sender != nil && echo != nil {
+		event.echo = echo(packet)
+	}
+	hub.publishEvent(event)
+}
+
+// Logs the message as a room event and broadcasts it with its sequence number,
+// so clients that miss it can get it back when they resume. This client gets it
+// too, in order with the room's other events
+func (c *WebSocketClient) broadcastEvent(ctx context.Context, message packets.Pkt, roomId uint64) {
+	c.broadcastEventEcho(ctx, message, roomId, func(event *packets.Packet) *packets.Packet {
+		return event
+	})
+}
+
+// Like broadcastEvent, but this client gets what echo returns for the event
+func (c *WebSocketClient) broadcastEventEcho(ctx context.Context, message packets.Pkt, roomId uint64, echo func(event *packets.Packet) *packets.Packet) {
+	c.service.publishRoomEvent(ctx, c.hub, roomId, c, c.userId, message, echo)
+}
+
+// Sends the client the room events it missed since lastSeq. Returns false when
+// that isn't possible and the client needs the recent messages instead
+func (c *WebSocketClient) resumeRoom(ctx context.Context, roomId uint64, lastSeq uint64) bool {
+	events, complete, err := c.service.GetRoomEvents(ctx, roomId, lastSeq)
+	if err != nil {
+		c.logger.Printf("error getting events of room %d: %v", roomId, err)
+		return false
+	}
+	if !complete {
+		return false
+	}
+
+	for _, event := range events {
+		c.sendPacketWaiting(event)
+	}
+	return true
+}
+
+// Sends a client that missed packets what happened in the room since lastSeq,
+// or the recent messages when that isn't possible
+func (c *WebSocketClient) resyncRoom(ctx context.Context, roomId uint64, lastSeq uint64) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	if lastSeq == 0 || !c.resumeRoom(ctx, roomId, lastSeq) {
+		c.replayMessages(ctx, room)
+	}
+}
@@ -1,9 +1,14 @@
 package ws
 
 import (
+	"io"
 	"log"
 	"net/http"
 	"server/internal/client"
+	"server/internal/jwt"
+	"server/pkg/packets"
+
+	"google.golang.org/protobuf/proto"
 )
 
 type Handler struct {
@@ -39,3 +44,553 @@ func (h *Handler) Serve(
 	go client.WritePump()
 	go client.ReadPump()
 }
+
+func (h *Handler) UpdateRoom(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_UpdateRoom)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.UpdateRoom(request.Context(), h.hub, accessToken.Subject, pktMessage.UpdateRoom)
+	if err != nil {
+		log.Printf("An error occured when trying to update a room: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) RenameRoom(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_RenameRoom)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.RenameRoom(request.Context(), h.hub, accessToken.Subject, pktMessage.RenameRoom)
+	if err != nil {
+		log.Printf("An error occured when trying to rename a room: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) DeleteRoom(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_DeleteRoom)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.DeleteRoom(request.Context(), h.hub, accessToken.Subject, pktMessage.DeleteRoom)
+	if err != nil {
+		log.Printf("An error occured when trying to delete a room: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) SetRoomVisibility(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_SetRoomVisibility)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.SetRoomVisibility(request.Context(), h.hub, accessToken.Subject, pktMessage.SetRoomVisibility)
+	if err != nil {
+		log.Printf("An error occured when trying to change the visibility of a room: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) InviteToRoom(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_InviteToRoom)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.InviteToRoom(request.Context(), h.hub, accessToken.Subject, pktMessage.InviteToRoom)
+	if err != nil {
+		log.Printf("An error occured when trying to invite to a room: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) GetRoomInvites(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	_, ok := message.Type.(*packets.Message_RoomInvitesRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.ListRoomInvites(request.Context(), accessToken.Subject)
+	if err != nil {
+		log.Printf("An error occured when trying to list room invites: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) RespondToInvite(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_RespondToInvite)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.RespondToInvite(request.Context(), accessToken.Subject, pktMessage.RespondToInvite)
+	if err != nil {
+		log.Printf("An error occured when trying to answer a room invite: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) CreateInviteCode(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_CreateInviteCode)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.CreateInviteCode(request.Context(), h.hub, accessToken.Subject, pktMessage.CreateInviteCode)
+	if err != nil {
+		log.Printf("An error occured when trying to create an invite code: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) GetInviteCodes(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_InviteCodesRequest)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.ListInviteCodes(request.Context(), h.hub, accessToken.Subject, pktMessage.InviteCodesRequest)
+	if err != nil {
+		log.Printf("An error occured when trying to list invite codes: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) RevokeInviteCode(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_RevokeInviteCode)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.RevokeInviteCode(request.Context(), h.hub, accessToken.Subject, pktMessage.RevokeInviteCode)
+	if err != nil {
+		log.Printf("An error occured when trying to revoke an invite code: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
+
+func (h *Handler) RedeemInviteCode(writer http.ResponseWriter, request *http.Request) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		log.Printf("Error reading request body: %v", err)
+		http.Error(writer, "Error reading request body", http.StatusBadRequest)
+		return
+	}
+	defer request.Body.Close()
+
+	message := &packets.Message{}
+	err = proto.Unmarshal(body, message)
+	if err != nil {
+		log.Printf("Error unmarshalling request body: %v", err)
+		http.Error(writer, "Error unmarshalling request body", http.StatusBadRequest)
+		return
+	}
+
+	pktMessage, ok := message.Type.(*packets.Message_RedeemInviteCode)
+	if !ok {
+		log.Printf("Message is not expected type: %v", err)
+		http.Error(writer, "Error reading message", http.StatusBadRequest)
+		return
+	}
+
+	token := request.Header.Get("Authorization")
+	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
+	if err != nil {
+		log.Printf("token revoked or expired: %v", err)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	responseMessage, err := h.service.RedeemInviteCode(request.Context(), h.hub, accessToken.Subject, pktMessage.RedeemInviteCode)
+	if err != nil {
+		log.Printf("An error occured when trying to redeem an invite code: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	packet, err := proto.Marshal(responseMessage)
+	if err != nil {
+		log.Printf("Failed to marshal success packet: %v", err)
+		http.Error(writer, "An error occured", http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusOK)
+	writer.Write(packet)
+}
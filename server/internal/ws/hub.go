@@ -7,26 +7,61 @@ import (
 	"server/pkg/packets"
 )
 
+// A request for a client to join or leave a room
+type Membership struct {
+	Client client.ClientInterfacer
+	RoomId uint64
+
+	// Told whether the client joined the room, when set
+	Joined chan<- bool
+}
+
 // The hub is the central point of communication between all connected clients
 type Hub struct {
 	Rooms *objects.SharedCollection[Room]
 
+	// Every connected client, whatever rooms they are in. Client IDs come from here
+	Clients *objects.SharedCollection[client.ClientInterfacer]
+
 	// Clients in this channel will be registered to the hub
 	RegisterChan chan client.ClientInterfacer
 
 	// Clients in this channel will be unregistered from the hub
 	UnregisterChan chan client.ClientInterfacer
 
-	// Packets in this channel will be processed by all connected clients except the sender
+	// Clients in this channel will be added to the room
+	JoinRoomChan chan Membership
+
+	// Clients in this channel will be removed from the room
+	LeaveRoomChan chan Membership
+
+	// Packets in this channel will be processed by all clients in the packet's room except the sender
 	BroadcastChan chan *packets.Packet
+
+	// Rooms in this channel were deleted, their clients will be taken out of them
+	RemovedRoomChan chan Room
+
+	// Logged room events in this channel will be sent to every client in their room
+	EventChan chan roomEvent
+
+	presence *presenceRegistry
+	drops    dropCounters
+	limits   *rateLimiter
 }
 
 func NewHub() *Hub {
 	return &Hub{
-		Rooms:          objects.NewSharedCollection[Room](),
-		RegisterChan:   make(chan client.ClientInterfacer),
-		UnregisterChan: make(chan client.ClientInterfacer),
-		BroadcastChan:  make(chan *packets.Packet, 256),
+		Rooms:           objects.NewSharedCollection[Room](),
+		Clients:         objects.NewSharedCollection[client.ClientInterfacer](),
+		RegisterChan:    make(chan client.ClientInterfacer),
+		UnregisterChan:  make(chan client.ClientInterfacer),
+		JoinRoomChan:    make(chan Membership),
+		LeaveRoomChan:   make(chan Membership),
+		BroadcastChan:   make(chan *packets.Packet, 256),
+		RemovedRoomChan: make(chan Room),
+		EventChan:       make(chan roomEvent, 256),
+		presence:        newPresenceRegistry(),
+		limits:          newRateLimiter(),
 	}
 }
 
@@ -35,13 +70,23 @@ func (h *Hub) Run() {
 	for {
 		select {
 		case client := <-h.RegisterChan:
-			room, _ := h.Rooms.Get(client.RoomId())
-			room.RemoveOldMessages(room.LastMessages)
-			client.Initialize(room.Clients.Add(client))
+			client.Initialize(h.Clients.Add(client))
 		case client := <-h.UnregisterChan:
-			room, _ := h.Rooms.Get(client.RoomId())
-			client.Broadcast(packets.NewUnregister(client.Id()), room.Id)
-			room.Clients.Remove(client.Id())
+			for _, roomId := range client.RoomIds() {
+				h.leaveRoom(client, roomId)
+			}
+			h.Clients.Remove(client.Id())
+		case membership := <-h.JoinRoomChan:
+			joined := h.joinRoom(membership.Client, membership.RoomId)
+			if membership.Joined != nil {
+				membership.Joined <- joined
+			}
+		case membership := <-h.LeaveRoomChan:
+			if h.leaveRoom(membership.Client, membership.RoomId) {
+				membership.Client.LeftRoom(membership.RoomId)
+			}
+		case room := <-h.RemovedRoomChan:
+			h.emptyRoom(room)
 		case packet := <-h.BroadcastChan:
 			if room, found := h.Rooms.Get(packet.RoomId); found {
 				room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
@@ -50,6 +95,136 @@ func (h *Hub) Run() {
 					}
 				})
 			}
+		case event := <-h.EventChan:
+			h.deliverEvent(event)
 		}
 	}
 }
+
+// Applies the message retention to every room and returns how many messages were removed
+func (h *Hub) RemoveOldMessages() int64 {
+	removed := int64(0)
+	h.Rooms.ForEach(func(id uint64, room Room) {
+		removed += int64(room.RemoveOldMessages(room.LastMessages))
+	})
+	return removed
+}
+
+// Updates the profile of every connection the user has open, so rooms they are
+// in see the new identity without reconnecting
+func (h *Hub) PropagateProfile(profile *packets.ProfileMessage) {
+	h.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if client.UserId() != profile.UserId {
+			return
+		}
+
+		client.SetProfile(profile)
+		register := packets.NewRegister(clientId, client.Username(), profile)
+		for _, roomId := range client.RoomIds() {
+			client.SocketSendAs(register, clientId, roomId)
+			client.Broadcast(register, roomId)
+		}
+	})
+}
+
+// Closes every connection the user has open and returns how many there were
+func (h *Hub) DisconnectUser(userId string, reason string) int64 {
+	closed := int64(0)
+	h.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if client.UserId() == userId {
+			client.Close(reason)
+			closed++
+		}
+	})
+	return closed
+}
+
+// A logged room event on its way to the clients in the room. The client that
+// caused it, nil when no connection did, gets the echo instead, or nothing when
+// there is none. It goes through the hub like the rest, so every client sees
+// the room's events in order
+type roomEvent struct {
+	packet *packets.Packet
+	sender client.ClientInterfacer
+	echo   *packets.Packet
+}
+
+func (h *Hub) deliverEvent(event roomEvent) {
+	room, found := h.Rooms.Get(event.packet.RoomId)
+	if !found {
+		return
+	}
+
+	room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if event.sender == nil || clientId != event.sender.Id() {
+			// Room events go out as they were logged, sequence number included
+			client.SocketSendPacket(event.packet)
+		} else if event.echo != nil {
+			client.SocketSendPacket(event.echo)
+		}
+	})
+}
+
+// Forgets the room and takes every client out of it. Clients stay connected,
+// they may be in other rooms
+func (h *Hub) RemoveRoom(roomId uint64) {
+	room, found := h.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	h.Rooms.Remove(roomId)
+	h.RemovedRoomChan <- room
+}
+
+// Tells the clients in the removed room and takes them out of it. Clients that
+// were joining it are in it by now, nobody can join it anymore
+func (h *Hub) emptyRoom(room Room) {
+	deleted := packets.NewRoomDeleted(room.Id)
+	room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		client.SocketSendAs(deleted, clientId, room.Id)
+		room.Clients.Remove(clientId)
+		client.LeftRoom(room.Id)
+	})
+}
+
+// Adds the client to the room. Returns whether it wasn't in the room already
+func (h *Hub) joinRoom(client client.ClientInterfacer, roomId uint64) bool {
+	if _, registered := h.Clients.Get(client.Id()); !registered {
+		// Closed and unregistered before its join got here
+		return false
+	}
+
+	room, found := h.Rooms.Get(roomId)
+	if !found {
+		client.SocketSend(packets.NewDenyResponsePkt("Room not found"))
+		return false
+	}
+
+	if _, member := room.Clients.Get(client.Id()); member {
+		return false
+	}
+
+	room.RemoveOldMessages(room.LastMessages)
+	room.Clients.Add(client, client.Id())
+	client.JoinedRoom(roomId)
+	return true
+}
+
+// Removes the client from the room and lets the other members know.
+// Returns whether the client was in the room
+func (h *Hub) leaveRoom(client client.ClientInterfacer, roomId uint64) bool {
+	room, found := h.Rooms.Get(roomId)
+	if !found {
+		// The room was deleted while the client was still in it
+		return false
+	}
+
+	if _, member := room.Clients.Get(client.Id()); !member {
+		return false
+	}
+
+	client.Broadcast(packets.NewUnregister(client.Id()), roomId)
+	room.Clients.Remove(client.Id())
+	return true
+}
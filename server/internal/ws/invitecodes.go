@@ -0,0 +1,281 @@
+package ws
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"server/internal/db"
+	"server/pkg/packets"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	maxInviteCodeLifetime = 30 * 24 * time.Hour
+	maxInviteCodeUses     = 10000
+
+	// Random bytes in a code, 9 of them make a 12 character code
+	inviteCodeBytes = 9
+)
+
+// Creates a code anyone can redeem to become a member of the room with the
+// role it grants, until it expires or runs out of uses. Only the owner can
+// create codes
+func (s *Service) CreateInviteCode(c context.Context, hub *Hub, userId string, request *packets.CreateInviteCodeRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+	if room.Role(userId) != RoleOwner {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Only the owner can create invite codes")}, nil
+	}
+	if request.Role != packets.RoomRole_ROOM_ROLE_MEMBER && request.Role != packets.RoomRole_ROOM_ROLE_MODERATOR {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite codes can only make members or moderators")}, nil
+	}
+	if request.MaxUses > uint32(maxInviteCodeUses) {
+		reason := fmt.Sprintf("Invite codes can be used at most %d times", maxInviteCodeUses)
+		return &packets.Message{Type: packets.NewDenyResponseMsg(reason)}, nil
+	}
+
+	now := time.Now().UTC()
+	expiresAt := sql.NullTime{}
+	if request.ExpiresInSeconds > 0 {
+		lifetime := time.Duration(request.ExpiresInSeconds) * time.Second
+		if lifetime > maxInviteCodeLifetime {
+			reason := fmt.Sprintf("Invite codes can last at most %s", formatDuration(maxInviteCodeLifetime))
+			return &packets.Message{Type: packets.NewDenyResponseMsg(reason)}, nil
+		}
+		expiresAt = sql.NullTime{Time: now.Add(lifetime), Valid: true}
+	}
+
+	code, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	created, err := s.repo.queries.CreateInviteCode(c, db.CreateInviteCodeParams{
+		Code:      code,
+		RoomID:    int64(room.Id),
+		CreatedBy: userId,
+		Role:      int64(request.Role),
+		MaxUses:   int64(request.MaxUses),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("User %s created invite code %s for room %d", userId, created.Code, room.Id)
+	return &packets.Message{Type: &packets.Message_InviteCode{InviteCode: inviteCodeMessage(created, nil)}}, nil
+}
+
+// Lists the invite codes of the room, newest first, with who redeemed each of
+// them. Only the owner can list them
+func (s *Service) ListInviteCodes(c context.Context, hub *Hub, userId string, request *packets.InviteCodesRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+	if room.Role(userId) != RoleOwner {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Only the owner can see invite codes")}, nil
+	}
+
+	codes, err := s.repo.queries.ListInviteCodes(c, int64(room.Id))
+	if err != nil {
+		return nil, err
+	}
+	uses, err := s.repo.queries.ListInviteCodeUses(c, int64(room.Id))
+	if err != nil {
+		return nil, err
+	}
+	redemptions := make(map[string][]*packets.InviteCodeUseMessage)
+	for _, use := range uses {
+		redemptions[use.Code] = append(redemptions[use.Code], &packets.InviteCodeUseMessage{
+			UserId:   use.UserID,
+			Username: use.Username,
+			UsedAt:   timestamppb.New(use.UsedAt),
+		})
+	}
+
+	messages := make([]*packets.InviteCodeMessage, 0, len(codes))
+	for _, code := range codes {
+		messages = append(messages, inviteCodeMessage(code, redemptions[code.Code]))
+	}
+	return &packets.Message{Type: &packets.Message_InviteCodesResponse{
+		InviteCodesResponse: &packets.InviteCodesResponseMessage{Codes: messages},
+	}}, nil
+}
+
+// Stops the code from being redeemed. Members who already redeemed it stay
+// members. Only the owner of the room can revoke its codes
+func (s *Service) RevokeInviteCode(c context.Context, hub *Hub, userId string, request *packets.RevokeInviteCodeRequestMessage) (*packets.Message, error) {
+	code, err := s.repo.queries.GetInviteCode(c, request.Code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite code not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	room, found := hub.Rooms.Get(uint64(code.RoomID))
+	if !found || room.Role(userId) != RoleOwner {
+		// Codes of rooms the user doesn't own are none of their business
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite code not found")}, nil
+	}
+
+	err = s.repo.queries.RevokeInviteCode(c, db.RevokeInviteCodeParams{
+		RevokedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		Code:      code.Code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("User %s revoked invite code %s of room %d", userId, code.Code, room.Id)
+	return &packets.Message{Type: packets.NewOkResponseMsg()}, nil
+}
+
+// Makes the user a member of the room the code is for, with the role the code
+// grants. Each use is recorded, and members never lose a higher role they had
+func (s *Service) RedeemInviteCode(c context.Context, hub *Hub, userId string, request *packets.RedeemInviteCodeRequestMessage) (*packets.Message, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	code, err := queries.GetInviteCode(c, request.Code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite code not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	room, found := hub.Rooms.Get(uint64(code.RoomID))
+	switch {
+	case !found:
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite code not found")}, nil
+	case code.RevokedAt.Valid:
+		return &packets.Message{Type: packets.NewDenyResponseMsg("This invite code was revoked")}, nil
+	case code.ExpiresAt.Valid && !now.Before(code.ExpiresAt.Time):
+		return &packets.Message{Type: packets.NewDenyResponseMsg("This invite code has expired")}, nil
+	}
+
+	role := RoomRole(code.Role)
+	current := room.Role(userId)
+	if current == RoleOwner {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("You own this room")}, nil
+	}
+	_, err = queries.GetRoomMember(c, db.GetRoomMemberParams{
+		RoomID: code.RoomID,
+		UserID: userId,
+	})
+	member := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if member && current >= role {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("You are already a member of this room")}, nil
+	}
+
+	used, err := queries.UseInviteCode(c, code.Code)
+	if err != nil {
+		return nil, err
+	}
+	if used == 0 {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("This invite code has been used up")}, nil
+	}
+
+	err = queries.CreateInviteCodeUse(c, db.CreateInviteCodeUseParams{
+		Code:   code.Code,
+		RoomID: code.RoomID,
+		UserID: userId,
+		Role:   code.Role,
+		UsedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = queries.UpsertRoomMemberRole(c, db.UpsertRoomMemberRoleParams{
+		RoomID:  code.RoomID,
+		UserID:  userId,
+		AddedBy: code.CreatedBy,
+		Role:    code.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// An invite they haven't answered is answered by redeeming the code
+	err = queries.DeleteRoomInvite(c, db.DeleteRoomInviteParams{
+		RoomID: code.RoomID,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if role > current {
+		room, found = hub.Rooms.Update(room.Id, func(room *Room) {
+			*room = room.withRole(userId, role)
+		})
+		if !found {
+			// Deleted since, along with the membership
+			return &packets.Message{Type: packets.NewDenyResponseMsg("Invite code not found")}, nil
+		}
+		current = role
+	}
+	log.Printf("User %s redeemed invite code %s of room %d", userId, code.Code, room.Id)
+
+	return &packets.Message{Type: &packets.Message_InviteCodeRedeemed{
+		InviteCodeRedeemed: &packets.InviteCodeRedeemedMessage{
+			Room: &packets.NewRoomResponseMessage{
+				RoomId:      room.Id,
+				OwnerId:     room.OwnerId,
+				Name:        room.Name,
+				Topic:       room.Topic,
+				Description: room.Description,
+				Visibility:  room.Visibility,
+			},
+			Role: packets.RoomRole(current),
+		},
+	}}, nil
+}
+
+func inviteCodeMessage(code db.RoomInviteCode, redemptions []*packets.InviteCodeUseMessage) *packets.InviteCodeMessage {
+	message := &packets.InviteCodeMessage{
+		Code:        code.Code,
+		RoomId:      uint64(code.RoomID),
+		Role:        packets.RoomRole(code.Role),
+		MaxUses:     uint32(code.MaxUses),
+		Uses:        uint32(code.Uses),
+		Revoked:     code.RevokedAt.Valid,
+		CreatorId:   code.CreatedBy,
+		CreatedAt:   timestamppb.New(code.CreatedAt),
+		Redemptions: redemptions,
+	}
+	if code.ExpiresAt.Valid {
+		message.ExpiresAt = timestamppb.New(code.ExpiresAt.Time)
+	}
+	return message
+}
+
+// Codes are random and URL safe, so they can be shared as part of a link
+func newInviteCode() (string, error) {
+	data := make([]byte, inviteCodeBytes)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
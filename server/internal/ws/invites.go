@@ -0,0 +1,261 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+	"time"
+)
+
+// Whether the user can join the room. Private rooms only let in their owner
+// and members, anyone with the id can join the others
+func (s *Service) CanJoinRoom(c context.Context, room Room, userId string) (bool, error) {
+	if room.Visibility != packets.RoomVisibility_ROOM_PRIVATE || room.OwnerId == userId {
+		return true, nil
+	}
+
+	member, err := s.repo.queries.IsRoomMember(c, db.IsRoomMemberParams{
+		RoomID: int64(room.Id),
+		UserID: userId,
+	})
+	return member == 1, err
+}
+
+// Whether the client can join the room, denying it when it can't. Private rooms
+// look like they don't exist to those who aren't members
+func (c *WebSocketClient) canJoin(ctx context.Context, roomId uint64) bool {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		// The hub tells the client
+		return true
+	}
+
+	allowed, err := c.service.CanJoinRoom(ctx, room, c.userId)
+	if err != nil {
+		c.logger.Printf("error checking access to room %d: %v", roomId, err)
+		c.SocketSend(packets.NewDenyResponsePkt("Unable to join the room"))
+		return false
+	}
+	if !allowed {
+		c.SocketSend(packets.NewDenyResponsePkt("Room not found"))
+	}
+	return allowed
+}
+
+// Changes who can find and join the room, and tells its members. Clients that
+// can't be in the room anymore are removed from it. Only the owner can change it
+func (s *Service) SetRoomVisibility(c context.Context, hub *Hub, userId string, request *packets.SetRoomVisibilityRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+	if room.Role(userId) != RoleOwner {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Only the owner can change who can join the room")}, nil
+	}
+	if _, valid := packets.RoomVisibility_name[int32(request.Visibility)]; !valid {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Unknown visibility")}, nil
+	}
+
+	err := s.repo.queries.SetRoomVisibility(c, db.SetRoomVisibilityParams{
+		Visibility: int64(request.Visibility),
+		ID:         int64(room.Id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	room, found = hub.Rooms.Update(room.Id, func(room *Room) {
+		room.Visibility = request.Visibility
+	})
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+	s.broadcastRoomEvent(c, hub, room.Id, userId, roomUpdated(room, userId))
+
+	if err := s.removeNonMembers(c, hub, room); err != nil {
+		return nil, err
+	}
+	return &packets.Message{Type: packets.NewOkResponseMsg()}, nil
+}
+
+// Takes the clients that can no longer join the room out of it
+func (s *Service) removeNonMembers(c context.Context, hub *Hub, room Room) error {
+	clients := []client.ClientInterfacer{}
+	room.Clients.ForEach(func(clientId uint64, member client.ClientInterfacer) {
+		clients = append(clients, member)
+	})
+
+	for _, member := range clients {
+		allowed, err := s.CanJoinRoom(c, room, member.UserId())
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			notice := fmt.Sprintf("%s is now private and you are not a member", room.Name)
+			member.SocketSendAs(packets.NewSystem(notice), member.Id(), room.Id)
+			hub.LeaveRoomChan <- Membership{Client: member, RoomId: room.Id}
+		}
+	}
+	return nil
+}
+
+// Invites a user to the room and tells their connections about it. The invite
+// stays until they answer it, and accepting it makes them a member
+func (s *Service) inviteUser(c context.Context, hub *Hub, room Room, inviterId string, inviterUsername string, username string) (db.User, error) {
+	user, err := s.findUser(c, username)
+	if err != nil {
+		return db.User{}, err
+	}
+	if user.ID == inviterId {
+		return db.User{}, &ChatMessageError{"You can't invite yourself"}
+	}
+
+	if room.Visibility == packets.RoomVisibility_ROOM_PRIVATE {
+		member, err := s.CanJoinRoom(c, room, user.ID)
+		if err != nil {
+			return db.User{}, err
+		}
+		if member {
+			reason := fmt.Sprintf("%s is already a member", user.Username)
+			return db.User{}, &ChatMessageError{reason}
+		}
+	} else {
+		alreadyHere := false
+		room.Clients.ForEach(func(clientId uint64, member client.ClientInterfacer) {
+			alreadyHere = alreadyHere || member.UserId() == user.ID
+		})
+		if alreadyHere {
+			reason := fmt.Sprintf("%s is already in the room", user.Username)
+			return db.User{}, &ChatMessageError{reason}
+		}
+	}
+
+	err = s.repo.queries.UpsertRoomInvite(c, db.UpsertRoomInviteParams{
+		RoomID:    int64(room.Id),
+		UserID:    user.ID,
+		InviterID: inviterId,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return db.User{}, err
+	}
+
+	invite := packets.NewRoomInvite(room.Id, room.Name, inviterId, inviterUsername)
+	hub.Clients.ForEach(func(clientId uint64, connection client.ClientInterfacer) {
+		if connection.UserId() == user.ID && !connection.IsInRoom(room.Id) {
+			connection.SocketSendAs(invite, clientId, 0)
+		}
+	})
+	return user, nil
+}
+
+// Invites a user to the room for a request made over HTTP. Anyone who can join
+// the room can invite others to it
+func (s *Service) InviteToRoom(c context.Context, hub *Hub, userId string, request *packets.InviteToRoomRequestMessage) (*packets.Message, error) {
+	room, found := hub.Rooms.Get(request.RoomId)
+	if found {
+		allowed, err := s.CanJoinRoom(c, room, userId)
+		if err != nil {
+			return nil, err
+		}
+		// Private rooms are hidden from those who can't join them
+		found = allowed
+	}
+	if !found {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Room not found")}, nil
+	}
+
+	inviter, err := s.GetUsernameById(c, userId)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.inviteUser(c, hub, room, userId, inviter, request.Username)
+	return roomChangeResponse(err)
+}
+
+// Lists the invites the user hasn't answered yet, oldest first
+func (s *Service) ListRoomInvites(c context.Context, userId string) (*packets.Message, error) {
+	rows, err := s.repo.queries.ListRoomInvitesForUser(c, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	invites := make([]*packets.RoomInviteMessage, 0, len(rows))
+	for _, row := range rows {
+		invites = append(invites, &packets.RoomInviteMessage{
+			RoomId:          uint64(row.RoomID),
+			RoomName:        row.RoomName,
+			InviterId:       row.InviterID,
+			InviterUsername: row.InviterUsername,
+		})
+	}
+	return &packets.Message{Type: packets.NewRoomInvitesResponseMsg(invites)}, nil
+}
+
+// Accepts or declines an invite. Accepting makes the user a member of the
+// room, so they can join it even when it's private
+func (s *Service) RespondToInvite(c context.Context, userId string, request *packets.RespondToInviteRequestMessage) (*packets.Message, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	invite, err := queries.GetRoomInvite(c, db.GetRoomInviteParams{
+		RoomID: int64(request.RoomId),
+		UserID: userId,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return &packets.Message{Type: packets.NewDenyResponseMsg("Invite not found")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = queries.DeleteRoomInvite(c, db.DeleteRoomInviteParams{
+		RoomID: invite.RoomID,
+		UserID: userId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if request.Accept {
+		err := queries.AddRoomMember(c, db.AddRoomMemberParams{
+			RoomID:  invite.RoomID,
+			UserID:  userId,
+			AddedBy: invite.InviterID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &packets.Message{Type: packets.NewOkResponseMsg()}, nil
+}
+
+// Takes the user's membership of the room away, along with its role. Returns
+// whether they had one
+func (s *Service) removeRoomMember(c context.Context, hub *Hub, room Room, userId string) (bool, error) {
+	removed, err := s.repo.queries.DeleteRoomMember(c, db.DeleteRoomMemberParams{
+		RoomID: int64(room.Id),
+		UserID: userId,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if room.Moderators[userId] {
+		hub.Rooms.Update(room.Id, func(room *Room) {
+			*room = room.withRole(userId, RoleMember)
+		})
+	}
+	return removed > 0, nil
+}
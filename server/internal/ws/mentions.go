@@ -0,0 +1,123 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"server/internal/client"
+	"server/internal/db"
+	"server/internal/usernames"
+	"server/pkg/packets"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	maxMentionsPerMessage = 20
+)
+
+// Returns the usernames written as @username in the message, in order and
+// without repeats. An @ preceded by a username character, like in an email
+// address, isn't a mention
+func parseMentions(msg string) []string {
+	names := []string{}
+	previous := rune(0)
+	for i, r := range msg {
+		if r != '@' || usernames.IsAllowedRune(previous) {
+			previous = r
+			continue
+		}
+		previous = r
+
+		end := i + 1
+		for end < len(msg) {
+			next, size := utf8.DecodeRuneInString(msg[end:])
+			if !usernames.IsAllowedRune(next) {
+				break
+			}
+			end += size
+		}
+
+		name := msg[i+1 : end]
+		if name != "" && utf8.RuneCountInString(name) <= usernames.MaxChars && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		if len(names) == maxMentionsPerMessage {
+			break
+		}
+	}
+	return names
+}
+
+// Stores a mention for every existing user named in the message, other than
+// its sender and those who can't join the room, and returns them keyed by the
+// mentioned user id
+func (s *Service) SaveMentions(c context.Context, message db.Message, room Room) (map[string]*packets.MentionMessage, error) {
+	mentions := make(map[string]*packets.MentionMessage)
+	for _, name := range parseMentions(message.Msg) {
+		user, err := s.repo.GetUserByUsername(c, usernames.Key(name))
+		if errors.Is(err, sql.ErrNoRows) && strings.HasSuffix(name, ".") {
+			// The mention ended a sentence
+			user, err = s.repo.GetUserByUsername(c, usernames.Key(strings.TrimRight(name, ".")))
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if user.ID == message.SenderID || mentions[user.ID] != nil {
+			continue
+		}
+		// Mentions carry the message, private rooms keep it to their members
+		allowed, err := s.CanJoinRoom(c, room, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			continue
+		}
+
+		mention, err := s.repo.queries.CreateMention(c, db.CreateMentionParams{
+			MessageID: message.ID,
+			UserID:    user.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		mentions[user.ID] = packets.NewMention(uint64(mention.ID), uint64(message.ID), uint64(message.RoomID), room.Name, message.SenderID, message.SenderUsername, message.Msg, message.CreatedAt)
+	}
+	return mentions, nil
+}
+
+// Notifies every live connection of the mentioned users, whatever room they
+// are in. Users who can't join the room anymore, because it was made private
+// since the mentions were saved, are left out
+func (c *WebSocketClient) sendMentions(ctx context.Context, roomId uint64, mentions map[string]*packets.MentionMessage) {
+	if len(mentions) == 0 {
+		return
+	}
+
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+	for userId := range mentions {
+		allowed, err := c.service.CanJoinRoom(ctx, room, userId)
+		if err != nil {
+			c.logger.Printf("error checking access to room %d: %v", roomId, err)
+		}
+		if !allowed {
+			delete(mentions, userId)
+		}
+	}
+
+	c.hub.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if mention, found := mentions[client.UserId()]; found {
+			client.SocketSendAs(packets.NewMentionPkt(mention), c.id, 0)
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package ws
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	many := []string{}
+	for i := 0; i < maxMentionsPerMessage+5; i++ {
+		many = append(many, fmt.Sprintf("user%d", i))
+	}
+
+	tests := []struct {
+		name string
+		msg  string
+		want []string
+	}{
+		{"no mentions", "hello there", []string{}},
+		{"one mention", "hi @alice", []string{"alice"}},
+		{"start of message", "@bob, look", []string{"bob"}},
+		{"in order without repeats", "@bob @alice @bob", []string{"bob", "alice"}},
+		{"separators are part of the name", "@bob_smith-2.0 hi", []string{"bob_smith-2.0"}},
+		{"sentence end kept for the lookup", "thanks @alice.", []string{"alice."}},
+		{"punctuation ends the name", "(@alice) @bob!", []string{"alice", "bob"}},
+		{"email address", "mail alice@example.com", []string{}},
+		{"lone at", "meet @ noon", []string{}},
+		{"non latin", "привет @алиса", []string{"алиса"}},
+		{"double at", "@@alice", []string{"alice"}},
+		{"too long", "@abcdefghijklmnopqrstu @bob", []string{"bob"}},
+		{"at most the limit", "@" + strings.Join(many, " @"), many[:maxMentionsPerMessage]},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseMentions(test.msg); !slices.Equal(got, test.want) {
+				t.Errorf("parseMentions(%q) = %q, want %q", test.msg, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,471 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"server/internal/db"
+	"server/pkg/packets"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	maxMessageChars = flag.Int("max-message-chars", 4000, "Longest room message that can be sent, in characters")
+
+	maxClientIdChars         = 64
+	maxAttachmentsPerMessage = 10
+)
+
+// Error shown to the client when a room message can't be sent, edited or deleted
+type ChatMessageError struct {
+	reason string
+}
+
+func (e *ChatMessageError) Error() string {
+	return e.reason
+}
+
+// Persists a message sent to a room, so it gets an id other clients can refer to.
+// Replies reference the top level message that started their thread. A message
+// resent with a client id that was already saved isn't saved again, the saved
+// one is returned along with true. Attachments must have been uploaded by the
+// sender and not sent yet. Action messages describe what the sender does, like
+// the ones sent with /me
+func (s *Service) SaveChatMessage(c context.Context, roomId uint64, senderId string, senderUsername string, msg string, action bool, parentId uint64, clientId string, attachmentIds []string) (db.Message, bool, error) {
+	if strings.TrimSpace(msg) == "" && len(attachmentIds) == 0 {
+		return db.Message{}, false, &ChatMessageError{"Message is empty"}
+	}
+	if err := validateMessageLength(msg); err != nil {
+		return db.Message{}, false, err
+	}
+	if len(attachmentIds) > maxAttachmentsPerMessage {
+		reason := fmt.Sprintf("Messages can have at most %d attachments", maxAttachmentsPerMessage)
+		return db.Message{}, false, &ChatMessageError{reason}
+	}
+	if len(clientId) > maxClientIdChars {
+		return db.Message{}, false, &ChatMessageError{"Invalid client id"}
+	}
+
+	params := db.CreateMessageParams{
+		RoomID:         int64(roomId),
+		SenderID:       senderId,
+		SenderUsername: senderUsername,
+		Msg:            msg,
+		Action:         action,
+		CreatedAt:      time.Now().UTC(),
+	}
+
+	if clientId != "" {
+		params.ClientID = sql.NullString{String: clientId, Valid: true}
+
+		existing, err := s.repo.queries.GetMessageByClientId(c, db.GetMessageByClientIdParams{
+			SenderID: senderId,
+			ClientID: params.ClientID,
+		})
+		if err == nil {
+			return existing, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return db.Message{}, false, err
+		}
+	}
+
+	if err := s.checkMuted(c, roomId, senderId); err != nil {
+		return db.Message{}, false, err
+	}
+
+	if parentId != 0 {
+		parent, err := s.repo.queries.GetMessage(c, int64(parentId))
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && uint64(parent.RoomID) != roomId) {
+			return db.Message{}, false, &ChatMessageError{"Thread not found"}
+		}
+		if err != nil {
+			return db.Message{}, false, err
+		}
+		if parent.ParentID.Valid {
+			// Threads are one level deep, replies to a reply go to the same thread
+			parentId = uint64(parent.ParentID.Int64)
+		}
+		params.ParentID = sql.NullInt64{Int64: int64(parentId), Valid: true}
+	}
+
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return db.Message{}, false, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	message, err := queries.CreateMessage(c, params)
+	if err != nil {
+		return db.Message{}, false, err
+	}
+
+	for _, attachmentId := range attachmentIds {
+		_, err := queries.BindAttachment(c, db.BindAttachmentParams{
+			MessageID:  sql.NullInt64{Int64: message.ID, Valid: true},
+			ID:         attachmentId,
+			UploaderID: senderId,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.Message{}, false, &ChatMessageError{"Attachment not found"}
+		}
+		if err != nil {
+			return db.Message{}, false, err
+		}
+	}
+
+	return message, false, tx.Commit()
+}
+
+// Returns the attachments of a message in the order they were sent
+func (s *Service) GetAttachments(c context.Context, messageId uint64) ([]*packets.AttachmentMessage, error) {
+	attachments, err := s.repo.queries.ListMessageAttachments(c, sql.NullInt64{Int64: int64(messageId), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	thumbnails, err := s.repo.queries.ListMessageThumbnails(c, sql.NullInt64{Int64: int64(messageId), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	return attachmentMessages(attachments, thumbnails), nil
+}
+
+// Builds the attachments with their thumbnails, which can belong to any of them
+func attachmentMessages(attachments []db.Attachment, thumbnails []db.AttachmentThumbnail) []*packets.AttachmentMessage {
+	thumbnailsById := make(map[string][]*packets.ThumbnailMessage)
+	for _, thumb := range thumbnails {
+		thumbnailsById[thumb.AttachmentID] = append(thumbnailsById[thumb.AttachmentID], packets.NewThumbnail(uint32(thumb.Size), uint32(thumb.Width), uint32(thumb.Height), thumb.Mime))
+	}
+
+	messages := make([]*packets.AttachmentMessage, 0, len(attachments))
+	for _, attachment := range attachments {
+		message := packets.NewAttachment(attachment.ID, attachment.Filename, attachment.Mime, uint64(attachment.Size))
+		message.Width = uint32(attachment.Width)
+		message.Height = uint32(attachment.Height)
+		message.Thumbnails = thumbnailsById[attachment.ID]
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func validateMessageLength(msg string) error {
+	if utf8.RuneCountInString(msg) > *maxMessageChars {
+		reason := fmt.Sprintf("Message is too long, the limit is %d characters", *maxMessageChars)
+		return &ChatMessageError{reason}
+	}
+	return nil
+}
+
+// Changes the text of a room message, keeping the previous text in its edit
+// history. Only the author and the room moderators can edit a message
+func (s *Service) EditChatMessage(c context.Context, room Room, editorId string, messageId uint64, msg string) (db.Message, error) {
+	if err := validateMessageLength(msg); err != nil {
+		return db.Message{}, err
+	}
+	if err := s.checkMuted(c, room.Id, editorId); err != nil {
+		return db.Message{}, err
+	}
+
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return db.Message{}, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	message, err := getEditableMessage(c, queries, room, editorId, messageId)
+	if err != nil {
+		return db.Message{}, err
+	}
+
+	if strings.TrimSpace(msg) == "" {
+		// The text can only be removed when attachments are left
+		attachments, err := queries.ListMessageAttachments(c, sql.NullInt64{Int64: message.ID, Valid: true})
+		if err != nil {
+			return db.Message{}, err
+		}
+		if len(attachments) == 0 {
+			return db.Message{}, &ChatMessageError{"Message is empty"}
+		}
+	}
+
+	err = queries.CreateMessageEdit(c, db.CreateMessageEditParams{
+		MessageID:   message.ID,
+		PreviousMsg: message.Msg,
+		EditedBy:    editorId,
+	})
+	if err != nil {
+		return db.Message{}, err
+	}
+
+	message, err = queries.UpdateMessage(c, db.UpdateMessageParams{
+		Msg:      msg,
+		EditedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		ID:       message.ID,
+	})
+	if err != nil {
+		return db.Message{}, err
+	}
+	if err := redactMessageEvents(c, queries, message.ID, msg, false); err != nil {
+		return db.Message{}, err
+	}
+
+	return message, tx.Commit()
+}
+
+// Deletes a room message along with its edit history, reactions and mentions. The row is kept so
+// message ids never get reused
+func (s *Service) DeleteChatMessage(c context.Context, room Room, deleterId string, messageId uint64) (db.Message, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return db.Message{}, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	message, err := getEditableMessage(c, queries, room, deleterId, messageId)
+	if err != nil {
+		return db.Message{}, err
+	}
+
+	if err := queries.DeleteMessageEdits(c, message.ID); err != nil {
+		return db.Message{}, err
+	}
+	if err := queries.DeleteMessageReactions(c, message.ID); err != nil {
+		return db.Message{}, err
+	}
+	if err := queries.DeleteMessageMentions(c, message.ID); err != nil {
+		return db.Message{}, err
+	}
+	if err := queries.DeleteMessageLinkPreviews(c, message.ID); err != nil {
+		return db.Message{}, err
+	}
+	if err := queries.DeleteMessage(c, message.ID); err != nil {
+		return db.Message{}, err
+	}
+	if err := redactMessageEvents(c, queries, message.ID, "", true); err != nil {
+		return db.Message{}, err
+	}
+
+	return message, tx.Commit()
+}
+
+func getEditableMessage(c context.Context, queries *db.Queries, room Room, userId string, messageId uint64) (db.Message, error) {
+	message, err := queries.GetMessage(c, int64(messageId))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && uint64(message.RoomID) != room.Id) {
+		return db.Message{}, &ChatMessageError{"Message not found"}
+	}
+	if err != nil {
+		return db.Message{}, err
+	}
+
+	if message.SenderID != userId && !room.IsModerator(userId) {
+		return db.Message{}, &ChatMessageError{"Not allowed to change this message"}
+	}
+
+	return message, nil
+}
+
+// Sends the chat message to the room, or runs the command it starts with
+func (c *WebSocketClient) sendChat(ctx context.Context, roomId uint64, chat *packets.ChatMessage) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	if name, args, isCommand := parseCommand(chat.Msg); isCommand {
+		args.chat = chat
+		c.runCommand(ctx, room, name, args)
+		return
+	}
+	if strings.HasPrefix(chat.Msg, "//") {
+		chat.Msg = chat.Msg[1:]
+	}
+	c.postChat(ctx, room, chat)
+}
+
+// Saves the chat message sent by this client, broadcasts it to the room with
+// its server assigned id and tells the sender which id it got. A message the
+// client resends after a reconnect is only acknowledged again
+func (c *WebSocketClient) postChat(ctx context.Context, room Room, chat *packets.ChatMessage) {
+	roomId := room.Id
+	attachmentIds := make([]string, 0, len(chat.Attachments))
+	for _, attachment := range chat.Attachments {
+		if !slices.Contains(attachmentIds, attachment.Id) {
+			attachmentIds = append(attachmentIds, attachment.Id)
+		}
+	}
+
+	saved, duplicate, err := c.service.SaveChatMessage(ctx, roomId, c.userId, c.username, chat.Msg, chat.Action, chat.ParentId, chat.ClientId, attachmentIds)
+	if err != nil {
+		c.denyChatChange(err, "Unable to send message")
+		return
+	}
+	if duplicate {
+		c.SocketSendAs(packets.NewChatSent(uint64(saved.ID), chat.ClientId, saved.CreatedAt), c.id, roomId)
+		return
+	}
+
+	c.stopTyping(roomId)
+
+	mentions, err := c.service.SaveMentions(ctx, saved, room)
+	if err != nil {
+		c.logger.Printf("error saving mentions of message %d: %v", saved.ID, err)
+	}
+
+	messageId := uint64(saved.ID)
+	message := &packets.Packet_Chat{Chat: chatFromMessage(saved)}
+	for userId := range mentions {
+		message.Chat.MentionIds = append(message.Chat.MentionIds, userId)
+	}
+	slices.Sort(message.Chat.MentionIds)
+	if len(attachmentIds) > 0 {
+		if message.Chat.Attachments, err = c.service.GetAttachments(ctx, messageId); err != nil {
+			c.logger.Printf("error getting attachments of message %d: %v", messageId, err)
+		}
+	}
+	room.LastMessages.Add(StoragedMessage{
+		Timestamp:      saved.CreatedAt,
+		Msg:            message,
+		SenderUsername: c.username,
+		SenderId:       c.id,
+		SenderUserId:   c.userId,
+	}, messageId)
+
+	// The sender gets the id of its message instead of the message
+	c.broadcastEventEcho(ctx, message, roomId, func(event *packets.Packet) *packets.Packet {
+		return &packets.Packet{
+			SenderId: c.id,
+			RoomId:   roomId,
+			Seq:      event.Seq,
+			Msg:      packets.NewChatSent(messageId, chat.ClientId, saved.CreatedAt),
+		}
+	})
+	c.sendMentions(ctx, roomId, mentions)
+	c.unfurlLinks(roomId, saved)
+
+	if saved.ParentID.Valid {
+		c.sendThreadUpdated(ctx, roomId, uint64(saved.ParentID.Int64))
+	}
+
+	// Everything before their own message has been seen by the sender
+	if moved, err := c.service.MarkRead(ctx, roomId, c.userId, messageId); err != nil {
+		c.logger.Printf("error marking message %d as read: %v", messageId, err)
+	} else if moved {
+		c.sendReadPosition(roomId, messageId)
+	}
+}
+
+func (c *WebSocketClient) editChat(ctx context.Context, roomId uint64, edit *packets.EditChatMessage) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	saved, err := c.service.EditChatMessage(ctx, room, c.userId, edit.MessageId, edit.Msg)
+	if err != nil {
+		c.denyChatChange(err, "Unable to edit message")
+		return
+	}
+
+	room.LastMessages.Update(edit.MessageId, func(sm *StoragedMessage) {
+		chat := proto.Clone(sm.Msg.Chat).(*packets.ChatMessage)
+		chat.Msg = saved.Msg
+		chat.EditedAt = timestamppb.New(saved.EditedAt.Time)
+		sm.Msg = &packets.Packet_Chat{Chat: chat}
+	})
+
+	message := packets.NewEditChat(edit.MessageId, saved.Msg, saved.EditedAt.Time)
+	c.broadcastEvent(ctx, message, roomId)
+	c.unfurlLinks(roomId, saved)
+}
+
+func (c *WebSocketClient) deleteChat(ctx context.Context, roomId uint64, deletion *packets.DeleteChatMessage) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	deleted, err := c.service.DeleteChatMessage(ctx, room, c.userId, deletion.MessageId)
+	if err != nil {
+		c.denyChatChange(err, "Unable to delete message")
+		return
+	}
+
+	room.LastMessages.Remove(deletion.MessageId)
+
+	message := packets.NewDeleteChat(deletion.MessageId)
+	c.broadcastEvent(ctx, message, roomId)
+
+	if deleted.ParentID.Valid {
+		c.sendThreadUpdated(ctx, roomId, uint64(deleted.ParentID.Int64))
+	}
+}
+
+// Sends the cached room messages to this client, with the reactions and thread
+// reply counts they have now
+func (c *WebSocketClient) replayMessages(ctx context.Context, room Room) {
+	lastMessages := room.OrderLastMessages(room.LastMessages)
+	if len(lastMessages) == 0 {
+		return
+	}
+
+	firstId := lastMessages[0].Msg.Chat.Id
+	reactions, err := c.service.GetRoomReactions(ctx, room.Id, firstId)
+	if err != nil {
+		c.logger.Printf("Error getting reactions of room %d: %v", room.Id, err)
+	}
+	replyCounts, err := c.service.GetRoomReplyCounts(ctx, room.Id, firstId)
+	if err != nil {
+		c.logger.Printf("Error getting reply counts of room %d: %v", room.Id, err)
+	}
+
+	for _, sm := range lastMessages {
+		message := sm.Msg
+		messageReactions, hasReactions := reactions[sm.Msg.Chat.Id]
+		replyCount, hasReplies := replyCounts[sm.Msg.Chat.Id]
+		if hasReactions || hasReplies {
+			chat := proto.Clone(sm.Msg.Chat).(*packets.ChatMessage)
+			chat.Reactions = messageReactions
+			chat.ReplyCount = replyCount
+			message = &packets.Packet_Chat{Chat: chat}
+		}
+		c.sendAsWaiting(message, sm.SenderId, room.Id)
+	}
+}
+
+func chatFromMessage(message db.Message) *packets.ChatMessage {
+	chat := packets.NewSavedChat(uint64(message.ID), message.SenderID, message.SenderUsername, message.Msg, message.CreatedAt).(*packets.Packet_Chat).Chat
+	if message.ParentID.Valid {
+		chat.ParentId = uint64(message.ParentID.Int64)
+	}
+	if message.EditedAt.Valid {
+		chat.EditedAt = timestamppb.New(message.EditedAt.Time)
+	}
+	if message.ClientID.Valid {
+		chat.ClientId = message.ClientID.String
+	}
+	chat.Action = message.Action
+	return chat
+}
+
+// Tells the client why its request was refused, hiding internal errors
+func (c *WebSocketClient) denyChatChange(err error, fallback string) {
+	var chatErr *ChatMessageError
+	if errors.As(err, &chatErr) {
+		c.SocketSend(packets.NewDenyResponsePkt(chatErr.reason))
+		return
+	}
+
+	c.logger.Printf("error handling chat message: %v", err)
+	c.SocketSend(packets.NewDenyResponsePkt(fallback))
+}
@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	defaultMuteDuration = 10 * time.Minute
+	maxMuteDuration     = 30 * 24 * time.Hour
+)
+
+// Mutes a member of the room for the duration, replacing any mute they had
+func (s *Service) MuteUser(c context.Context, room Room, moderatorId string, userId string, duration time.Duration) error {
+	if duration <= 0 || duration > maxMuteDuration {
+		reason := fmt.Sprintf("Mutes last between 1 second and %s", formatDuration(maxMuteDuration))
+		return &ChatMessageError{reason}
+	}
+
+	return s.repo.queries.UpsertRoomMute(c, db.UpsertRoomMuteParams{
+		RoomID:     int64(room.Id),
+		UserID:     userId,
+		MutedBy:    moderatorId,
+		MutedUntil: time.Now().Add(duration).UTC(),
+	})
+}
+
+// Lifts the mute of a member of the room. Returns false when they weren't muted
+func (s *Service) UnmuteUser(c context.Context, room Room, userId string) (bool, error) {
+	removed, err := s.repo.queries.DeleteRoomMute(c, db.DeleteRoomMuteParams{
+		RoomID: int64(room.Id),
+		UserID: userId,
+	})
+	return removed > 0, err
+}
+
+// Returns an error to show the user when they are muted in the room
+func (s *Service) checkMuted(c context.Context, roomId uint64, userId string) error {
+	mute, err := s.repo.queries.GetRoomMute(c, db.GetRoomMuteParams{
+		RoomID:     int64(roomId),
+		UserID:     userId,
+		MutedUntil: time.Now().UTC(),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("You are muted in this room for %s", formatDuration(time.Until(mute.MutedUntil)))
+	return &ChatMessageError{reason}
+}
+
+// Deletes the mutes that are over and returns how many
+func (s *Service) RemoveExpiredMutes(c context.Context) (int64, error) {
+	return s.repo.queries.DeleteExpiredRoomMutes(c, time.Now().UTC())
+}
+
+// Removes every connection the user has in the room, telling them why. Returns
+// how many connections were removed
+func (c *WebSocketClient) kickFromRoom(room Room, userId string, notice string) int {
+	kicked := []client.ClientInterfacer{}
+	room.Clients.ForEach(func(clientId uint64, member client.ClientInterfacer) {
+		if member.UserId() == userId {
+			kicked = append(kicked, member)
+		}
+	})
+
+	// Outside ForEach, the hub needs the room's clients to remove them
+	for _, member := range kicked {
+		member.SocketSendAs(packets.NewSystem(notice), member.Id(), room.Id)
+		c.hub.LeaveRoomChan <- Membership{Client: member, RoomId: room.Id}
+	}
+	return len(kicked)
+}
+
+// Parses durations like 90s, 30m or 2h, plus days like 2d
+func parseMuteDuration(text string) (time.Duration, bool) {
+	if days, found := strings.CutSuffix(text, "d"); found {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, false
+		}
+		return time.Duration(count) * 24 * time.Hour, true
+	}
+
+	duration, err := time.ParseDuration(text)
+	return duration, err == nil
+}
+
+// Formats the duration to the second, without the zero units Duration.String
+// adds, so 10 minutes is 10m rather than 10m0s
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	if duration >= 24*time.Hour && duration%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", duration/(24*time.Hour))
+	}
+
+	text := duration.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
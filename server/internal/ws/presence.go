@@ -0,0 +1,248 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"log"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	idleAfter = flag.Duration("idle-after", 5*time.Minute, "How long a user can go without activity before showing as idle")
+)
+
+// Presence of every user with at least one open connection, keyed by user id
+type presenceRegistry struct {
+	mutex sync.Mutex
+	users map[string]*userPresence
+}
+
+type userPresence struct {
+	// Status chosen by the user, online unless they set away or do not disturb
+	manual      packets.PresenceStatus
+	status      packets.PresenceStatus
+	connections map[uint64]*WebSocketClient
+}
+
+func newPresenceRegistry() *presenceRegistry {
+	return &presenceRegistry{
+		users: make(map[string]*userPresence),
+	}
+}
+
+// The status the user shows given their chosen status and how active their
+// connections are
+func (p *userPresence) currentStatus() packets.PresenceStatus {
+	if len(p.connections) == 0 {
+		return packets.PresenceStatus_PRESENCE_OFFLINE
+	}
+	if p.manual != packets.PresenceStatus_PRESENCE_ONLINE {
+		return p.manual
+	}
+	for _, connection := range p.connections {
+		if time.Since(connection.LastActive()) < *idleAfter {
+			return packets.PresenceStatus_PRESENCE_ONLINE
+		}
+	}
+	return packets.PresenceStatus_PRESENCE_IDLE
+}
+
+// Adds a new connection to the user's presence, loading the status they chose
+// last time when they weren't connected yet
+func (s *Service) PresenceConnected(c context.Context, hub *Hub, connection *WebSocketClient) {
+	manual := packets.PresenceStatus_PRESENCE_ONLINE
+	saved, err := s.repo.queries.GetPresence(c, connection.UserId())
+	if err == nil {
+		manual = packets.PresenceStatus(saved.Status)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		connection.logger.Printf("Error getting presence: %v", err)
+	}
+
+	hub.presence.mutex.Lock()
+	presence, found := hub.presence.users[connection.UserId()]
+	if !found {
+		presence = &userPresence{
+			manual:      manual,
+			status:      packets.PresenceStatus_PRESENCE_OFFLINE,
+			connections: make(map[uint64]*WebSocketClient),
+		}
+		hub.presence.users[connection.UserId()] = presence
+	}
+	presence.connections[connection.Id()] = connection
+	hub.presence.mutex.Unlock()
+
+	s.UpdatePresence(c, hub, connection.UserId(), nil)
+}
+
+// Removes a closed connection from the user's presence. The rooms it was in are
+// told about the change even though it has already left them
+func (s *Service) PresenceDisconnected(c context.Context, hub *Hub, connection *WebSocketClient) {
+	hub.presence.mutex.Lock()
+	if presence, found := hub.presence.users[connection.UserId()]; found {
+		delete(presence.connections, connection.Id())
+	}
+	hub.presence.mutex.Unlock()
+
+	s.UpdatePresence(c, hub, connection.UserId(), connection.RoomIds())
+}
+
+// Changes the status the user chose for themselves
+func (s *Service) SetPresence(c context.Context, hub *Hub, userId string, status packets.PresenceStatus) error {
+	switch status {
+	case packets.PresenceStatus_PRESENCE_ONLINE, packets.PresenceStatus_PRESENCE_AWAY, packets.PresenceStatus_PRESENCE_DO_NOT_DISTURB:
+	default:
+		return &ChatMessageError{"Invalid presence"}
+	}
+
+	err := s.repo.queries.SetPresenceStatus(c, db.SetPresenceStatusParams{
+		UserID: userId,
+		Status: int64(status),
+	})
+	if err != nil {
+		return err
+	}
+
+	hub.presence.mutex.Lock()
+	if presence, found := hub.presence.users[userId]; found {
+		presence.manual = status
+	}
+	hub.presence.mutex.Unlock()
+
+	s.UpdatePresence(c, hub, userId, nil)
+	return nil
+}
+
+// Recomputes the user's presence and, when it changed, pushes it to every
+// connection sharing a room with the user, plus the rooms given. Returns
+// whether it changed
+func (s *Service) UpdatePresence(c context.Context, hub *Hub, userId string, roomIds []uint64) bool {
+	hub.presence.mutex.Lock()
+	presence, found := hub.presence.users[userId]
+	if !found {
+		hub.presence.mutex.Unlock()
+		return false
+	}
+
+	status := presence.currentStatus()
+	changed := status != presence.status
+	presence.status = status
+	for _, connection := range presence.connections {
+		roomIds = append(roomIds, connection.RoomIds()...)
+	}
+	if status == packets.PresenceStatus_PRESENCE_OFFLINE {
+		delete(hub.presence.users, userId)
+	}
+	hub.presence.mutex.Unlock()
+
+	if !changed {
+		return false
+	}
+
+	var lastSeen *time.Time
+	if status == packets.PresenceStatus_PRESENCE_OFFLINE {
+		now := time.Now().UTC()
+		lastSeen = &now
+
+		err := s.repo.queries.SetLastSeen(c, db.SetLastSeenParams{
+			UserID:     userId,
+			LastSeenAt: sql.NullTime{Time: now, Valid: true},
+		})
+		if err != nil {
+			log.Printf("error saving last seen of user %s: %v", userId, err)
+		}
+	}
+
+	message := packets.NewPresence(userId, status, lastSeen)
+	hub.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		sharesRoom := slices.ContainsFunc(client.RoomIds(), func(roomId uint64) bool {
+			return slices.Contains(roomIds, roomId)
+		})
+		if sharesRoom || client.UserId() == userId {
+			client.SocketSendAs(message, clientId, 0)
+		}
+	})
+	return true
+}
+
+// Moves users between online and idle as their activity changes. Returns how
+// many users changed
+func (s *Service) UpdateIdlePresence(c context.Context, hub *Hub) (int64, error) {
+	hub.presence.mutex.Lock()
+	userIds := make([]string, 0, len(hub.presence.users))
+	for userId := range hub.presence.users {
+		userIds = append(userIds, userId)
+	}
+	hub.presence.mutex.Unlock()
+
+	changed := int64(0)
+	for _, userId := range userIds {
+		if s.UpdatePresence(c, hub, userId, nil) {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// Returns the presence of the user, with when they were last seen if they are
+// offline
+func (s *Service) GetPresence(c context.Context, hub *Hub, userId string) packets.Pkt {
+	hub.presence.mutex.Lock()
+	presence, found := hub.presence.users[userId]
+	status := packets.PresenceStatus_PRESENCE_OFFLINE
+	if found {
+		status = presence.status
+	}
+	hub.presence.mutex.Unlock()
+
+	if found {
+		return packets.NewPresence(userId, status, nil)
+	}
+
+	var lastSeen *time.Time
+	if saved, err := s.repo.queries.GetPresence(c, userId); err == nil && saved.LastSeenAt.Valid {
+		lastSeen = &saved.LastSeenAt.Time
+	}
+	return packets.NewPresence(userId, status, lastSeen)
+}
+
+// Marks the user as active, bringing them back from idle right away
+func (c *WebSocketClient) markActive(ctx context.Context) {
+	c.lastActive.Store(time.Now().UnixNano())
+
+	c.hub.presence.mutex.Lock()
+	presence, found := c.hub.presence.users[c.userId]
+	idle := found && presence.status == packets.PresenceStatus_PRESENCE_IDLE
+	c.hub.presence.mutex.Unlock()
+
+	if idle {
+		c.service.UpdatePresence(ctx, c.hub, c.userId, nil)
+	}
+}
+
+func (c *WebSocketClient) setPresence(ctx context.Context, status packets.PresenceStatus) {
+	if err := c.service.SetPresence(ctx, c.hub, c.userId, status); err != nil {
+		c.denyChatChange(err, "Unable to set presence")
+	}
+}
+
+// Tells the room about this client's presence and tells the client about the
+// presence of everyone already in the room
+func (c *WebSocketClient) sendRoomPresence(ctx context.Context, room Room) {
+	c.broadcast(c.service.GetPresence(ctx, c.hub, c.userId), room.Id)
+
+	sent := map[string]bool{c.userId: true}
+	room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if sent[client.UserId()] {
+			return
+		}
+		sent[client.UserId()] = true
+		c.sendAsWaiting(c.service.GetPresence(ctx, c.hub, client.UserId()), clientId, room.Id)
+	})
+}
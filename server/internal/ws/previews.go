@@ -0,0 +1,127 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"server/internal/db"
+	"server/internal/unfurl"
+	"server/pkg/packets"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// Longest the links of a message are fetched for, all of them together
+	unfurlMessageTimeout = 20 * time.Second
+)
+
+// Replaces the link previews of the message, unless its text changed since the
+// links were found or it was deleted. Returns the previews it has now, and
+// whether they are different from before
+func (s *Service) SetLinkPreviews(c context.Context, messageId uint64, msg string, previews []unfurl.Preview) ([]*packets.LinkPreviewMessage, bool, error) {
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	message, err := queries.GetMessage(c, int64(messageId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if message.DeletedAt.Valid || message.Msg != msg {
+		// A newer edit unfurls its own links
+		return nil, false, nil
+	}
+
+	rows := make([]db.MessageLinkPreview, 0, len(previews))
+	for i, preview := range previews {
+		rows = append(rows, db.MessageLinkPreview{
+			MessageID:   message.ID,
+			Url:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageUrl:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+			Position:    int64(i),
+		})
+	}
+
+	current, err := queries.ListMessageLinkPreviews(c, message.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if slices.Equal(current, rows) {
+		return nil, false, nil
+	}
+
+	if err := queries.DeleteMessageLinkPreviews(c, message.ID); err != nil {
+		return nil, false, err
+	}
+	for _, row := range rows {
+		err := queries.CreateMessageLinkPreview(c, db.CreateMessageLinkPreviewParams(row))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	return linkPreviewMessages(rows), true, tx.Commit()
+}
+
+func linkPreviewMessages(previews []db.MessageLinkPreview) []*packets.LinkPreviewMessage {
+	messages := make([]*packets.LinkPreviewMessage, 0, len(previews))
+	for _, preview := range previews {
+		messages = append(messages, packets.NewLinkPreview(preview.Url, preview.Title, preview.Description, preview.ImageUrl, preview.SiteName))
+	}
+	return messages
+}
+
+// Fetches the previews of the links in the message in the background, then adds
+// them to the message for everyone in the room. Edits unfurl again, so the
+// previews follow the links of the current text
+func (c *WebSocketClient) unfurlLinks(roomId uint64, message db.Message) {
+	links := unfurl.FindLinks(message.Msg)
+	if len(links) == 0 && !message.EditedAt.Valid {
+		return
+	}
+
+	go func() {
+		fetchCtx, cancel := context.WithTimeout(context.Background(), unfurlMessageTimeout)
+		previews := c.service.links.Previews(fetchCtx, links)
+		cancel()
+
+		ctx := context.Background()
+		messageId := uint64(message.ID)
+		updated, changed, err := c.service.SetLinkPreviews(ctx, messageId, message.Msg, previews)
+		if err != nil {
+			c.logger.Printf("error saving link previews of message %d: %v", messageId, err)
+			return
+		}
+		if !changed {
+			return
+		}
+
+		room, found := c.hub.Rooms.Get(roomId)
+		if !found {
+			return
+		}
+		room.LastMessages.Update(messageId, func(sm *StoragedMessage) {
+			if sm.Msg.Chat.Msg != message.Msg {
+				// Edited while the links were fetched, its own unfurl sets the previews
+				return
+			}
+			chat := proto.Clone(sm.Msg.Chat).(*packets.ChatMessage)
+			chat.Previews = updated
+			sm.Msg = &packets.Packet_Chat{Chat: chat}
+		})
+
+		c.broadcastEvent(ctx, packets.NewMessageUpdated(messageId, updated), roomId)
+	}()
+}
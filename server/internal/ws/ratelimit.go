@@ -0,0 +1,256 @@
+package ws
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"server/internal/db"
+	"server/pkg/packets"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+var (
+	connectionChatRate  = flag.Float64("connection-chat-rate", 5, "Chat packets per second a connection can send on average")
+	connectionChatBurst = flag.Int("connection-chat-burst", 10, "Chat packets a connection can send at once")
+	userChatRate        = flag.Float64("user-chat-rate", 8, "Chat packets per second a user can send on average, across their connections")
+	userChatBurst       = flag.Int("user-chat-burst", 15, "Chat packets a user can send at once, across their connections")
+	roomChatRate        = flag.Float64("room-chat-rate", 20, "Chat packets per second a room accepts on average, from all its members")
+	roomChatBurst       = flag.Int("room-chat-burst", 40, "Chat packets a room accepts at once, from all its members")
+	muteAfter           = flag.Int("mute-after", 5, "Rate limit violations within a minute before the user is muted. Reaching it again while muted disconnects them")
+	muteDuration        = flag.Duration("mute-duration", time.Minute, "How long users who keep going over the rate limits are muted")
+
+	violationWindow = time.Minute
+	maxSlowMode     = time.Hour
+)
+
+// Refills at rate tokens per second up to burst, each allowed packet takes one
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Rate limit state of every user and room that sent chat packets recently
+type rateLimiter struct {
+	mutex sync.Mutex
+	users map[string]*userLimits
+	rooms map[uint64]*tokenBucket
+}
+
+type userLimits struct {
+	bucket     *tokenBucket
+	violations []time.Time
+	mutedUntil time.Time
+
+	// When the user last sent a message to each room, for slow mode
+	lastChat map[uint64]time.Time
+	lastSeen time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		users: make(map[string]*userLimits),
+		rooms: make(map[uint64]*tokenBucket),
+	}
+}
+
+// Decides whether a chat packet goes through. Returns the reason to give the
+// sender when it doesn't, and whether they should be disconnected. The
+// connection bucket belongs to the caller's connection. roomId is zero for
+// packets outside of rooms, and slowMode only applies to new messages
+func (l *rateLimiter) check(connection *tokenBucket, userId string, roomId uint64, slowMode time.Duration, now time.Time) (string, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	user, found := l.users[userId]
+	if !found {
+		user = &userLimits{
+			bucket:   newTokenBucket(*userChatRate, *userChatBurst, now),
+			lastChat: make(map[uint64]time.Time),
+		}
+		l.users[userId] = user
+	}
+	user.lastSeen = now
+
+	if now.Before(user.mutedUntil) {
+		if user.violate(now) {
+			return "Disconnected for sending messages too fast", true
+		}
+		wait := int(math.Ceil(user.mutedUntil.Sub(now).Seconds()))
+		return fmt.Sprintf("Muted for %d seconds for sending messages too fast", wait), false
+	}
+
+	if slowMode > 0 {
+		if next := user.lastChat[roomId].Add(slowMode); now.Before(next) {
+			user.violate(now)
+			wait := int(math.Ceil(next.Sub(now).Seconds()))
+			return fmt.Sprintf("Slow mode is on, wait %d seconds", wait), false
+		}
+	}
+
+	allowed := connection.take(now) && user.bucket.take(now)
+	if allowed && roomId != 0 {
+		room, found := l.rooms[roomId]
+		if !found {
+			room = newTokenBucket(*roomChatRate, *roomChatBurst, now)
+			l.rooms[roomId] = room
+		}
+		if !room.take(now) {
+			// Busy room, not the user's fault
+			return "Too many messages in this room, try again shortly", false
+		}
+	}
+	if !allowed {
+		if user.violate(now) {
+			user.mutedUntil = now.Add(*muteDuration)
+			return fmt.Sprintf("Muted for %d seconds for sending messages too fast", int(muteDuration.Seconds())), false
+		}
+		return "You are sending messages too fast", false
+	}
+
+	if slowMode > 0 {
+		user.lastChat[roomId] = now
+	}
+	return "", false
+}
+
+// Records a violation and returns whether the user reached the limit of
+// violations within the window, starting over when they did
+func (u *userLimits) violate(now time.Time) bool {
+	recent := u.violations[:0]
+	for _, at := range u.violations {
+		if now.Sub(at) < violationWindow {
+			recent = append(recent, at)
+		}
+	}
+	u.violations = append(recent, now)
+
+	if len(u.violations) < *muteAfter {
+		return false
+	}
+	u.violations = u.violations[:0]
+	return true
+}
+
+// Forgets users and rooms that haven't sent anything in a while. Returns how
+// many were removed
+func (h *Hub) PruneRateLimits() int64 {
+	h.limits.mutex.Lock()
+	defer h.limits.mutex.Unlock()
+
+	now := time.Now()
+	removed := int64(0)
+	for userId, user := range h.limits.users {
+		if now.Sub(user.lastSeen) > maxSlowMode && now.After(user.mutedUntil) {
+			delete(h.limits.users, userId)
+			removed++
+		}
+	}
+	for roomId, room := range h.limits.rooms {
+		// Full again, so forgetting it changes nothing
+		if now.Sub(room.last).Seconds()*room.rate >= room.burst {
+			delete(h.limits.rooms, roomId)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Changes how long members of the room wait between two messages. Only the
+// room moderators can change it
+func (s *Service) SetSlowMode(c context.Context, hub *Hub, room Room, userId string, seconds uint32) (Room, error) {
+	if !room.IsModerator(userId) {
+		return Room{}, &ChatMessageError{"Only moderators can change slow mode"}
+	}
+	slowMode := time.Duration(seconds) * time.Second
+	if slowMode > maxSlowMode {
+		reason := fmt.Sprintf("Slow mode can be at most %d seconds", int(maxSlowMode.Seconds()))
+		return Room{}, &ChatMessageError{reason}
+	}
+
+	err := s.repo.queries.SetRoomSlowMode(c, db.SetRoomSlowModeParams{
+		SlowModeSeconds: int64(seconds),
+		ID:              int64(room.Id),
+	})
+	if err != nil {
+		return Room{}, err
+	}
+
+	room, found := hub.Rooms.Update(room.Id, func(room *Room) {
+		room.SlowMode = slowMode
+	})
+	if !found {
+		return Room{}, &ChatMessageError{"Room not found"}
+	}
+	return room, nil
+}
+
+// Whether packets of this type count towards the chat rate limits
+func isRateLimited(message packets.Pkt) bool {
+	switch message.(type) {
+	case *packets.Packet_Chat, *packets.Packet_EditChat, *packets.Packet_DeleteChat,
+		*packets.Packet_AddReaction, *packets.Packet_RemoveReaction, *packets.Packet_DirectMessage:
+		return true
+	}
+	return false
+}
+
+// Applies the rate limits to a chat packet from this client, telling the client
+// when it was refused. Returns whether the packet can go through
+func (c *WebSocketClient) allowChatPacket(roomId uint64, message packets.Pkt) bool {
+	var slowMode time.Duration
+	if _, isChat := message.(*packets.Packet_Chat); isChat && roomId != 0 {
+		if room, found := c.hub.Rooms.Get(roomId); found && !room.IsModerator(c.userId) {
+			slowMode = room.SlowMode
+		}
+	}
+
+	reason, disconnect := c.hub.limits.check(c.chatLimit, c.userId, roomId, slowMode, time.Now())
+	if reason == "" {
+		return true
+	}
+
+	c.SocketSend(packets.NewDenyResponsePkt(reason))
+	if disconnect {
+		c.CloseWithCode(websocket.ClosePolicyViolation, reason, "rate limits exceeded while muted")
+	}
+	return false
+}
+
+func (c *WebSocketClient) setSlowMode(ctx context.Context, roomId uint64, seconds uint32) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		return
+	}
+
+	if _, err := c.service.SetSlowMode(ctx, c.hub, room, c.userId, seconds); err != nil {
+		c.denyChatChange(err, "Unable to change slow mode")
+		return
+	}
+
+	c.broadcastEvent(ctx, packets.NewSlowMode(seconds), roomId)
+}
@@ -0,0 +1,115 @@
+package ws
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name string
+		// Seconds after the bucket was created at which a token is taken
+		takes []float64
+		want  []bool
+	}{
+		{"burst", []float64{0, 0, 0, 0}, []bool{true, true, true, false}},
+		{"refills at the rate", []float64{0, 0, 0, 0.4, 0.5}, []bool{true, true, true, false, true}},
+		{"refills up to the burst", []float64{0, 0, 0, 10, 10, 10, 10}, []bool{true, true, true, true, true, true, false}},
+		{"steady at the rate", []float64{0, 0.5, 1, 1.5, 2}, []bool{true, true, true, true, true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bucket := newTokenBucket(2, 3, start)
+			for i, at := range test.takes {
+				now := start.Add(time.Duration(at * float64(time.Second)))
+				if got := bucket.take(now); got != test.want[i] {
+					t.Errorf("take %d at %.1fs = %v, want %v", i, at, got, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterEscalates(t *testing.T) {
+	start := time.Unix(0, 0)
+	steps := []struct {
+		name string
+		// Chat packets sent at once
+		packets    int
+		wantReason string
+		disconnect bool
+	}{
+		{"within the connection burst", *connectionChatBurst, "", false},
+		{"over the limit", *muteAfter - 1, "You are sending messages too fast", false},
+		{"too many violations", 1, "Muted for", false},
+		{"still muted", *muteAfter - 1, "Muted for", false},
+		{"keeps going while muted", 1, "Disconnected", true},
+	}
+
+	limiter := newRateLimiter()
+	connection := newTokenBucket(*connectionChatRate, *connectionChatBurst, start)
+	for _, step := range steps {
+		for i := 0; i < step.packets; i++ {
+			reason, disconnect := limiter.check(connection, "user", 1, 0, start)
+			if !strings.HasPrefix(reason, step.wantReason) || (step.wantReason == "" && reason != "") {
+				t.Fatalf("%s: packet %d reason = %q, want %q", step.name, i, reason, step.wantReason)
+			}
+			if disconnect != (step.disconnect && i == step.packets-1) {
+				t.Fatalf("%s: packet %d disconnect = %v", step.name, i, disconnect)
+			}
+		}
+	}
+}
+
+func TestRateLimiterLimits(t *testing.T) {
+	start := time.Unix(0, 0)
+	tests := []struct {
+		name string
+		// Connections of the same user, each sending its burst at once
+		connections int
+		slowMode    time.Duration
+		// Reason given to the first packet that didn't go through
+		wantReason string
+	}{
+		{"one connection", 1, 0, ""},
+		{"user limit across connections", 2, 0, "You are sending messages too fast"},
+		{"slow mode", 1, 10 * time.Second, "Slow mode is on, wait 10 seconds"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			limiter := newRateLimiter()
+			reason := ""
+			for c := 0; c < test.connections; c++ {
+				connection := newTokenBucket(*connectionChatRate, *connectionChatBurst, start)
+				for i := 0; i < *connectionChatBurst; i++ {
+					if denied, _ := limiter.check(connection, "user", 1, test.slowMode, start); reason == "" {
+						reason = denied
+					}
+				}
+			}
+			if reason != test.wantReason {
+				t.Errorf("reason = %q, want %q", reason, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestRateLimiterBusyRoom(t *testing.T) {
+	start := time.Unix(0, 0)
+	limiter := newRateLimiter()
+	reason := ""
+	// Enough users, each within their own limits, to fill the room's burst
+	for u := 0; u <= *roomChatBurst / *connectionChatBurst; u++ {
+		connection := newTokenBucket(*connectionChatRate, *connectionChatBurst, start)
+		for i := 0; i < *connectionChatBurst; i++ {
+			reason, _ = limiter.check(connection, string(rune('a'+u)), 1, 0, start)
+		}
+	}
+	if want := "Too many messages in this room, try again shortly"; reason != want {
+		t.Errorf("reason = %q, want %q", reason, want)
+	}
+}
@@ -0,0 +1,162 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"server/internal/db"
+	"server/pkg/packets"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	maxEmojiChars       = 16
+	maxEmojisPerMessage = 20
+)
+
+// Adds or removes the user's reaction to a room message and returns the new
+// reaction summaries of that message
+func (s *Service) SetReaction(c context.Context, roomId uint64, userId string, messageId uint64, emoji string, add bool) ([]*packets.ReactionSummary, error) {
+	if err := validateEmoji(emoji); err != nil {
+		return nil, err
+	}
+	if err := s.checkMuted(c, roomId, userId); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.repo.dbPool.BeginTx(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	queries := s.repo.queries.WithTx(tx)
+
+	message, err := queries.GetMessage(c, int64(messageId))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && uint64(message.RoomID) != roomId) {
+		return nil, &ChatMessageError{"Message not found"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	params := db.AddReactionParams{
+		MessageID: message.ID,
+		UserID:    userId,
+		Emoji:     emoji,
+	}
+	if add {
+		if err := queries.AddReaction(c, params); err != nil {
+			return nil, err
+		}
+
+		emojis, err := queries.CountReactionEmojis(c, message.ID)
+		if err != nil {
+			return nil, err
+		}
+		if emojis > int64(maxEmojisPerMessage) {
+			reason := fmt.Sprintf("Messages can have at most %d different reactions", maxEmojisPerMessage)
+			return nil, &ChatMessageError{reason}
+		}
+	} else {
+		if err := queries.RemoveReaction(c, db.RemoveReactionParams(params)); err != nil {
+			return nil, err
+		}
+	}
+
+	reactions, err := queries.ListReactions(c, message.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := summarizeReactions(len(reactions), func(i int) (string, string) {
+		return reactions[i].Emoji, reactions[i].UserID
+	})
+	return summaries, tx.Commit()
+}
+
+// Returns the reaction summaries of the room messages with an id of at least
+// sinceMessageId, keyed by message id
+func (s *Service) GetRoomReactions(c context.Context, roomId uint64, sinceMessageId uint64) (map[uint64][]*packets.ReactionSummary, error) {
+	reactions, err := s.repo.queries.ListRoomReactions(c, db.ListRoomReactionsParams{
+		RoomID:    int64(roomId),
+		MessageID: int64(sinceMessageId),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groupReactions(reactions), nil
+}
+
+// Summarizes the reactions of each message, keyed by message id. Reactions must
+// be ordered by message id
+func groupReactions(reactions []db.ListRoomReactionsRow) map[uint64][]*packets.ReactionSummary {
+	summaries := make(map[uint64][]*packets.ReactionSummary)
+	for start := 0; start < len(reactions); {
+		end := start
+		for end < len(reactions) && reactions[end].MessageID == reactions[start].MessageID {
+			end++
+		}
+
+		messageReactions := reactions[start:end]
+		summaries[uint64(reactions[start].MessageID)] = summarizeReactions(len(messageReactions), func(i int) (string, string) {
+			return messageReactions[i].Emoji, messageReactions[i].UserID
+		})
+		start = end
+	}
+	return summaries
+}
+
+// Groups reactions by emoji, keeping the order in which each emoji was first used
+func summarizeReactions(count int, reaction func(i int) (emoji string, userId string)) []*packets.ReactionSummary {
+	summaries := []*packets.ReactionSummary{}
+	byEmoji := make(map[string]*packets.ReactionSummary)
+	for i := range count {
+		emoji, userId := reaction(i)
+		summary, found := byEmoji[emoji]
+		if !found {
+			summary = &packets.ReactionSummary{Emoji: emoji}
+			byEmoji[emoji] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.Count++
+		summary.UserIds = append(summary.UserIds, userId)
+	}
+	return summaries
+}
+
+func validateEmoji(emoji string) error {
+	if emoji == "" || utf8.RuneCountInString(emoji) > maxEmojiChars {
+		return &ChatMessageError{"Invalid reaction"}
+	}
+
+	hasSymbol := false
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return &ChatMessageError{"Invalid reaction"}
+		}
+		if unicode.Is(unicode.So, r) {
+			hasSymbol = true
+		}
+	}
+	if !hasSymbol {
+		return &ChatMessageError{"Invalid reaction"}
+	}
+
+	return nil
+}
+
+// Saves the reaction change of this client and sends the new reactions of the
+// message to everyone in the room
+func (c *WebSocketClient) setReaction(ctx context.Context, roomId uint64, messageId uint64, emoji string, add bool) {
+	reactions, err := c.service.SetReaction(ctx, roomId, c.userId, messageId, emoji, add)
+	if err != nil {
+		c.denyChatChange(err, "Unable to change reaction")
+		return
+	}
+
+	message := packets.NewReactions(messageId, reactions)
+	c.broadcastEvent(ctx, message, roomId)
+}
@@ -0,0 +1,74 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"server/internal/client"
+	"server/internal/db"
+	"server/pkg/packets"
+)
+
+var (
+	readReceipts = flag.Bool("read-receipts", true, "Show members of a room how far the others have read")
+)
+
+// Moves the user's read position in the room forward to the message, which also
+// marks the mentions before it as seen. Returns whether it moved, marking an
+// older message as read changes nothing
+func (s *Service) MarkRead(c context.Context, roomId uint64, userId string, messageId uint64) (bool, error) {
+	messageRoomId, err := s.repo.queries.GetMessageRoomId(c, int64(messageId))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && uint64(messageRoomId) != roomId) {
+		return false, &ChatMessageError{"Message not found"}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := s.repo.queries.MarkRoomRead(c, db.MarkRoomReadParams{
+		UserID:     userId,
+		RoomID:     int64(roomId),
+		LastReadID: int64(messageId),
+	})
+	if err != nil || updated == 0 {
+		return false, err
+	}
+
+	// Mentions up to the read position have been seen
+	err = s.repo.queries.MarkMentionsSeen(c, db.MarkMentionsSeenParams{
+		UserID:     userId,
+		RoomID:     int64(roomId),
+		LastReadID: int64(messageId),
+	})
+	return true, err
+}
+
+func (c *WebSocketClient) markRead(ctx context.Context, roomId uint64, messageId uint64) {
+	moved, err := c.service.MarkRead(ctx, roomId, c.userId, messageId)
+	if err != nil {
+		c.denyChatChange(err, "Unable to mark message as read")
+		return
+	}
+	if !moved {
+		return
+	}
+
+	c.sendReadPosition(roomId, messageId)
+}
+
+// Tells the room how far the user has read. With read receipts turned off only
+// the user's own connections are told, so their unread counts stay in sync
+func (c *WebSocketClient) sendReadPosition(roomId uint64, messageId uint64) {
+	message := packets.NewReadPosition(c.userId, messageId)
+	if *readReceipts {
+		c.broadcast(message, roomId)
+		return
+	}
+
+	c.hub.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if clientId != c.id && client.UserId() == c.userId && client.IsInRoom(roomId) {
+			client.SocketSendAs(message, c.id, roomId)
+		}
+	})
+}
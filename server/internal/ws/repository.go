@@ -18,6 +18,21 @@ func NewRepository(dbPool *sql.DB) Repository {
 	}
 }
 
-func (r *Repository) GetUserByUsername(ctx context.Context, username string) (db.User, error) {
-	return r.queries.GetUserByUsername(ctx, username)
+func (r *Repository) GetUserByUsername(ctx context.Context, usernameKey string) (db.User, error) {
+	return r.queries.GetUserByUsername(ctx, usernameKey)
+}
+
+// Deletes the room with everything it holds, in a single transaction
+func (r *Repository) PurgeRoom(ctx context.Context, id int64) (int64, error) {
+	tx, err := r.dbPool.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	deleted, err := r.queries.WithTx(tx).PurgeRoom(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, tx.Commit()
 }
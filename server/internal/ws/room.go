@@ -1,28 +1,74 @@
 package ws
 
 import (
+	"flag"
 	"server/internal/client"
 	"server/internal/objects"
 	"server/pkg/packets"
 	"sort"
+	"sync"
 	"time"
 )
 
+var (
+	messageRetention = flag.Duration("message-retention", 5*time.Minute, "How long chat messages are kept to be sent to new clients")
+)
+
 type StoragedMessage struct {
 	Timestamp      time.Time
 	Msg            *packets.Packet_Chat
 	SenderId       uint64
+	SenderUserId   string
 	SenderUsername string
 }
 
+// What a user can do in a room. Higher roles can do everything lower ones can
+type RoomRole int
+
+const (
+	RoleMember RoomRole = iota
+	RoleModerator
+	RoleOwner
+)
+
+func (r RoomRole) String() string {
+	switch r {
+	case RoleModerator:
+		return "moderator"
+	case RoleOwner:
+		return "owner"
+	}
+	return "member"
+}
+
 type Room struct {
 	Id      uint64
 	OwnerId string
 	Name    string
 	Clients *objects.SharedCollection[client.ClientInterfacer]
 
-	// Last messages sent from clients, so it can be sent to new clients
+	// Shown to members, the topic on a single line and the description at length
+	Topic       string
+	Description string
+
+	// Who can find and join the room
+	Visibility packets.RoomVisibility
+
+	// Members who moderate the room besides its owner. Never changed in place,
+	// see withRole
+	Moderators map[string]bool
+
+	// How long members other than moderators wait between two messages, zero when
+	// slow mode is off
+	SlowMode time.Duration
+
+	// Last messages sent from clients, so it can be sent to new clients. Keyed by
+	// the message id
 	LastMessages *objects.SharedCollection[StoragedMessage]
+
+	// Held while an event of the room is logged and handed to the hub, so the hub
+	// gets the room's events in the order of their sequence numbers
+	events *sync.Mutex
 }
 
 func NewRoom(id uint64, ownerId string, name string) *Room {
@@ -32,7 +78,41 @@ func NewRoom(id uint64, ownerId string, name string) *Room {
 		Name:         name,
 		Clients:      objects.NewSharedCollection[client.ClientInterfacer](),
 		LastMessages: objects.NewSharedCollection[StoragedMessage](),
+		events:       &sync.Mutex{},
+	}
+}
+
+// Moderators can edit and delete messages of other members. The owner is
+// always a moderator of their room
+func (r *Room) IsModerator(userId string) bool {
+	return r.Role(userId) >= RoleModerator
+}
+
+func (r *Room) Role(userId string) RoomRole {
+	if userId == r.OwnerId {
+		return RoleOwner
 	}
+	if r.Moderators[userId] {
+		return RoleModerator
+	}
+	return RoleMember
+}
+
+// Returns a copy of the room with the role of the member changed. Moderators
+// are copied so rooms handed out before keep reading the old ones safely
+func (r Room) withRole(userId string, role RoomRole) Room {
+	moderators := make(map[string]bool, len(r.Moderators)+1)
+	for id := range r.Moderators {
+		moderators[id] = true
+	}
+	if role >= RoleModerator {
+		moderators[userId] = true
+	} else {
+		delete(moderators, userId)
+	}
+
+	r.Moderators = moderators
+	return r
 }
 
 func (r *Room) OrderLastMessages(lastMessages *objects.SharedCollection[StoragedMessage]) []StoragedMessage {
@@ -48,10 +128,14 @@ func (r *Room) OrderLastMessages(lastMessages *objects.SharedCollection[Storaged
 	return messages
 }
 
-func (r *Room) RemoveOldMessages(lastMessages *objects.SharedCollection[StoragedMessage]) {
+// Removes messages older than the retention period and returns how many were removed
+func (r *Room) RemoveOldMessages(lastMessages *objects.SharedCollection[StoragedMessage]) int {
+	removed := 0
 	lastMessages.ForEach(func(id uint64, sm StoragedMessage) {
-		if time.Since(sm.Timestamp) >= 5*time.Minute {
+		if time.Since(sm.Timestamp) >= *messageRetention {
 			lastMessages.Remove(id)
+			removed++
 		}
 	})
+	return removed
 }
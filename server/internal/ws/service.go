@@ -2,18 +2,172 @@ package ws
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"server/internal/client"
+	"server/internal/db"
+	"server/internal/unfurl"
+	"server/internal/usernames"
+	"server/pkg/packets"
+	"time"
 )
 
 type Service struct {
-	repo Repository
+	repo  Repository
+	links unfurl.Service
 }
 
-func NewService(repository Repository) Service {
+func NewService(repository Repository, links unfurl.Service) Service {
 	return Service{
-		repo: repository,
+		repo:  repository,
+		links: links,
 	}
 }
 
 func (s *Service) GetUsernameById(c context.Context, id string) (string, error) {
 	return s.repo.queries.GetUsernameById(c, id)
 }
+
+func (s *Service) GetUserById(c context.Context, id string) (db.User, error) {
+	return s.repo.queries.GetUserById(c, id)
+}
+
+// Adds the rooms saved on the database to the hub
+func (s *Service) LoadRooms(c context.Context, hub *Hub) error {
+	rooms, err := s.repo.queries.ListRooms(c)
+	if err != nil {
+		return err
+	}
+
+	for _, room := range rooms {
+		id := uint64(room.ID)
+		loaded := NewRoom(id, room.OwnerID, room.Name)
+		loaded.SlowMode = time.Duration(room.SlowModeSeconds) * time.Second
+		loaded.Topic = room.Topic
+		loaded.Description = room.Description
+		loaded.Visibility = packets.RoomVisibility(room.Visibility)
+		hub.Rooms.Add(*loaded, id)
+	}
+
+	moderators, err := s.repo.queries.ListModeratorMemberships(c)
+	if err != nil {
+		return err
+	}
+	for _, moderator := range moderators {
+		hub.Rooms.Update(uint64(moderator.RoomID), func(room *Room) {
+			*room = room.withRole(moderator.UserID, RoleModerator)
+		})
+	}
+
+	log.Printf("Loaded %d rooms", len(rooms))
+	return nil
+}
+
+func (s *Service) GetProfile(c context.Context, userId string) (*packets.ProfileMessage, error) {
+	profile, err := s.repo.queries.GetProfile(c, userId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return packets.NewProfile(userId, "", "", "", false, 0), nil
+		}
+		return nil, err
+	}
+
+	return packets.NewProfile(profile.UserID, profile.DisplayName, profile.Bio, profile.Status, profile.AvatarMime != "", profile.Version), nil
+}
+
+// Changes the display name of the user and returns their updated profile
+func (s *Service) SetDisplayName(c context.Context, userId string, displayName string) (*packets.ProfileMessage, error) {
+	displayName, err := usernames.CleanDisplayName(displayName)
+	if err != nil {
+		reason := fmt.Sprintf("Invalid display name: %v", err)
+		return nil, &ChatMessageError{reason}
+	}
+
+	err = s.repo.queries.SetProfileDisplayName(c, db.SetProfileDisplayNameParams{
+		UserID:      userId,
+		DisplayName: displayName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProfile(c, userId)
+}
+
+// Closes the connections of disabled users. Users are disabled through the
+// admin command, so the server finds them by checking the database often.
+// Returns how many connections were closed
+func (s *Service) DisconnectDisabledUsers(c context.Context, hub *Hub) (int64, error) {
+	userIds, err := s.repo.queries.ListDisabledUserIds(c)
+	if err != nil || len(userIds) == 0 {
+		return 0, err
+	}
+
+	disabled := make(map[string]bool, len(userIds))
+	for _, userId := range userIds {
+		disabled[userId] = true
+	}
+
+	closed := int64(0)
+	hub.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+		if disabled[client.UserId()] {
+			client.Close("Account disabled")
+			closed++
+		}
+	})
+	return closed, nil
+}
+
+// Drops state the hub still holds but the database no longer backs: rooms deleted
+// through the admin command and connections of deleted users. Disabled users are
+// left to DisconnectDisabledUsers. Returns how many rooms and connections were removed
+func (s *Service) RemoveStaleState(c context.Context, hub *Hub) (int64, error) {
+	// Rooms are saved before the hub gets them, so looking at the hub first means
+	// a room created meanwhile can't be taken for one that was deleted
+	hubRooms := []Room{}
+	hub.Rooms.ForEach(func(roomId uint64, room Room) {
+		hubRooms = append(hubRooms, room)
+	})
+
+	rooms, err := s.repo.queries.ListRooms(c)
+	if err != nil {
+		return 0, err
+	}
+
+	saved := make(map[uint64]bool, len(rooms))
+	for _, room := range rooms {
+		saved[uint64(room.ID)] = true
+	}
+
+	// Whether each user still exists, so every user is only queried once
+	exists := make(map[string]bool)
+	userExists := func(userId string) bool {
+		if ok, checked := exists[userId]; checked {
+			return ok
+		}
+
+		_, err := s.repo.queries.GetUserById(c, userId)
+		ok := !errors.Is(err, sql.ErrNoRows)
+		exists[userId] = ok
+		return ok
+	}
+
+	removed := int64(0)
+	for _, room := range hubRooms {
+		if !saved[room.Id] {
+			hub.RemoveRoom(room.Id)
+			removed++
+			continue
+		}
+
+		room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
+			if !userExists(client.UserId()) {
+				client.Close("Account deleted")
+				removed++
+			}
+		})
+	}
+
+	return removed, nil
+}
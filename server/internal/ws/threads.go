@@ -0,0 +1,140 @@
+package ws
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"server/internal/db"
+	"server/pkg/packets"
+)
+
+var (
+	defaultThreadLimit = 50
+	maxThreadLimit     = 200
+)
+
+// Returns the top level message of a thread and its replies after afterId,
+// oldest first
+func (s *Service) GetThread(c context.Context, roomId uint64, parentId uint64, afterId uint64, limit uint32) (*packets.ChatMessage, []*packets.ChatMessage, error) {
+	parent, err := s.repo.queries.GetMessage(c, int64(parentId))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && (uint64(parent.RoomID) != roomId || parent.ParentID.Valid)) {
+		return nil, nil, &ChatMessageError{"Thread not found"}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if limit == 0 {
+		limit = uint32(defaultThreadLimit)
+	}
+	limit = min(limit, uint32(maxThreadLimit))
+
+	replies, err := s.repo.queries.ListThreadReplies(c, db.ListThreadRepliesParams{
+		ParentID: sql.NullInt64{Int64: parent.ID, Valid: true},
+		ID:       int64(afterId),
+		Limit:    int64(limit),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replyCount, err := s.repo.queries.CountThreadReplies(c, sql.NullInt64{Int64: parent.ID, Valid: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	threadReactions, err := s.repo.queries.ListThreadReactions(c, parent.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows := make([]db.ListRoomReactionsRow, 0, len(threadReactions))
+	for _, reaction := range threadReactions {
+		rows = append(rows, db.ListRoomReactionsRow(reaction))
+	}
+	reactions := groupReactions(rows)
+
+	threadAttachments, err := s.repo.queries.ListThreadAttachments(c, parent.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachments := make(map[int64][]db.Attachment)
+	for _, attachment := range threadAttachments {
+		attachments[attachment.MessageID.Int64] = append(attachments[attachment.MessageID.Int64], attachment)
+	}
+	thumbnails, err := s.repo.queries.ListThreadThumbnails(c, parent.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	threadPreviews, err := s.repo.queries.ListThreadLinkPreviews(c, parent.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	previews := make(map[int64][]db.MessageLinkPreview)
+	for _, preview := range threadPreviews {
+		previews[preview.MessageID] = append(previews[preview.MessageID], preview)
+	}
+
+	parentChat := chatFromMessage(parent)
+	parentChat.ReplyCount = uint32(replyCount)
+	parentChat.Reactions = reactions[uint64(parent.ID)]
+	parentChat.Attachments = attachmentMessages(attachments[parent.ID], thumbnails)
+	parentChat.Previews = linkPreviewMessages(previews[parent.ID])
+
+	replyChats := make([]*packets.ChatMessage, 0, len(replies))
+	for _, reply := range replies {
+		chat := chatFromMessage(reply)
+		chat.Reactions = reactions[uint64(reply.ID)]
+		chat.Attachments = attachmentMessages(attachments[reply.ID], thumbnails)
+		chat.Previews = linkPreviewMessages(previews[reply.ID])
+		replyChats = append(replyChats, chat)
+	}
+
+	return parentChat, replyChats, nil
+}
+
+// Returns how many replies each thread started at or after sinceMessageId has,
+// keyed by the id of the message that started it
+func (s *Service) GetRoomReplyCounts(c context.Context, roomId uint64, sinceMessageId uint64) (map[uint64]uint32, error) {
+	counts, err := s.repo.queries.ListRoomReplyCounts(c, db.ListRoomReplyCountsParams{
+		RoomID:   int64(roomId),
+		ParentID: sql.NullInt64{Int64: int64(sinceMessageId), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	replyCounts := make(map[uint64]uint32, len(counts))
+	for _, count := range counts {
+		replyCounts[uint64(count.ParentID.Int64)] = uint32(count.ReplyCount)
+	}
+	return replyCounts, nil
+}
+
+func (s *Service) CountThreadReplies(c context.Context, parentId uint64) (uint32, error) {
+	count, err := s.repo.queries.CountThreadReplies(c, sql.NullInt64{Int64: int64(parentId), Valid: true})
+	return uint32(count), err
+}
+
+func (c *WebSocketClient) sendThread(ctx context.Context, roomId uint64, request *packets.ThreadRequestMessage) {
+	parent, replies, err := c.service.GetThread(ctx, roomId, request.ParentId, request.AfterId, request.Limit)
+	if err != nil {
+		c.denyChatChange(err, "Unable to get thread")
+		return
+	}
+
+	c.sendAsWaiting(packets.NewThread(parent, replies), c.id, roomId)
+}
+
+// Tells everyone in the room how many replies the thread has now, so clients can
+// show it collapsed under its first message
+func (c *WebSocketClient) sendThreadUpdated(ctx context.Context, roomId uint64, parentId uint64) {
+	replyCount, err := c.service.CountThreadReplies(ctx, parentId)
+	if err != nil {
+		c.logger.Printf("error counting replies of message %d: %v", parentId, err)
+		return
+	}
+
+	message := packets.NewThreadUpdated(parentId, replyCount)
+	c.broadcastEvent(ctx, message, roomId)
+}
@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"server/pkg/packets"
+	"time"
+)
+
+var (
+	// Typing packets are forwarded at most once per interval for each room
+	typingInterval = 2 * time.Second
+	// Clients that stop sending typing packets stop being shown as typing
+	typingTimeout = 5 * time.Second
+)
+
+type typingState struct {
+	lastSent time.Time
+	timer    *time.Timer
+}
+
+// Forwards that the client is typing in the room. Typing state is never
+// persisted, it expires on its own unless the client keeps refreshing it
+func (c *WebSocketClient) setTyping(roomId uint64, typing bool) {
+	if !typing {
+		c.stopTyping(roomId)
+		return
+	}
+
+	c.typingMutex.Lock()
+	state, found := c.typing[roomId]
+	if found {
+		state.timer.Reset(typingTimeout)
+		if time.Since(state.lastSent) < typingInterval {
+			c.typingMutex.Unlock()
+			return
+		}
+		state.lastSent = time.Now()
+	} else {
+		state = &typingState{lastSent: time.Now()}
+		state.timer = time.AfterFunc(typingTimeout, func() {
+			c.expireTyping(roomId, state)
+		})
+		c.typing[roomId] = state
+	}
+	c.typingMutex.Unlock()
+
+	c.broadcast(packets.NewTyping(c.userId, c.username, true), roomId)
+}
+
+func (c *WebSocketClient) stopTyping(roomId uint64) {
+	c.typingMutex.Lock()
+	state, found := c.typing[roomId]
+	if found {
+		state.timer.Stop()
+		delete(c.typing, roomId)
+	}
+	c.typingMutex.Unlock()
+
+	if found {
+		// Also called by the hub, when the client leaves the room or is closed
+		c.Broadcast(packets.NewTyping(c.userId, c.username, false), roomId)
+	}
+}
+
+func (c *WebSocketClient) stopAllTyping() {
+	c.typingMutex.Lock()
+	roomIds := make([]uint64, 0, len(c.typing))
+	for roomId := range c.typing {
+		roomIds = append(roomIds, roomId)
+	}
+	c.typingMutex.Unlock()
+
+	for _, roomId := range roomIds {
+		c.stopTyping(roomId)
+	}
+}
+
+// Called when the typing timeout runs out. The state may have been replaced
+// since the timer was set, in which case it's left alone
+func (c *WebSocketClient) expireTyping(roomId uint64, state *typingState) {
+	c.typingMutex.Lock()
+	expired := c.typing[roomId] == state
+	if expired {
+		delete(c.typing, roomId)
+	}
+	c.typingMutex.Unlock()
+
+	if expired {
+		c.broadcast(packets.NewTyping(c.userId, c.username, false), roomId)
+	}
+}
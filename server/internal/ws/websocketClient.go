@@ -1,14 +1,20 @@
 package ws
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"server/internal/client"
 	"server/internal/jwt"
+	"server/internal/objects"
 	"server/pkg/packets"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -18,22 +24,59 @@ import (
 var (
 	pongWait     = 10 * time.Second
 	pingInterval = (pongWait * 9) / 10
+
+	// Packets above this close the connection, message limits are enforced below it
+	// with a DenyResponse
+	maxPacketBytes = flag.Int("max-packet-bytes", 64*1024, "Largest packet a client can send over the websocket, in bytes")
+
+	closeMessageTimeout = time.Second
 )
 
 type WebSocketClient struct {
 	id       uint64
 	userId   string
 	username string
-	roomId   uint64
+	profile  atomic.Pointer[packets.ProfileMessage]
+	rooms    *objects.SharedCollection[bool] // Rooms the client is a member of, keyed by room id
 	conn     *websocket.Conn
 	hub      *Hub
+	service  Service
 	sendChan chan *packets.Packet // To send messages from server to client. WritePump consumes it
+	done     chan struct{}        // Closed when the client is closed, stops WritePump
 	logger   *log.Logger
+
+	// Closed once the hub gave the client its id, ReadPump waits for it
+	registered chan struct{}
+
+	// Room given on the connection URL, joined as soon as the client starts reading
+	initialRoomId uint64
+	// Last event of the initial room the client saw, to resume from it
+	initialLastSeq uint64
+	closeOnce      sync.Once
+	// Close frame WritePump sends when the client is closed, a normal closure when nil
+	closeMessage atomic.Pointer[[]byte]
+
+	// What happens to packets when sendChan is full, and what got lost because of it
+	policy  backpressurePolicy
+	dropped atomic.Uint64
+	missed  missedPackets
+
+	// Rate limit of chat packets from this connection, only used by ReadPump
+	chatLimit *tokenBucket
+
+	// When the client last sent a packet, in Unix nanoseconds
+	lastActive atomic.Int64
+
+	// Rooms the client is typing in, keyed by room id
+	typing      map[uint64]*typingState
+	typingMutex sync.Mutex
 }
 
 func NewWebSocketClient(hub *Hub, service Service, writer http.ResponseWriter, request *http.Request) (client.ClientInterfacer, error) {
 	token := request.URL.Query().Get("token")
 	roomStr := request.URL.Query().Get("room")
+	seqStr := request.URL.Query().Get("seq")
+	policyStr := request.URL.Query().Get("policy")
 	accessToken, err := jwt.IsValidAccessToken(token, &jwt.AccessToken{})
 	if err != nil {
 		log.Printf("error getting access token: %v", err)
@@ -41,19 +84,70 @@ func NewWebSocketClient(hub *Hub, service Service, writer http.ResponseWriter, r
 		return nil, err
 	}
 
-	roomId, err := strconv.ParseUint(roomStr, 10, 64)
-	if err != nil {
-		log.Printf("error converting roomId %v to uint64", roomId)
-		return nil, err
+	// The room is optional, more rooms can be joined later with JoinRoom packets
+	var roomId uint64
+	if roomStr != "" {
+		roomId, err = strconv.ParseUint(roomStr, 10, 64)
+		if err != nil {
+			log.Printf("error converting roomId %v to uint64", roomStr)
+			return nil, err
+		}
+
+		_, found := hub.Rooms.Get(roomId)
+		if !found {
+			reason := fmt.Sprintf("unable to find room id %v", roomId)
+			log.Println(reason)
+			return nil, errors.New(reason)
+		}
 	}
 
-	_, found := hub.Rooms.Get(roomId)
-	if !found {
-		reason := fmt.Sprintf("unable to find room id %v", roomId)
+	// Clients reconnecting give the last room event they saw so they only get what they missed
+	var lastSeq uint64
+	if seqStr != "" {
+		lastSeq, err = strconv.ParseUint(seqStr, 10, 64)
+		if err != nil {
+			log.Printf("error converting seq %v to uint64", seqStr)
+			return nil, err
+		}
+	}
+
+	policy, ok := connectionBackpressurePolicy(policyStr)
+	if !ok {
+		reason := fmt.Sprintf("slow client policy %v can't be picked", policyStr)
+		log.Println(reason)
+		writer.WriteHeader(http.StatusBadRequest)
+		return nil, errors.New(reason)
+	}
+
+	user, userErr := service.GetUserById(request.Context(), accessToken.Subject)
+	if errors.Is(userErr, sql.ErrNoRows) {
+		// The account was deleted while the access token is still valid
+		log.Printf("user %v not found", accessToken.Subject)
+		writer.WriteHeader(http.StatusUnauthorized)
+		return nil, userErr
+	}
+	if user.DisabledAt.Valid {
+		reason := fmt.Sprintf("user %v is disabled", accessToken.Subject)
 		log.Println(reason)
+		writer.WriteHeader(http.StatusForbidden)
 		return nil, errors.New(reason)
 	}
 
+	if room, found := hub.Rooms.Get(roomId); found {
+		allowed, err := service.CanJoinRoom(request.Context(), room, user.ID)
+		if err != nil {
+			log.Printf("error checking access to room %v: %v", roomId, err)
+			writer.WriteHeader(http.StatusInternalServerError)
+			return nil, err
+		}
+		if !allowed {
+			reason := fmt.Sprintf("user %v is not a member of room %v", user.ID, roomId)
+			log.Println(reason)
+			writer.WriteHeader(http.StatusForbidden)
+			return nil, errors.New(reason)
+		}
+	}
+
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
@@ -66,20 +160,35 @@ func NewWebSocketClient(hub *Hub, service Service, writer http.ResponseWriter, r
 	}
 
 	c := &WebSocketClient{
-		userId:   accessToken.Subject,
-		roomId:   roomId,
-		hub:      hub,
-		conn:     conn,
-		sendChan: make(chan *packets.Packet, 256),
-		logger:   log.New(log.Writer(), "Client unknown: ", log.LstdFlags),
+		userId:         accessToken.Subject,
+		rooms:          objects.NewSharedCollection[bool](),
+		hub:            hub,
+		service:        service,
+		conn:           conn,
+		sendChan:       make(chan *packets.Packet, *sendBufferSize),
+		policy:         policy,
+		chatLimit:      newTokenBucket(*connectionChatRate, *connectionChatBurst, time.Now()),
+		done:           make(chan struct{}),
+		registered:     make(chan struct{}),
+		logger:         log.New(log.Writer(), "Client unknown: ", log.LstdFlags),
+		initialRoomId:  roomId,
+		initialLastSeq: lastSeq,
+		typing:         make(map[uint64]*typingState),
+	}
+
+	c.username = user.Username
+	if userErr != nil {
+		c.username = fmt.Sprintf("Client %v", c.id)
+		c.logger.Printf("Error getting username: %v", userErr)
 	}
 
-	username, err := service.GetUsernameById(request.Context(), c.userId)
+	profile, err := service.GetProfile(request.Context(), c.userId)
 	if err != nil {
-		username = fmt.Sprintf("Client %v", c.id)
-		c.logger.Printf("Error getting username: %v", err)
+		profile = packets.NewProfile(c.userId, "", "", "", false, 0)
+		c.logger.Printf("Error getting profile: %v", err)
 	}
-	c.username = username
+	c.profile.Store(profile)
+	c.lastActive.Store(time.Now().UnixNano())
 
 	return c, nil
 }
@@ -88,35 +197,72 @@ func (c *WebSocketClient) Initialize(id uint64) {
 	c.id = id
 	c.logger.SetPrefix(fmt.Sprintf("Client %d: ", c.id))
 
-	// Check if has another client with the same userID connected. If yes, drop it
-	room, _ := c.hub.Rooms.Get(c.roomId)
-	room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
-		if clientId == c.Id() {
-			return
-		}
+	c.SocketSend(packets.NewId(c.Id(), c.Username(), nil))
+	close(c.registered)
+}
 
-		if c.userId == client.UserId() {
-			c.logger.Printf("Clients %v and %v with the same userId. Disconnecting client %v", c.id, clientId, clientId)
-			client.Close("Another connection was found")
-		}
-	})
+func (c *WebSocketClient) JoinedRoom(roomId uint64) {
+	c.rooms.Add(true, roomId)
+}
+
+// Joins the room through the hub, then sends the client the room's state. No
+// event of the room is logged meanwhile, so the state is followed by exactly
+// the events that come after it. The hub only adds the client to the room, the
+// database is read here so a slow query doesn't hold up every room
+func (c *WebSocketClient) joinRoom(ctx context.Context, roomId uint64, lastSeq uint64) {
+	room, found := c.hub.Rooms.Get(roomId)
+	if !found {
+		c.SocketSend(packets.NewDenyResponsePkt("Room not found"))
+		return
+	}
+	room.events.Lock()
+	defer room.events.Unlock()
+
+	joined := make(chan bool, 1)
+	c.hub.JoinRoomChan <- Membership{Client: c, RoomId: roomId, Joined: joined}
+	if !<-joined {
+		return
+	}
 
-	c.SocketSend(packets.NewId(c.Id(), c.Username(), room.Id, room.OwnerId, room.Name))
-	c.Broadcast(packets.NewRegister(c.id, c.username), c.roomId)
+	// The hub copy, with whoever joined before
+	if room, found = c.hub.Rooms.Get(roomId); !found {
+		return
+	}
 
-	c.logger.Printf("Fowarding already connected users to client")
+	roomInfo := packets.NewRoomRegistered(room.Id, room.OwnerId, room.Name)
+	lastRoomSeq, err := c.service.GetRoomLastSeq(ctx, roomId)
+	if err != nil {
+		c.logger.Printf("Error getting last event of room %d: %v", roomId, err)
+	}
+	roomInfo.LastSeq = lastRoomSeq
+	roomInfo.SlowModeSeconds = uint32(room.SlowMode.Seconds())
+	roomInfo.Topic = room.Topic
+	roomInfo.Description = room.Description
+	roomInfo.Visibility = room.Visibility
+	roomInfo.Role = packets.RoomRole(room.Role(c.userId))
+	c.sendAsWaiting(packets.NewId(c.Id(), c.Username(), roomInfo), c.id, roomId)
+	c.broadcast(packets.NewRegister(c.id, c.username, c.Profile()), roomId)
+
+	c.logger.Printf("Fowarding users already in room %d to client", roomId)
 	room.Clients.ForEach(func(clientId uint64, client client.ClientInterfacer) {
 		if clientId != c.Id() {
 			// Already connected client (client) is forwarding their register to the newer client (c)
-			client.PassToPeer(packets.NewRegister(clientId, client.Username()), c.Id())
+			client.PassToPeer(packets.NewRegister(clientId, client.Username(), client.Profile()), c.Id(), roomId)
 		}
 	})
 
-	for _, sm := range room.OrderLastMessages(room.LastMessages) {
-		c.SocketSendAs(sm.Msg, sm.SenderId, c.roomId)
+	c.sendRoomPresence(ctx, room)
+	if lastSeq == 0 || !c.resumeRoom(ctx, roomId, lastSeq) {
+		c.replayMessages(ctx, room)
 	}
 }
 
+func (c *WebSocketClient) LeftRoom(roomId uint64) {
+	c.stopTyping(roomId)
+	c.rooms.Remove(roomId)
+	c.SocketSendAs(packets.NewLeaveRoom(roomId), c.id, roomId)
+}
+
 func (c *WebSocketClient) Id() uint64 {
 	return c.id
 }
@@ -129,8 +275,29 @@ func (c *WebSocketClient) Username() string {
 	return c.username
 }
 
-func (c *WebSocketClient) RoomId() uint64 {
-	return c.roomId
+func (c *WebSocketClient) RoomIds() []uint64 {
+	roomIds := make([]uint64, 0, c.rooms.Len())
+	c.rooms.ForEach(func(roomId uint64, _ bool) {
+		roomIds = append(roomIds, roomId)
+	})
+	return roomIds
+}
+
+func (c *WebSocketClient) IsInRoom(roomId uint64) bool {
+	_, found := c.rooms.Get(roomId)
+	return found
+}
+
+func (c *WebSocketClient) LastActive() time.Time {
+	return time.Unix(0, c.lastActive.Load())
+}
+
+func (c *WebSocketClient) Profile() *packets.ProfileMessage {
+	return c.profile.Load()
+}
+
+func (c *WebSocketClient) SetProfile(profile *packets.ProfileMessage) {
+	c.profile.Store(profile)
 }
 
 func (c *WebSocketClient) ProcessMessage(senderId uint64, roomId uint64, message packets.Pkt) {
@@ -145,31 +312,28 @@ func (c *WebSocketClient) ProcessMessage(senderId uint64, roomId uint64, message
 }
 
 func (c *WebSocketClient) SocketSend(message packets.Pkt) {
-	c.SocketSendAs(message, c.id, c.roomId)
+	c.SocketSendAs(message, c.id, 0)
 }
 
 func (c *WebSocketClient) SocketSendAs(message packets.Pkt, senderId uint64, roomId uint64) {
-	select {
-	case c.sendChan <- &packets.Packet{SenderId: senderId, RoomId: roomId, Msg: message}:
-	default:
-		c.logger.Printf("Send channel full, dropping message: %T", message)
-	}
+	c.SocketSendPacket(&packets.Packet{SenderId: senderId, RoomId: roomId, Msg: message})
 }
 
-func (c *WebSocketClient) PassToPeer(message packets.Pkt, peerId uint64) {
-	room, _ := c.hub.Rooms.Get(c.roomId)
-	if peer, exists := room.Clients.Get(peerId); exists {
-		peer.ProcessMessage(c.id, c.roomId, message)
+func (c *WebSocketClient) PassToPeer(message packets.Pkt, peerId uint64, roomId uint64) {
+	if peer, exists := c.hub.Clients.Get(peerId); exists {
+		peer.ProcessMessage(c.id, roomId, message)
 	}
 }
 
+// Never waits for the hub, which calls it too, e.g. when the client leaves a room
 func (c *WebSocketClient) Broadcast(message packets.Pkt, roomId uint64) {
-	select {
-	case c.hub.BroadcastChan <- &packets.Packet{SenderId: c.id, RoomId: roomId, Msg: message}:
-	default:
-		c.logger.Printf("Broadcast channel full, dropping message: %t", message)
-	}
+	c.tryBroadcastPacket(&packets.Packet{SenderId: c.id, RoomId: roomId, Msg: message})
+}
 
+// Like Broadcast, but waits a little when the hub is behind. Only called from
+// the client's own goroutines, like ReadPump
+func (c *WebSocketClient) broadcast(message packets.Pkt, roomId uint64) {
+	c.broadcastPacket(&packets.Packet{SenderId: c.id, RoomId: roomId, Msg: message})
 }
 
 // Listen messages from client
@@ -183,10 +347,16 @@ func (c *WebSocketClient) ReadPump() {
 		return
 	}
 
-	c.conn.SetReadLimit(512)
+	c.conn.SetReadLimit(int64(*maxPacketBytes))
 
 	c.conn.SetPongHandler(c.pongHandler)
 
+	<-c.registered
+	c.service.PresenceConnected(context.Background(), c.hub, c)
+	if c.initialRoomId != 0 {
+		c.joinRoom(context.Background(), c.initialRoomId, c.initialLastSeq)
+	}
+
 	for {
 		_, data, err := c.conn.ReadMessage()
 		if err != nil {
@@ -204,22 +374,71 @@ func (c *WebSocketClient) ReadPump() {
 		}
 
 		packet.SenderId = c.id
-		packet.RoomId = c.roomId
+		c.markActive(context.Background())
 
-		if msg, ok := packet.Msg.(*packets.Packet_Chat); ok {
-			room, found := c.hub.Rooms.Get(c.roomId)
-			if !found {
-				return
+		switch msg := packet.Msg.(type) {
+		case *packets.Packet_JoinRoom:
+			if c.IsInRoom(msg.JoinRoom.RoomId) {
+				// Joining again is how clients resync after missing packets
+				c.resyncRoom(context.Background(), msg.JoinRoom.RoomId, msg.JoinRoom.LastSeq)
+				continue
 			}
-			room.LastMessages.Add(StoragedMessage{
-				Timestamp:      time.Now(),
-				Msg:            msg,
-				SenderUsername: c.username,
-				SenderId:       packet.SenderId,
-			})
+			if !c.canJoin(context.Background(), msg.JoinRoom.RoomId) {
+				continue
+			}
+			c.joinRoom(context.Background(), msg.JoinRoom.RoomId, msg.JoinRoom.LastSeq)
+			continue
+		case *packets.Packet_LeaveRoom:
+			c.hub.LeaveRoomChan <- Membership{Client: c, RoomId: msg.LeaveRoom.RoomId}
+			continue
+		case *packets.Packet_DirectMessage:
+			if c.allowChatPacket(0, msg) {
+				c.sendDirectMessage(context.Background(), msg.DirectMessage)
+			}
+			continue
+		case *packets.Packet_SetPresence:
+			c.setPresence(context.Background(), msg.SetPresence.Status)
+			continue
+		}
+
+		if !c.IsInRoom(packet.RoomId) {
+			c.SocketSend(packets.NewDenyResponsePkt("Not a member of this room"))
+			continue
 		}
 
-		c.ProcessMessage(packet.SenderId, packet.RoomId, packet.Msg)
+		if isRateLimited(packet.Msg) && !c.allowChatPacket(packet.RoomId, packet.Msg) {
+			continue
+		}
+
+		// Only the packets clients can send to a room, each handled by the server
+		switch msg := packet.Msg.(type) {
+		case *packets.Packet_Chat:
+			c.sendChat(context.Background(), packet.RoomId, msg.Chat)
+		case *packets.Packet_EditChat:
+			c.editChat(context.Background(), packet.RoomId, msg.EditChat)
+		case *packets.Packet_DeleteChat:
+			c.deleteChat(context.Background(), packet.RoomId, msg.DeleteChat)
+		case *packets.Packet_AddReaction:
+			c.setReaction(context.Background(), packet.RoomId, msg.AddReaction.MessageId, msg.AddReaction.Emoji, true)
+		case *packets.Packet_RemoveReaction:
+			c.setReaction(context.Background(), packet.RoomId, msg.RemoveReaction.MessageId, msg.RemoveReaction.Emoji, false)
+		case *packets.Packet_ThreadRequest:
+			c.sendThread(context.Background(), packet.RoomId, msg.ThreadRequest)
+		case *packets.Packet_Typing:
+			c.setTyping(packet.RoomId, msg.Typing.Typing)
+		case *packets.Packet_MarkRead:
+			c.markRead(context.Background(), packet.RoomId, msg.MarkRead.MessageId)
+		case *packets.Packet_SlowMode:
+			c.setSlowMode(context.Background(), packet.RoomId, msg.SlowMode.Seconds)
+		case *packets.Packet_RoomUpdated:
+			_, err := c.updateRoomDetails(context.Background(), packet.RoomId, msg.RoomUpdated.Topic, msg.RoomUpdated.Description)
+			if err != nil {
+				c.denyChatChange(err, "Unable to update the room")
+			}
+		default:
+			// Everything else is sent by the server only, clients can't relay it
+			c.SocketSend(packets.NewDenyResponsePkt("Unsupported packet"))
+		}
 	}
 }
 
@@ -227,64 +446,111 @@ func (c *WebSocketClient) ReadPump() {
 func (c *WebSocketClient) WritePump() {
 	defer func() {
 		c.Close("write pump closed")
+		c.conn.Close()
 	}()
 
 	ticker := time.NewTicker(pingInterval)
 
 	for {
 		select {
-		case packet, ok := <-c.sendChan:
-			if !ok {
-				if err := c.conn.WriteMessage(websocket.CloseMessage, nil); err != nil {
-					c.logger.Printf("connection closed: %v", err)
-				}
+		case <-c.done:
+			c.writeCloseMessage()
+			return
+		case packet := <-c.sendChan:
+			if !c.writePacket(packet) {
 				return
 			}
-			writer, err := c.conn.NextWriter(websocket.BinaryMessage)
-			if err != nil {
-				c.logger.Printf("error getting writer for %T packet, closing client: %v", packet.Msg, err)
+			if !c.writeMissedPackets() {
 				return
 			}
-
-			data, err := proto.Marshal(packet)
-			if err != nil {
-				c.logger.Printf("error marshalling %T packet, closing client: %v", packet.Msg, err)
-				continue
-			}
-
-			_, err = writer.Write(data)
-			if err != nil {
-				c.logger.Printf("error writing %T packet: %v", packet.Msg, err)
-				continue
-			}
-
-			if err = writer.Close(); err != nil {
-				c.logger.Printf("error closing writer for %T packet: %v", packet.Msg, err)
-				continue
-			}
 		case <-ticker.C:
 			if err := c.conn.WriteMessage(websocket.PingMessage, []byte(``)); err != nil {
 				log.Printf("error sending ping %v", err)
 				return
 			}
+			if !c.writeMissedPackets() {
+				return
+			}
 		}
 	}
 }
 
-func (c *WebSocketClient) Close(reason string) {
-	c.logger.Printf("Closing client connection because: %s", reason)
+// Writes the packet to the socket. Returns false when the connection is broken
+func (c *WebSocketClient) writePacket(packet *packets.Packet) bool {
+	writer, err := c.conn.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		c.logger.Printf("error getting writer for %T packet, closing client: %v", packet.Msg, err)
+		return false
+	}
 
-	c.conn.Close()
+	data, err := proto.Marshal(packet)
+	if err != nil {
+		c.logger.Printf("error marshalling %T packet, closing client: %v", packet.Msg, err)
+		return true
+	}
 
-	select {
-	case c.hub.UnregisterChan <- c:
-	default:
+	_, err = writer.Write(data)
+	if err != nil {
+		c.logger.Printf("error writing %T packet: %v", packet.Msg, err)
+		return true
 	}
 
-	defer func() {
-		recover()
-	}()
-	close(c.sendChan)
+	if err = writer.Close(); err != nil {
+		c.logger.Printf("error closing writer for %T packet: %v", packet.Msg, err)
+	}
+	return true
+}
+
+// Tells the client about the packets it missed since the last notice. The
+// notice skips sendChan, which is likely what was full
+func (c *WebSocketClient) writeMissedPackets() bool {
+	notice := c.missed.take()
+	if notice == nil {
+		return true
+	}
+	return c.writePacket(&packets.Packet{SenderId: c.id, Msg: notice})
+}
+
+// Sends the close frame, with the code the client was closed with if any
+func (c *WebSocketClient) writeCloseMessage() {
+	message := []byte{}
+	if closeMessage := c.closeMessage.Load(); closeMessage != nil {
+		message = *closeMessage
+	}
+	if err := c.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(closeMessageTimeout)); err != nil {
+		c.logger.Printf("connection closed: %v", err)
+	}
+}
+
+// Stops the client without waiting on its connection, which can be called from
+// the hub. WritePump sends the close frame and closes the connection
+func (c *WebSocketClient) Close(reason string) {
+	c.closeOnce.Do(func() {
+		c.logger.Printf("Closing client connection because: %s", reason)
+		if dropped := c.dropped.Load(); dropped > 0 {
+			c.logger.Printf("Dropped %d packets for this client", dropped)
+		}
+
+		close(c.done)
+		c.stopAllTyping()
+		// In case WritePump is stuck writing to a client that stopped reading
+		time.AfterFunc(closeMessageTimeout, func() {
+			c.conn.Close()
+		})
+
+		// Close can be called from the hub itself, so don't wait for it
+		go func() {
+			c.hub.UnregisterChan <- c
+			c.service.PresenceDisconnected(context.Background(), c.hub, c)
+		}()
+	})
+}
+
+// Closes the client, telling it why with the close frame WritePump sends
+func (c *WebSocketClient) CloseWithCode(code int, text string, reason string) {
+	message := websocket.FormatCloseMessage(code, text)
+	c.closeMessage.CompareAndSwap(nil, &message)
+	c.Close(reason)
 }
 
 func (c *WebSocketClient) pongHandler(pongMsg string) error {
@@ -22,12 +22,178 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type PresenceStatus int32
+
+const (
+	PresenceStatus_PRESENCE_OFFLINE        PresenceStatus = 0
+	PresenceStatus_PRESENCE_ONLINE         PresenceStatus = 1
+	PresenceStatus_PRESENCE_IDLE           PresenceStatus = 2
+	PresenceStatus_PRESENCE_AWAY           PresenceStatus = 3
+	PresenceStatus_PRESENCE_DO_NOT_DISTURB PresenceStatus = 4
+)
+
+// Enum value maps for PresenceStatus.
+var (
+	PresenceStatus_name = map[int32]string{
+		0: "PRESENCE_OFFLINE",
+		1: "PRESENCE_ONLINE",
+		2: "PRESENCE_IDLE",
+		3: "PRESENCE_AWAY",
+		4: "PRESENCE_DO_NOT_DISTURB",
+	}
+	PresenceStatus_value = map[string]int32{
+		"PRESENCE_OFFLINE":        0,
+		"PRESENCE_ONLINE":         1,
+		"PRESENCE_IDLE":           2,
+		"PRESENCE_AWAY":           3,
+		"PRESENCE_DO_NOT_DISTURB": 4,
+	}
+)
+
+func (x PresenceStatus) Enum() *PresenceStatus {
+	p := new(PresenceStatus)
+	*p = x
+	return p
+}
+
+func (x PresenceStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PresenceStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_packets_proto_enumTypes[0].Descriptor()
+}
+
+func (PresenceStatus) Type() protoreflect.EnumType {
+	return &file_packets_proto_enumTypes[0]
+}
+
+func (x PresenceStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PresenceStatus.Descriptor instead.
+func (PresenceStatus) EnumDescriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{0}
+}
+
+// Public rooms are listed to everyone, unlisted ones can be joined by anyone with
+// their id, and private ones only by their members
+type RoomVisibility int32
+
+const (
+	RoomVisibility_ROOM_PUBLIC   RoomVisibility = 0
+	RoomVisibility_ROOM_PRIVATE  RoomVisibility = 1
+	RoomVisibility_ROOM_UNLISTED RoomVisibility = 2
+)
+
+// Enum value maps for RoomVisibility.
+var (
+	RoomVisibility_name = map[int32]string{
+		0: "ROOM_PUBLIC",
+		1: "ROOM_PRIVATE",
+		2: "ROOM_UNLISTED",
+	}
+	RoomVisibility_value = map[string]int32{
+		"ROOM_PUBLIC":   0,
+		"ROOM_PRIVATE":  1,
+		"ROOM_UNLISTED": 2,
+	}
+)
+
+func (x RoomVisibility) Enum() *RoomVisibility {
+	p := new(RoomVisibility)
+	*p = x
+	return p
+}
+
+func (x RoomVisibility) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoomVisibility) Descriptor() protoreflect.EnumDescriptor {
+	return file_packets_proto_enumTypes[1].Descriptor()
+}
+
+func (RoomVisibility) Type() protoreflect.EnumType {
+	return &file_packets_proto_enumTypes[1]
+}
+
+func (x RoomVisibility) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoomVisibility.Descriptor instead.
+func (RoomVisibility) EnumDescriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{1}
+}
+
+type RoomRole int32
+
+const (
+	RoomRole_ROOM_ROLE_MEMBER    RoomRole = 0
+	RoomRole_ROOM_ROLE_MODERATOR RoomRole = 1
+	RoomRole_ROOM_ROLE_OWNER     RoomRole = 2
+)
+
+// Enum value maps for RoomRole.
+var (
+	RoomRole_name = map[int32]string{
+		0: "ROOM_ROLE_MEMBER",
+		1: "ROOM_ROLE_MODERATOR",
+		2: "ROOM_ROLE_OWNER",
+	}
+	RoomRole_value = map[string]int32{
+		"ROOM_ROLE_MEMBER":    0,
+		"ROOM_ROLE_MODERATOR": 1,
+		"ROOM_ROLE_OWNER":     2,
+	}
+)
+
+func (x RoomRole) Enum() *RoomRole {
+	p := new(RoomRole)
+	*p = x
+	return p
+}
+
+func (x RoomRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RoomRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_packets_proto_enumTypes[2].Descriptor()
+}
+
+func (RoomRole) Type() protoreflect.EnumType {
+	return &file_packets_proto_enumTypes[2]
+}
+
+func (x RoomRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RoomRole.Descriptor instead.
+func (RoomRole) EnumDescriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{2}
+}
+
 // WS
 type ChatMessage struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	SenderUsername string                 `protobuf:"bytes,2,opt,name=senderUsername,proto3" json:"senderUsername,omitempty"`
 	Msg            string                 `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	Id             uint64                 `protobuf:"varint,4,opt,name=id,proto3" json:"id,omitempty"`
+	SenderUserId   string                 `protobuf:"bytes,5,opt,name=sender_user_id,json=senderUserId,proto3" json:"sender_user_id,omitempty"`
+	EditedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
+	Reactions      []*ReactionSummary     `protobuf:"bytes,7,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	ParentId       uint64                 `protobuf:"varint,8,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	ReplyCount     uint32                 `protobuf:"varint,9,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	MentionIds     []string               `protobuf:"bytes,10,rep,name=mention_ids,json=mentionIds,proto3" json:"mention_ids,omitempty"`
+	ClientId       string                 `protobuf:"bytes,11,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	Attachments    []*AttachmentMessage   `protobuf:"bytes,12,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	Previews       []*LinkPreviewMessage  `protobuf:"bytes,13,rep,name=previews,proto3" json:"previews,omitempty"`
+	Action         bool                   `protobuf:"varint,14,opt,name=action,proto3" json:"action,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
@@ -83,29 +249,110 @@ func (x *ChatMessage) GetMsg() string {
 	return ""
 }
 
-type IdMessage struct {
+func (x *ChatMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetSenderUserId() string {
+	if x != nil {
+		return x.SenderUserId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetReactions() []*ReactionSummary {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetParentId() uint64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetReplyCount() uint32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetMentionIds() []string {
+	if x != nil {
+		return x.MentionIds
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetAttachments() []*AttachmentMessage {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetPreviews() []*LinkPreviewMessage {
+	if x != nil {
+		return x.Previews
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetAction() bool {
+	if x != nil {
+		return x.Action
+	}
+	return false
+}
+
+type AttachmentMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
-	Room          *RoomRegisteredMessage `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Mime          string                 `protobuf:"bytes,3,opt,name=mime,proto3" json:"mime,omitempty"`
+	Size          uint64                 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Width         uint32                 `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	Thumbnails    []*ThumbnailMessage    `protobuf:"bytes,7,rep,name=thumbnails,proto3" json:"thumbnails,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IdMessage) Reset() {
-	*x = IdMessage{}
+func (x *AttachmentMessage) Reset() {
+	*x = AttachmentMessage{}
 	mi := &file_packets_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IdMessage) String() string {
+func (x *AttachmentMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IdMessage) ProtoMessage() {}
+func (*AttachmentMessage) ProtoMessage() {}
 
-func (x *IdMessage) ProtoReflect() protoreflect.Message {
+func (x *AttachmentMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -117,54 +364,84 @@ func (x *IdMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IdMessage.ProtoReflect.Descriptor instead.
-func (*IdMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use AttachmentMessage.ProtoReflect.Descriptor instead.
+func (*AttachmentMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *IdMessage) GetId() uint64 {
+func (x *AttachmentMessage) GetId() string {
 	if x != nil {
 		return x.Id
 	}
-	return 0
+	return ""
 }
 
-func (x *IdMessage) GetUsername() string {
+func (x *AttachmentMessage) GetFilename() string {
 	if x != nil {
-		return x.Username
+		return x.Filename
 	}
 	return ""
 }
 
-func (x *IdMessage) GetRoom() *RoomRegisteredMessage {
+func (x *AttachmentMessage) GetMime() string {
 	if x != nil {
-		return x.Room
+		return x.Mime
+	}
+	return ""
+}
+
+func (x *AttachmentMessage) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *AttachmentMessage) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *AttachmentMessage) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *AttachmentMessage) GetThumbnails() []*ThumbnailMessage {
+	if x != nil {
+		return x.Thumbnails
 	}
 	return nil
 }
 
-type RegisterMessage struct {
+type ThumbnailMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Size          uint32                 `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	Width         uint32                 `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        uint32                 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Mime          string                 `protobuf:"bytes,4,opt,name=mime,proto3" json:"mime,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterMessage) Reset() {
-	*x = RegisterMessage{}
+func (x *ThumbnailMessage) Reset() {
+	*x = ThumbnailMessage{}
 	mi := &file_packets_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterMessage) String() string {
+func (x *ThumbnailMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterMessage) ProtoMessage() {}
+func (*ThumbnailMessage) ProtoMessage() {}
 
-func (x *RegisterMessage) ProtoReflect() protoreflect.Message {
+func (x *ThumbnailMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -176,46 +453,64 @@ func (x *RegisterMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterMessage.ProtoReflect.Descriptor instead.
-func (*RegisterMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ThumbnailMessage.ProtoReflect.Descriptor instead.
+func (*ThumbnailMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *RegisterMessage) GetId() uint64 {
+func (x *ThumbnailMessage) GetSize() uint32 {
 	if x != nil {
-		return x.Id
+		return x.Size
 	}
 	return 0
 }
 
-func (x *RegisterMessage) GetUsername() string {
+func (x *ThumbnailMessage) GetWidth() uint32 {
 	if x != nil {
-		return x.Username
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ThumbnailMessage) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ThumbnailMessage) GetMime() string {
+	if x != nil {
+		return x.Mime
 	}
 	return ""
 }
 
-type UnregisterMessage struct {
+type LinkPreviewMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	SiteName      string                 `protobuf:"bytes,5,opt,name=site_name,json=siteName,proto3" json:"site_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnregisterMessage) Reset() {
-	*x = UnregisterMessage{}
+func (x *LinkPreviewMessage) Reset() {
+	*x = LinkPreviewMessage{}
 	mi := &file_packets_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnregisterMessage) String() string {
+func (x *LinkPreviewMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnregisterMessage) ProtoMessage() {}
+func (*LinkPreviewMessage) ProtoMessage() {}
 
-func (x *UnregisterMessage) ProtoReflect() protoreflect.Message {
+func (x *LinkPreviewMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -227,41 +522,68 @@ func (x *UnregisterMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnregisterMessage.ProtoReflect.Descriptor instead.
-func (*UnregisterMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use LinkPreviewMessage.ProtoReflect.Descriptor instead.
+func (*LinkPreviewMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *UnregisterMessage) GetId() uint64 {
+func (x *LinkPreviewMessage) GetUrl() string {
 	if x != nil {
-		return x.Id
+		return x.Url
 	}
-	return 0
+	return ""
 }
 
-type RoomRegisteredMessage struct {
+func (x *LinkPreviewMessage) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *LinkPreviewMessage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LinkPreviewMessage) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *LinkPreviewMessage) GetSiteName() string {
+	if x != nil {
+		return x.SiteName
+	}
+	return ""
+}
+
+type MessageUpdatedMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	OwnerId       string                 `protobuf:"bytes,2,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Previews      []*LinkPreviewMessage  `protobuf:"bytes,2,rep,name=previews,proto3" json:"previews,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomRegisteredMessage) Reset() {
-	*x = RoomRegisteredMessage{}
+func (x *MessageUpdatedMessage) Reset() {
+	*x = MessageUpdatedMessage{}
 	mi := &file_packets_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomRegisteredMessage) String() string {
+func (x *MessageUpdatedMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomRegisteredMessage) ProtoMessage() {}
+func (*MessageUpdatedMessage) ProtoMessage() {}
 
-func (x *RoomRegisteredMessage) ProtoReflect() protoreflect.Message {
+func (x *MessageUpdatedMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -273,55 +595,48 @@ func (x *RoomRegisteredMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomRegisteredMessage.ProtoReflect.Descriptor instead.
-func (*RoomRegisteredMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use MessageUpdatedMessage.ProtoReflect.Descriptor instead.
+func (*MessageUpdatedMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RoomRegisteredMessage) GetId() uint64 {
+func (x *MessageUpdatedMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.Id
+		return x.MessageId
 	}
 	return 0
 }
 
-func (x *RoomRegisteredMessage) GetOwnerId() string {
-	if x != nil {
-		return x.OwnerId
-	}
-	return ""
-}
-
-func (x *RoomRegisteredMessage) GetName() string {
+func (x *MessageUpdatedMessage) GetPreviews() []*LinkPreviewMessage {
 	if x != nil {
-		return x.Name
+		return x.Previews
 	}
-	return ""
+	return nil
 }
 
-// HTTP
-type JwtMessage struct {
+type ChatSentMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
-	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ClientId      string                 `protobuf:"bytes,3,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JwtMessage) Reset() {
-	*x = JwtMessage{}
+func (x *ChatSentMessage) Reset() {
+	*x = ChatSentMessage{}
 	mi := &file_packets_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JwtMessage) String() string {
+func (x *ChatSentMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JwtMessage) ProtoMessage() {}
+func (*ChatSentMessage) ProtoMessage() {}
 
-func (x *JwtMessage) ProtoReflect() protoreflect.Message {
+func (x *ChatSentMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -333,47 +648,55 @@ func (x *JwtMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JwtMessage.ProtoReflect.Descriptor instead.
-func (*JwtMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChatSentMessage.ProtoReflect.Descriptor instead.
+func (*ChatSentMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *JwtMessage) GetAccessToken() string {
+func (x *ChatSentMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.AccessToken
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *JwtMessage) GetRefreshToken() string {
+func (x *ChatSentMessage) GetTimestamp() *timestamppb.Timestamp {
 	if x != nil {
-		return x.RefreshToken
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ChatSentMessage) GetClientId() string {
+	if x != nil {
+		return x.ClientId
 	}
 	return ""
 }
 
-type LoginRequestMessage struct {
+type EditChatMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Msg           string                 `protobuf:"bytes,2,opt,name=msg,proto3" json:"msg,omitempty"`
+	EditedAt      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LoginRequestMessage) Reset() {
-	*x = LoginRequestMessage{}
+func (x *EditChatMessage) Reset() {
+	*x = EditChatMessage{}
 	mi := &file_packets_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LoginRequestMessage) String() string {
+func (x *EditChatMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LoginRequestMessage) ProtoMessage() {}
+func (*EditChatMessage) ProtoMessage() {}
 
-func (x *LoginRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *EditChatMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -385,47 +708,53 @@ func (x *LoginRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LoginRequestMessage.ProtoReflect.Descriptor instead.
-func (*LoginRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use EditChatMessage.ProtoReflect.Descriptor instead.
+func (*EditChatMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *LoginRequestMessage) GetUsername() string {
+func (x *EditChatMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.Username
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-func (x *LoginRequestMessage) GetPassword() string {
+func (x *EditChatMessage) GetMsg() string {
 	if x != nil {
-		return x.Password
+		return x.Msg
 	}
 	return ""
 }
 
-type RegisterRequestMessage struct {
+func (x *EditChatMessage) GetEditedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EditedAt
+	}
+	return nil
+}
+
+type DeleteChatMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterRequestMessage) Reset() {
-	*x = RegisterRequestMessage{}
+func (x *DeleteChatMessage) Reset() {
+	*x = DeleteChatMessage{}
 	mi := &file_packets_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterRequestMessage) String() string {
+func (x *DeleteChatMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterRequestMessage) ProtoMessage() {}
+func (*DeleteChatMessage) ProtoMessage() {}
 
-func (x *RegisterRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *DeleteChatMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -437,45 +766,41 @@ func (x *RegisterRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterRequestMessage.ProtoReflect.Descriptor instead.
-func (*RegisterRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteChatMessage.ProtoReflect.Descriptor instead.
+func (*DeleteChatMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *RegisterRequestMessage) GetUsername() string {
-	if x != nil {
-		return x.Username
-	}
-	return ""
-}
-
-func (x *RegisterRequestMessage) GetPassword() string {
+func (x *DeleteChatMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.Password
+		return x.MessageId
 	}
-	return ""
+	return 0
 }
 
-type RefreshRequestMessage struct {
+type ReactionSummary struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emoji         string                 `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	UserIds       []string               `protobuf:"bytes,3,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefreshRequestMessage) Reset() {
-	*x = RefreshRequestMessage{}
+func (x *ReactionSummary) Reset() {
+	*x = ReactionSummary{}
 	mi := &file_packets_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RefreshRequestMessage) String() string {
+func (x *ReactionSummary) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RefreshRequestMessage) ProtoMessage() {}
+func (*ReactionSummary) ProtoMessage() {}
 
-func (x *RefreshRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *ReactionSummary) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -487,31 +812,54 @@ func (x *RefreshRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RefreshRequestMessage.ProtoReflect.Descriptor instead.
-func (*RefreshRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReactionSummary.ProtoReflect.Descriptor instead.
+func (*ReactionSummary) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{8}
 }
 
-type LogoutRequestMessage struct {
+func (x *ReactionSummary) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionSummary) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionSummary) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type AddReactionMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Emoji         string                 `protobuf:"bytes,2,opt,name=emoji,proto3" json:"emoji,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogoutRequestMessage) Reset() {
-	*x = LogoutRequestMessage{}
+func (x *AddReactionMessage) Reset() {
+	*x = AddReactionMessage{}
 	mi := &file_packets_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogoutRequestMessage) String() string {
+func (x *AddReactionMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogoutRequestMessage) ProtoMessage() {}
+func (*AddReactionMessage) ProtoMessage() {}
 
-func (x *LogoutRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *AddReactionMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -523,33 +871,47 @@ func (x *LogoutRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogoutRequestMessage.ProtoReflect.Descriptor instead.
-func (*LogoutRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use AddReactionMessage.ProtoReflect.Descriptor instead.
+func (*AddReactionMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{9}
 }
 
-type NewRoomRequestMessage struct {
+func (x *AddReactionMessage) GetMessageId() uint64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *AddReactionMessage) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+type RemoveReactionMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        uint64                 `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Emoji         string                 `protobuf:"bytes,2,opt,name=emoji,proto3" json:"emoji,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NewRoomRequestMessage) Reset() {
-	*x = NewRoomRequestMessage{}
+func (x *RemoveReactionMessage) Reset() {
+	*x = RemoveReactionMessage{}
 	mi := &file_packets_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NewRoomRequestMessage) String() string {
+func (x *RemoveReactionMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewRoomRequestMessage) ProtoMessage() {}
+func (*RemoveReactionMessage) ProtoMessage() {}
 
-func (x *NewRoomRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *RemoveReactionMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -561,48 +923,47 @@ func (x *NewRoomRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewRoomRequestMessage.ProtoReflect.Descriptor instead.
-func (*NewRoomRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use RemoveReactionMessage.ProtoReflect.Descriptor instead.
+func (*RemoveReactionMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *NewRoomRequestMessage) GetRoomId() uint64 {
+func (x *RemoveReactionMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.RoomId
+		return x.MessageId
 	}
 	return 0
 }
 
-func (x *NewRoomRequestMessage) GetName() string {
+func (x *RemoveReactionMessage) GetEmoji() string {
 	if x != nil {
-		return x.Name
+		return x.Emoji
 	}
 	return ""
 }
 
-type NewRoomResponseMessage struct {
+type ReactionsMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        uint64                 `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`
-	OwnerId       string                 `protobuf:"bytes,2,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Reactions     []*ReactionSummary     `protobuf:"bytes,2,rep,name=reactions,proto3" json:"reactions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NewRoomResponseMessage) Reset() {
-	*x = NewRoomResponseMessage{}
+func (x *ReactionsMessage) Reset() {
+	*x = ReactionsMessage{}
 	mi := &file_packets_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NewRoomResponseMessage) String() string {
+func (x *ReactionsMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewRoomResponseMessage) ProtoMessage() {}
+func (*ReactionsMessage) ProtoMessage() {}
 
-func (x *NewRoomResponseMessage) ProtoReflect() protoreflect.Message {
+func (x *ReactionsMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -614,52 +975,48 @@ func (x *NewRoomResponseMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewRoomResponseMessage.ProtoReflect.Descriptor instead.
-func (*NewRoomResponseMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReactionsMessage.ProtoReflect.Descriptor instead.
+func (*ReactionsMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *NewRoomResponseMessage) GetRoomId() uint64 {
+func (x *ReactionsMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.RoomId
+		return x.MessageId
 	}
 	return 0
 }
 
-func (x *NewRoomResponseMessage) GetOwnerId() string {
-	if x != nil {
-		return x.OwnerId
-	}
-	return ""
-}
-
-func (x *NewRoomResponseMessage) GetName() string {
+func (x *ReactionsMessage) GetReactions() []*ReactionSummary {
 	if x != nil {
-		return x.Name
+		return x.Reactions
 	}
-	return ""
+	return nil
 }
 
-type RoomsRequestMessage struct {
+type ThreadRequestMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	ParentId      uint64                 `protobuf:"varint,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	AfterId       uint64                 `protobuf:"varint,2,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	Limit         uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomsRequestMessage) Reset() {
-	*x = RoomsRequestMessage{}
+func (x *ThreadRequestMessage) Reset() {
+	*x = ThreadRequestMessage{}
 	mi := &file_packets_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomsRequestMessage) String() string {
+func (x *ThreadRequestMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomsRequestMessage) ProtoMessage() {}
+func (*ThreadRequestMessage) ProtoMessage() {}
 
-func (x *RoomsRequestMessage) ProtoReflect() protoreflect.Message {
+func (x *ThreadRequestMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -671,32 +1028,54 @@ func (x *RoomsRequestMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomsRequestMessage.ProtoReflect.Descriptor instead.
-func (*RoomsRequestMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ThreadRequestMessage.ProtoReflect.Descriptor instead.
+func (*ThreadRequestMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{12}
 }
 
-type RoomsResponseMessage struct {
-	state         protoimpl.MessageState    `protogen:"open.v1"`
-	Rooms         []*NewRoomResponseMessage `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+func (x *ThreadRequestMessage) GetParentId() uint64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *ThreadRequestMessage) GetAfterId() uint64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *ThreadRequestMessage) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ThreadMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parent        *ChatMessage           `protobuf:"bytes,1,opt,name=parent,proto3" json:"parent,omitempty"`
+	Replies       []*ChatMessage         `protobuf:"bytes,2,rep,name=replies,proto3" json:"replies,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RoomsResponseMessage) Reset() {
-	*x = RoomsResponseMessage{}
+func (x *ThreadMessage) Reset() {
+	*x = ThreadMessage{}
 	mi := &file_packets_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RoomsResponseMessage) String() string {
+func (x *ThreadMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RoomsResponseMessage) ProtoMessage() {}
+func (*ThreadMessage) ProtoMessage() {}
 
-func (x *RoomsResponseMessage) ProtoReflect() protoreflect.Message {
+func (x *ThreadMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -708,38 +1087,47 @@ func (x *RoomsResponseMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RoomsResponseMessage.ProtoReflect.Descriptor instead.
-func (*RoomsResponseMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ThreadMessage.ProtoReflect.Descriptor instead.
+func (*ThreadMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *RoomsResponseMessage) GetRooms() []*NewRoomResponseMessage {
+func (x *ThreadMessage) GetParent() *ChatMessage {
 	if x != nil {
-		return x.Rooms
+		return x.Parent
 	}
 	return nil
 }
 
-type OkResponseMessage struct {
+func (x *ThreadMessage) GetReplies() []*ChatMessage {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+type ThreadUpdatedMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ReplyCount    uint32                 `protobuf:"varint,2,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *OkResponseMessage) Reset() {
-	*x = OkResponseMessage{}
+func (x *ThreadUpdatedMessage) Reset() {
+	*x = ThreadUpdatedMessage{}
 	mi := &file_packets_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *OkResponseMessage) String() string {
+func (x *ThreadUpdatedMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*OkResponseMessage) ProtoMessage() {}
+func (*ThreadUpdatedMessage) ProtoMessage() {}
 
-func (x *OkResponseMessage) ProtoReflect() protoreflect.Message {
+func (x *ThreadUpdatedMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -751,32 +1139,48 @@ func (x *OkResponseMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use OkResponseMessage.ProtoReflect.Descriptor instead.
-func (*OkResponseMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use ThreadUpdatedMessage.ProtoReflect.Descriptor instead.
+func (*ThreadUpdatedMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{14}
 }
 
-type DenyResponseMessage struct {
+func (x *ThreadUpdatedMessage) GetMessageId() uint64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *ThreadUpdatedMessage) GetReplyCount() uint32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+type TypingMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Typing        bool                   `protobuf:"varint,3,opt,name=typing,proto3" json:"typing,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DenyResponseMessage) Reset() {
-	*x = DenyResponseMessage{}
+func (x *TypingMessage) Reset() {
+	*x = TypingMessage{}
 	mi := &file_packets_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DenyResponseMessage) String() string {
+func (x *TypingMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DenyResponseMessage) ProtoMessage() {}
+func (*TypingMessage) ProtoMessage() {}
 
-func (x *DenyResponseMessage) ProtoReflect() protoreflect.Message {
+func (x *TypingMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -788,49 +1192,53 @@ func (x *DenyResponseMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DenyResponseMessage.ProtoReflect.Descriptor instead.
-func (*DenyResponseMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use TypingMessage.ProtoReflect.Descriptor instead.
+func (*TypingMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *DenyResponseMessage) GetReason() string {
+func (x *TypingMessage) GetUserId() string {
 	if x != nil {
-		return x.Reason
+		return x.UserId
 	}
 	return ""
 }
 
-type Packet struct {
-	state    protoimpl.MessageState `protogen:"open.v1"`
-	SenderId uint64                 `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	RoomId   uint64                 `protobuf:"varint,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	// Types that are valid to be assigned to Msg:
-	//
-	//	*Packet_Chat
-	//	*Packet_Id
-	//	*Packet_Register
-	//	*Packet_Unregister
-	//	*Packet_OkResponse
-	//	*Packet_DenyResponse
-	Msg           isPacket_Msg `protobuf_oneof:"msg"`
+func (x *TypingMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *TypingMessage) GetTyping() bool {
+	if x != nil {
+		return x.Typing
+	}
+	return false
+}
+
+type MarkReadMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     uint64                 `protobuf:"varint,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Packet) Reset() {
-	*x = Packet{}
+func (x *MarkReadMessage) Reset() {
+	*x = MarkReadMessage{}
 	mi := &file_packets_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Packet) String() string {
+func (x *MarkReadMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Packet) ProtoMessage() {}
+func (*MarkReadMessage) ProtoMessage() {}
 
-func (x *Packet) ProtoReflect() protoreflect.Message {
+func (x *MarkReadMessage) ProtoReflect() protoreflect.Message {
 	mi := &file_packets_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -842,267 +1250,4172 @@ func (x *Packet) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Packet.ProtoReflect.Descriptor instead.
-func (*Packet) Descriptor() ([]byte, []int) {
+// Deprecated: Use MarkReadMessage.ProtoReflect.Descriptor instead.
+func (*MarkReadMessage) Descriptor() ([]byte, []int) {
 	return file_packets_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *Packet) GetSenderId() uint64 {
+func (x *MarkReadMessage) GetMessageId() uint64 {
 	if x != nil {
-		return x.SenderId
+		return x.MessageId
 	}
 	return 0
 }
 
-func (x *Packet) GetRoomId() uint64 {
-	if x != nil {
-		return x.RoomId
-	}
-	return 0
+type ReadPositionMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MessageId     uint64                 `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Packet) GetMsg() isPacket_Msg {
-	if x != nil {
-		return x.Msg
-	}
-	return nil
+func (x *ReadPositionMessage) Reset() {
+	*x = ReadPositionMessage{}
+	mi := &file_packets_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *Packet) GetChat() *ChatMessage {
+func (x *ReadPositionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadPositionMessage) ProtoMessage() {}
+
+func (x *ReadPositionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[17]
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_Chat); ok {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadPositionMessage.ProtoReflect.Descriptor instead.
+func (*ReadPositionMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ReadPositionMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReadPositionMessage) GetMessageId() uint64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+type PresenceMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        PresenceStatus         `protobuf:"varint,2,opt,name=status,proto3,enum=packets.PresenceStatus" json:"status,omitempty"`
+	LastSeen      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PresenceMessage) Reset() {
+	*x = PresenceMessage{}
+	mi := &file_packets_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PresenceMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PresenceMessage) ProtoMessage() {}
+
+func (x *PresenceMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PresenceMessage.ProtoReflect.Descriptor instead.
+func (*PresenceMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PresenceMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PresenceMessage) GetStatus() PresenceStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PresenceStatus_PRESENCE_OFFLINE
+}
+
+func (x *PresenceMessage) GetLastSeen() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeen
+	}
+	return nil
+}
+
+type SetPresenceMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        PresenceStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=packets.PresenceStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetPresenceMessage) Reset() {
+	*x = SetPresenceMessage{}
+	mi := &file_packets_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPresenceMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPresenceMessage) ProtoMessage() {}
+
+func (x *SetPresenceMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPresenceMessage.ProtoReflect.Descriptor instead.
+func (*SetPresenceMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetPresenceMessage) GetStatus() PresenceStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PresenceStatus_PRESENCE_OFFLINE
+}
+
+type SlowModeMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seconds       uint32                 `protobuf:"varint,1,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SlowModeMessage) Reset() {
+	*x = SlowModeMessage{}
+	mi := &file_packets_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SlowModeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SlowModeMessage) ProtoMessage() {}
+
+func (x *SlowModeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SlowModeMessage.ProtoReflect.Descriptor instead.
+func (*SlowModeMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SlowModeMessage) GetSeconds() uint32 {
+	if x != nil {
+		return x.Seconds
+	}
+	return 0
+}
+
+type SystemMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemMessage) Reset() {
+	*x = SystemMessage{}
+	mi := &file_packets_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemMessage) ProtoMessage() {}
+
+func (x *SystemMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemMessage.ProtoReflect.Descriptor instead.
+func (*SystemMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SystemMessage) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type RoomUpdatedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Topic         string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	UpdaterId     string                 `protobuf:"bytes,5,opt,name=updater_id,json=updaterId,proto3" json:"updater_id,omitempty"`
+	Visibility    RoomVisibility         `protobuf:"varint,6,opt,name=visibility,proto3,enum=packets.RoomVisibility" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomUpdatedMessage) Reset() {
+	*x = RoomUpdatedMessage{}
+	mi := &file_packets_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomUpdatedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomUpdatedMessage) ProtoMessage() {}
+
+func (x *RoomUpdatedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomUpdatedMessage.ProtoReflect.Descriptor instead.
+func (*RoomUpdatedMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RoomUpdatedMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RoomUpdatedMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomUpdatedMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *RoomUpdatedMessage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RoomUpdatedMessage) GetUpdaterId() string {
+	if x != nil {
+		return x.UpdaterId
+	}
+	return ""
+}
+
+func (x *RoomUpdatedMessage) GetVisibility() RoomVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return RoomVisibility_ROOM_PUBLIC
+}
+
+type RoomInviteMessage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	RoomName        string                 `protobuf:"bytes,2,opt,name=room_name,json=roomName,proto3" json:"room_name,omitempty"`
+	InviterId       string                 `protobuf:"bytes,3,opt,name=inviter_id,json=inviterId,proto3" json:"inviter_id,omitempty"`
+	InviterUsername string                 `protobuf:"bytes,4,opt,name=inviter_username,json=inviterUsername,proto3" json:"inviter_username,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RoomInviteMessage) Reset() {
+	*x = RoomInviteMessage{}
+	mi := &file_packets_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomInviteMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomInviteMessage) ProtoMessage() {}
+
+func (x *RoomInviteMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomInviteMessage.ProtoReflect.Descriptor instead.
+func (*RoomInviteMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RoomInviteMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RoomInviteMessage) GetRoomName() string {
+	if x != nil {
+		return x.RoomName
+	}
+	return ""
+}
+
+func (x *RoomInviteMessage) GetInviterId() string {
+	if x != nil {
+		return x.InviterId
+	}
+	return ""
+}
+
+func (x *RoomInviteMessage) GetInviterUsername() string {
+	if x != nil {
+		return x.InviterUsername
+	}
+	return ""
+}
+
+type RoomDeletedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomDeletedMessage) Reset() {
+	*x = RoomDeletedMessage{}
+	mi := &file_packets_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomDeletedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomDeletedMessage) ProtoMessage() {}
+
+func (x *RoomDeletedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomDeletedMessage.ProtoReflect.Descriptor instead.
+func (*RoomDeletedMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RoomDeletedMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type MissedPacketsMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	RoomIds       []uint64               `protobuf:"varint,2,rep,packed,name=room_ids,json=roomIds,proto3" json:"room_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MissedPacketsMessage) Reset() {
+	*x = MissedPacketsMessage{}
+	mi := &file_packets_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MissedPacketsMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MissedPacketsMessage) ProtoMessage() {}
+
+func (x *MissedPacketsMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MissedPacketsMessage.ProtoReflect.Descriptor instead.
+func (*MissedPacketsMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MissedPacketsMessage) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *MissedPacketsMessage) GetRoomIds() []uint64 {
+	if x != nil {
+		return x.RoomIds
+	}
+	return nil
+}
+
+type MentionMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageId      uint64                 `protobuf:"varint,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	RoomId         uint64                 `protobuf:"varint,3,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	RoomName       string                 `protobuf:"bytes,4,opt,name=room_name,json=roomName,proto3" json:"room_name,omitempty"`
+	SenderId       string                 `protobuf:"bytes,5,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	SenderUsername string                 `protobuf:"bytes,6,opt,name=sender_username,json=senderUsername,proto3" json:"sender_username,omitempty"`
+	Msg            string                 `protobuf:"bytes,7,opt,name=msg,proto3" json:"msg,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MentionMessage) Reset() {
+	*x = MentionMessage{}
+	mi := &file_packets_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MentionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MentionMessage) ProtoMessage() {}
+
+func (x *MentionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MentionMessage.ProtoReflect.Descriptor instead.
+func (*MentionMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *MentionMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MentionMessage) GetMessageId() uint64 {
+	if x != nil {
+		return x.MessageId
+	}
+	return 0
+}
+
+func (x *MentionMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *MentionMessage) GetRoomName() string {
+	if x != nil {
+		return x.RoomName
+	}
+	return ""
+}
+
+func (x *MentionMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *MentionMessage) GetSenderUsername() string {
+	if x != nil {
+		return x.SenderUsername
+	}
+	return ""
+}
+
+func (x *MentionMessage) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *MentionMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type IdMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Room          *RoomRegisteredMessage `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdMessage) Reset() {
+	*x = IdMessage{}
+	mi := &file_packets_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdMessage) ProtoMessage() {}
+
+func (x *IdMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdMessage.ProtoReflect.Descriptor instead.
+func (*IdMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *IdMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *IdMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *IdMessage) GetRoom() *RoomRegisteredMessage {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+type RegisterMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Profile       *ProfileMessage        `protobuf:"bytes,3,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterMessage) Reset() {
+	*x = RegisterMessage{}
+	mi := &file_packets_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterMessage) ProtoMessage() {}
+
+func (x *RegisterMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterMessage.ProtoReflect.Descriptor instead.
+func (*RegisterMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RegisterMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RegisterMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RegisterMessage) GetProfile() *ProfileMessage {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+type UnregisterMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterMessage) Reset() {
+	*x = UnregisterMessage{}
+	mi := &file_packets_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterMessage) ProtoMessage() {}
+
+func (x *UnregisterMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterMessage.ProtoReflect.Descriptor instead.
+func (*UnregisterMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UnregisterMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RoomRegisteredMessage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OwnerId         string                 `protobuf:"bytes,2,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	Name            string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	LastSeq         uint64                 `protobuf:"varint,4,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	SlowModeSeconds uint32                 `protobuf:"varint,5,opt,name=slow_mode_seconds,json=slowModeSeconds,proto3" json:"slow_mode_seconds,omitempty"`
+	Topic           string                 `protobuf:"bytes,6,opt,name=topic,proto3" json:"topic,omitempty"`
+	Description     string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	Visibility      RoomVisibility         `protobuf:"varint,8,opt,name=visibility,proto3,enum=packets.RoomVisibility" json:"visibility,omitempty"`
+	Role            RoomRole               `protobuf:"varint,9,opt,name=role,proto3,enum=packets.RoomRole" json:"role,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RoomRegisteredMessage) Reset() {
+	*x = RoomRegisteredMessage{}
+	mi := &file_packets_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomRegisteredMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomRegisteredMessage) ProtoMessage() {}
+
+func (x *RoomRegisteredMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomRegisteredMessage.ProtoReflect.Descriptor instead.
+func (*RoomRegisteredMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RoomRegisteredMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RoomRegisteredMessage) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *RoomRegisteredMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RoomRegisteredMessage) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+func (x *RoomRegisteredMessage) GetSlowModeSeconds() uint32 {
+	if x != nil {
+		return x.SlowModeSeconds
+	}
+	return 0
+}
+
+func (x *RoomRegisteredMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *RoomRegisteredMessage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *RoomRegisteredMessage) GetVisibility() RoomVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return RoomVisibility_ROOM_PUBLIC
+}
+
+func (x *RoomRegisteredMessage) GetRole() RoomRole {
+	if x != nil {
+		return x.Role
+	}
+	return RoomRole_ROOM_ROLE_MEMBER
+}
+
+type JoinRoomMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	LastSeq       uint64                 `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRoomMessage) Reset() {
+	*x = JoinRoomMessage{}
+	mi := &file_packets_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRoomMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinRoomMessage) ProtoMessage() {}
+
+func (x *JoinRoomMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinRoomMessage.ProtoReflect.Descriptor instead.
+func (*JoinRoomMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *JoinRoomMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *JoinRoomMessage) GetLastSeq() uint64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+type LeaveRoomMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveRoomMessage) Reset() {
+	*x = LeaveRoomMessage{}
+	mi := &file_packets_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveRoomMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveRoomMessage) ProtoMessage() {}
+
+func (x *LeaveRoomMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveRoomMessage.ProtoReflect.Descriptor instead.
+func (*LeaveRoomMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LeaveRoomMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type DirectMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ConversationId uint64                 `protobuf:"varint,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	RecipientIds   []string               `protobuf:"bytes,3,rep,name=recipient_ids,json=recipientIds,proto3" json:"recipient_ids,omitempty"`
+	SenderId       string                 `protobuf:"bytes,4,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	SenderUsername string                 `protobuf:"bytes,5,opt,name=sender_username,json=senderUsername,proto3" json:"sender_username,omitempty"`
+	Msg            string                 `protobuf:"bytes,6,opt,name=msg,proto3" json:"msg,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DirectMessage) Reset() {
+	*x = DirectMessage{}
+	mi := &file_packets_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DirectMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DirectMessage) ProtoMessage() {}
+
+func (x *DirectMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DirectMessage.ProtoReflect.Descriptor instead.
+func (*DirectMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DirectMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DirectMessage) GetConversationId() uint64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *DirectMessage) GetRecipientIds() []string {
+	if x != nil {
+		return x.RecipientIds
+	}
+	return nil
+}
+
+func (x *DirectMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *DirectMessage) GetSenderUsername() string {
+	if x != nil {
+		return x.SenderUsername
+	}
+	return ""
+}
+
+func (x *DirectMessage) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *DirectMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type ProfileMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Bio           string                 `protobuf:"bytes,3,opt,name=bio,proto3" json:"bio,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	HasAvatar     bool                   `protobuf:"varint,5,opt,name=has_avatar,json=hasAvatar,proto3" json:"has_avatar,omitempty"`
+	Version       int64                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileMessage) Reset() {
+	*x = ProfileMessage{}
+	mi := &file_packets_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileMessage) ProtoMessage() {}
+
+func (x *ProfileMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileMessage.ProtoReflect.Descriptor instead.
+func (*ProfileMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ProfileMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ProfileMessage) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ProfileMessage) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *ProfileMessage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ProfileMessage) GetHasAvatar() bool {
+	if x != nil {
+		return x.HasAvatar
+	}
+	return false
+}
+
+func (x *ProfileMessage) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// HTTP
+type JwtMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,2,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JwtMessage) Reset() {
+	*x = JwtMessage{}
+	mi := &file_packets_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JwtMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JwtMessage) ProtoMessage() {}
+
+func (x *JwtMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JwtMessage.ProtoReflect.Descriptor instead.
+func (*JwtMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *JwtMessage) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *JwtMessage) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+type LoginRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginRequestMessage) Reset() {
+	*x = LoginRequestMessage{}
+	mi := &file_packets_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginRequestMessage) ProtoMessage() {}
+
+func (x *LoginRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginRequestMessage.ProtoReflect.Descriptor instead.
+func (*LoginRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *LoginRequestMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *LoginRequestMessage) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RegisterRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequestMessage) Reset() {
+	*x = RegisterRequestMessage{}
+	mi := &file_packets_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequestMessage) ProtoMessage() {}
+
+func (x *RegisterRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequestMessage.ProtoReflect.Descriptor instead.
+func (*RegisterRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RegisterRequestMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RegisterRequestMessage) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type RefreshRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshRequestMessage) Reset() {
+	*x = RefreshRequestMessage{}
+	mi := &file_packets_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequestMessage) ProtoMessage() {}
+
+func (x *RefreshRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshRequestMessage.ProtoReflect.Descriptor instead.
+func (*RefreshRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{38}
+}
+
+type LogoutRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogoutRequestMessage) Reset() {
+	*x = LogoutRequestMessage{}
+	mi := &file_packets_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogoutRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogoutRequestMessage) ProtoMessage() {}
+
+func (x *LogoutRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogoutRequestMessage.ProtoReflect.Descriptor instead.
+func (*LogoutRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{39}
+}
+
+type NewRoomRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Visibility    RoomVisibility         `protobuf:"varint,3,opt,name=visibility,proto3,enum=packets.RoomVisibility" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewRoomRequestMessage) Reset() {
+	*x = NewRoomRequestMessage{}
+	mi := &file_packets_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewRoomRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewRoomRequestMessage) ProtoMessage() {}
+
+func (x *NewRoomRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewRoomRequestMessage.ProtoReflect.Descriptor instead.
+func (*NewRoomRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *NewRoomRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *NewRoomRequestMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NewRoomRequestMessage) GetVisibility() RoomVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return RoomVisibility_ROOM_PUBLIC
+}
+
+type NewRoomResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=roomId,proto3" json:"roomId,omitempty"`
+	OwnerId       string                 `protobuf:"bytes,2,opt,name=ownerId,proto3" json:"ownerId,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UnreadCount   uint32                 `protobuf:"varint,4,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	LastReadId    uint64                 `protobuf:"varint,5,opt,name=last_read_id,json=lastReadId,proto3" json:"last_read_id,omitempty"`
+	Topic         string                 `protobuf:"bytes,6,opt,name=topic,proto3" json:"topic,omitempty"`
+	Description   string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	Visibility    RoomVisibility         `protobuf:"varint,8,opt,name=visibility,proto3,enum=packets.RoomVisibility" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewRoomResponseMessage) Reset() {
+	*x = NewRoomResponseMessage{}
+	mi := &file_packets_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewRoomResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewRoomResponseMessage) ProtoMessage() {}
+
+func (x *NewRoomResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewRoomResponseMessage.ProtoReflect.Descriptor instead.
+func (*NewRoomResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *NewRoomResponseMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *NewRoomResponseMessage) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *NewRoomResponseMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NewRoomResponseMessage) GetUnreadCount() uint32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *NewRoomResponseMessage) GetLastReadId() uint64 {
+	if x != nil {
+		return x.LastReadId
+	}
+	return 0
+}
+
+func (x *NewRoomResponseMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *NewRoomResponseMessage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *NewRoomResponseMessage) GetVisibility() RoomVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return RoomVisibility_ROOM_PUBLIC
+}
+
+type RoomsRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomsRequestMessage) Reset() {
+	*x = RoomsRequestMessage{}
+	mi := &file_packets_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomsRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomsRequestMessage) ProtoMessage() {}
+
+func (x *RoomsRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomsRequestMessage.ProtoReflect.Descriptor instead.
+func (*RoomsRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{42}
+}
+
+type RoomsResponseMessage struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Rooms         []*NewRoomResponseMessage `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomsResponseMessage) Reset() {
+	*x = RoomsResponseMessage{}
+	mi := &file_packets_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomsResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomsResponseMessage) ProtoMessage() {}
+
+func (x *RoomsResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomsResponseMessage.ProtoReflect.Descriptor instead.
+func (*RoomsResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RoomsResponseMessage) GetRooms() []*NewRoomResponseMessage {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+type ProfileRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileRequestMessage) Reset() {
+	*x = ProfileRequestMessage{}
+	mi := &file_packets_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileRequestMessage) ProtoMessage() {}
+
+func (x *ProfileRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileRequestMessage.ProtoReflect.Descriptor instead.
+func (*ProfileRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ProfileRequestMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Unset texts and an empty avatar keep their current value
+type UpdateProfileRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName   *string                `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3,oneof" json:"display_name,omitempty"`
+	Bio           *string                `protobuf:"bytes,2,opt,name=bio,proto3,oneof" json:"bio,omitempty"`
+	Status        *string                `protobuf:"bytes,3,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	Avatar        []byte                 `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	RemoveAvatar  bool                   `protobuf:"varint,5,opt,name=remove_avatar,json=removeAvatar,proto3" json:"remove_avatar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateProfileRequestMessage) Reset() {
+	*x = UpdateProfileRequestMessage{}
+	mi := &file_packets_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateProfileRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateProfileRequestMessage) ProtoMessage() {}
+
+func (x *UpdateProfileRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateProfileRequestMessage.ProtoReflect.Descriptor instead.
+func (*UpdateProfileRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UpdateProfileRequestMessage) GetDisplayName() string {
+	if x != nil && x.DisplayName != nil {
+		return *x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequestMessage) GetBio() string {
+	if x != nil && x.Bio != nil {
+		return *x.Bio
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequestMessage) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *UpdateProfileRequestMessage) GetAvatar() []byte {
+	if x != nil {
+		return x.Avatar
+	}
+	return nil
+}
+
+func (x *UpdateProfileRequestMessage) GetRemoveAvatar() bool {
+	if x != nil {
+		return x.RemoveAvatar
+	}
+	return false
+}
+
+type ConversationsRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversationsRequestMessage) Reset() {
+	*x = ConversationsRequestMessage{}
+	mi := &file_packets_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationsRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationsRequestMessage) ProtoMessage() {}
+
+func (x *ConversationsRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationsRequestMessage.ProtoReflect.Descriptor instead.
+func (*ConversationsRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{46}
+}
+
+type ConversationMessage struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MemberIds       []string               `protobuf:"bytes,2,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	MemberUsernames []string               `protobuf:"bytes,3,rep,name=member_usernames,json=memberUsernames,proto3" json:"member_usernames,omitempty"`
+	LastMessage     *DirectMessage         `protobuf:"bytes,4,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConversationMessage) Reset() {
+	*x = ConversationMessage{}
+	mi := &file_packets_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationMessage) ProtoMessage() {}
+
+func (x *ConversationMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationMessage.ProtoReflect.Descriptor instead.
+func (*ConversationMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ConversationMessage) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ConversationMessage) GetMemberIds() []string {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+func (x *ConversationMessage) GetMemberUsernames() []string {
+	if x != nil {
+		return x.MemberUsernames
+	}
+	return nil
+}
+
+func (x *ConversationMessage) GetLastMessage() *DirectMessage {
+	if x != nil {
+		return x.LastMessage
+	}
+	return nil
+}
+
+func (x *ConversationMessage) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ConversationsResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversations []*ConversationMessage `protobuf:"bytes,1,rep,name=conversations,proto3" json:"conversations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversationsResponseMessage) Reset() {
+	*x = ConversationsResponseMessage{}
+	mi := &file_packets_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationsResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationsResponseMessage) ProtoMessage() {}
+
+func (x *ConversationsResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationsResponseMessage.ProtoReflect.Descriptor instead.
+func (*ConversationsResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ConversationsResponseMessage) GetConversations() []*ConversationMessage {
+	if x != nil {
+		return x.Conversations
+	}
+	return nil
+}
+
+type ConversationHistoryRequestMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId uint64                 `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	BeforeId       uint64                 `protobuf:"varint,2,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"`
+	Limit          uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversationHistoryRequestMessage) Reset() {
+	*x = ConversationHistoryRequestMessage{}
+	mi := &file_packets_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationHistoryRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationHistoryRequestMessage) ProtoMessage() {}
+
+func (x *ConversationHistoryRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationHistoryRequestMessage.ProtoReflect.Descriptor instead.
+func (*ConversationHistoryRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ConversationHistoryRequestMessage) GetConversationId() uint64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *ConversationHistoryRequestMessage) GetBeforeId() uint64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+func (x *ConversationHistoryRequestMessage) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ConversationHistoryResponseMessage struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId uint64                 `protobuf:"varint,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Messages       []*DirectMessage       `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversationHistoryResponseMessage) Reset() {
+	*x = ConversationHistoryResponseMessage{}
+	mi := &file_packets_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationHistoryResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationHistoryResponseMessage) ProtoMessage() {}
+
+func (x *ConversationHistoryResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationHistoryResponseMessage.ProtoReflect.Descriptor instead.
+func (*ConversationHistoryResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ConversationHistoryResponseMessage) GetConversationId() uint64 {
+	if x != nil {
+		return x.ConversationId
+	}
+	return 0
+}
+
+func (x *ConversationHistoryResponseMessage) GetMessages() []*DirectMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type MentionsRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MentionsRequestMessage) Reset() {
+	*x = MentionsRequestMessage{}
+	mi := &file_packets_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MentionsRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MentionsRequestMessage) ProtoMessage() {}
+
+func (x *MentionsRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MentionsRequestMessage.ProtoReflect.Descriptor instead.
+func (*MentionsRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{51}
+}
+
+type MentionsResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Mentions      []*MentionMessage      `protobuf:"bytes,1,rep,name=mentions,proto3" json:"mentions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MentionsResponseMessage) Reset() {
+	*x = MentionsResponseMessage{}
+	mi := &file_packets_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MentionsResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MentionsResponseMessage) ProtoMessage() {}
+
+func (x *MentionsResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MentionsResponseMessage.ProtoReflect.Descriptor instead.
+func (*MentionsResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *MentionsResponseMessage) GetMentions() []*MentionMessage {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+type UploadAttachmentRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequestMessage) Reset() {
+	*x = UploadAttachmentRequestMessage{}
+	mi := &file_packets_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequestMessage) ProtoMessage() {}
+
+func (x *UploadAttachmentRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequestMessage.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *UploadAttachmentRequestMessage) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadAttachmentRequestMessage) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type UpdateRoomRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Topic         string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoomRequestMessage) Reset() {
+	*x = UpdateRoomRequestMessage{}
+	mi := &file_packets_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomRequestMessage) ProtoMessage() {}
+
+func (x *UpdateRoomRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomRequestMessage.ProtoReflect.Descriptor instead.
+func (*UpdateRoomRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *UpdateRoomRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *UpdateRoomRequestMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *UpdateRoomRequestMessage) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type RenameRoomRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameRoomRequestMessage) Reset() {
+	*x = RenameRoomRequestMessage{}
+	mi := &file_packets_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameRoomRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameRoomRequestMessage) ProtoMessage() {}
+
+func (x *RenameRoomRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameRoomRequestMessage.ProtoReflect.Descriptor instead.
+func (*RenameRoomRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RenameRoomRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RenameRoomRequestMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteRoomRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRoomRequestMessage) Reset() {
+	*x = DeleteRoomRequestMessage{}
+	mi := &file_packets_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRoomRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRoomRequestMessage) ProtoMessage() {}
+
+func (x *DeleteRoomRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRoomRequestMessage.ProtoReflect.Descriptor instead.
+func (*DeleteRoomRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *DeleteRoomRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type SetRoomVisibilityRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Visibility    RoomVisibility         `protobuf:"varint,2,opt,name=visibility,proto3,enum=packets.RoomVisibility" json:"visibility,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRoomVisibilityRequestMessage) Reset() {
+	*x = SetRoomVisibilityRequestMessage{}
+	mi := &file_packets_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRoomVisibilityRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRoomVisibilityRequestMessage) ProtoMessage() {}
+
+func (x *SetRoomVisibilityRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRoomVisibilityRequestMessage.ProtoReflect.Descriptor instead.
+func (*SetRoomVisibilityRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *SetRoomVisibilityRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *SetRoomVisibilityRequestMessage) GetVisibility() RoomVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return RoomVisibility_ROOM_PUBLIC
+}
+
+type InviteToRoomRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteToRoomRequestMessage) Reset() {
+	*x = InviteToRoomRequestMessage{}
+	mi := &file_packets_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteToRoomRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteToRoomRequestMessage) ProtoMessage() {}
+
+func (x *InviteToRoomRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteToRoomRequestMessage.ProtoReflect.Descriptor instead.
+func (*InviteToRoomRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *InviteToRoomRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *InviteToRoomRequestMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+type RoomInvitesRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomInvitesRequestMessage) Reset() {
+	*x = RoomInvitesRequestMessage{}
+	mi := &file_packets_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomInvitesRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomInvitesRequestMessage) ProtoMessage() {}
+
+func (x *RoomInvitesRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomInvitesRequestMessage.ProtoReflect.Descriptor instead.
+func (*RoomInvitesRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{59}
+}
+
+type RoomInvitesResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Invites       []*RoomInviteMessage   `protobuf:"bytes,1,rep,name=invites,proto3" json:"invites,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomInvitesResponseMessage) Reset() {
+	*x = RoomInvitesResponseMessage{}
+	mi := &file_packets_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomInvitesResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomInvitesResponseMessage) ProtoMessage() {}
+
+func (x *RoomInvitesResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomInvitesResponseMessage.ProtoReflect.Descriptor instead.
+func (*RoomInvitesResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *RoomInvitesResponseMessage) GetInvites() []*RoomInviteMessage {
+	if x != nil {
+		return x.Invites
+	}
+	return nil
+}
+
+type RespondToInviteRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Accept        bool                   `protobuf:"varint,2,opt,name=accept,proto3" json:"accept,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RespondToInviteRequestMessage) Reset() {
+	*x = RespondToInviteRequestMessage{}
+	mi := &file_packets_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RespondToInviteRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RespondToInviteRequestMessage) ProtoMessage() {}
+
+func (x *RespondToInviteRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RespondToInviteRequestMessage.ProtoReflect.Descriptor instead.
+func (*RespondToInviteRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *RespondToInviteRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *RespondToInviteRequestMessage) GetAccept() bool {
+	if x != nil {
+		return x.Accept
+	}
+	return false
+}
+
+type InviteCodeUseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	UsedAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=used_at,json=usedAt,proto3" json:"used_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteCodeUseMessage) Reset() {
+	*x = InviteCodeUseMessage{}
+	mi := &file_packets_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteCodeUseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteCodeUseMessage) ProtoMessage() {}
+
+func (x *InviteCodeUseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteCodeUseMessage.ProtoReflect.Descriptor instead.
+func (*InviteCodeUseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *InviteCodeUseMessage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *InviteCodeUseMessage) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *InviteCodeUseMessage) GetUsedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UsedAt
+	}
+	return nil
+}
+
+// max_uses and expires_at are unset when the code has no such limit
+type InviteCodeMessage struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Code          string                  `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	RoomId        uint64                  `protobuf:"varint,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Role          RoomRole                `protobuf:"varint,3,opt,name=role,proto3,enum=packets.RoomRole" json:"role,omitempty"`
+	MaxUses       uint32                  `protobuf:"varint,4,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	Uses          uint32                  `protobuf:"varint,5,opt,name=uses,proto3" json:"uses,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp  `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Revoked       bool                    `protobuf:"varint,7,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	CreatorId     string                  `protobuf:"bytes,8,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp  `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Redemptions   []*InviteCodeUseMessage `protobuf:"bytes,10,rep,name=redemptions,proto3" json:"redemptions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteCodeMessage) Reset() {
+	*x = InviteCodeMessage{}
+	mi := &file_packets_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteCodeMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteCodeMessage) ProtoMessage() {}
+
+func (x *InviteCodeMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteCodeMessage.ProtoReflect.Descriptor instead.
+func (*InviteCodeMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *InviteCodeMessage) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *InviteCodeMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *InviteCodeMessage) GetRole() RoomRole {
+	if x != nil {
+		return x.Role
+	}
+	return RoomRole_ROOM_ROLE_MEMBER
+}
+
+func (x *InviteCodeMessage) GetMaxUses() uint32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *InviteCodeMessage) GetUses() uint32 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+func (x *InviteCodeMessage) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *InviteCodeMessage) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *InviteCodeMessage) GetCreatorId() string {
+	if x != nil {
+		return x.CreatorId
+	}
+	return ""
+}
+
+func (x *InviteCodeMessage) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *InviteCodeMessage) GetRedemptions() []*InviteCodeUseMessage {
+	if x != nil {
+		return x.Redemptions
+	}
+	return nil
+}
+
+type CreateInviteCodeRequestMessage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RoomId           uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Role             RoomRole               `protobuf:"varint,2,opt,name=role,proto3,enum=packets.RoomRole" json:"role,omitempty"`
+	MaxUses          uint32                 `protobuf:"varint,3,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	ExpiresInSeconds uint32                 `protobuf:"varint,4,opt,name=expires_in_seconds,json=expiresInSeconds,proto3" json:"expires_in_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateInviteCodeRequestMessage) Reset() {
+	*x = CreateInviteCodeRequestMessage{}
+	mi := &file_packets_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateInviteCodeRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateInviteCodeRequestMessage) ProtoMessage() {}
+
+func (x *CreateInviteCodeRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateInviteCodeRequestMessage.ProtoReflect.Descriptor instead.
+func (*CreateInviteCodeRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *CreateInviteCodeRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *CreateInviteCodeRequestMessage) GetRole() RoomRole {
+	if x != nil {
+		return x.Role
+	}
+	return RoomRole_ROOM_ROLE_MEMBER
+}
+
+func (x *CreateInviteCodeRequestMessage) GetMaxUses() uint32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CreateInviteCodeRequestMessage) GetExpiresInSeconds() uint32 {
+	if x != nil {
+		return x.ExpiresInSeconds
+	}
+	return 0
+}
+
+type InviteCodesRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        uint64                 `protobuf:"varint,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteCodesRequestMessage) Reset() {
+	*x = InviteCodesRequestMessage{}
+	mi := &file_packets_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteCodesRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteCodesRequestMessage) ProtoMessage() {}
+
+func (x *InviteCodesRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteCodesRequestMessage.ProtoReflect.Descriptor instead.
+func (*InviteCodesRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *InviteCodesRequestMessage) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+type InviteCodesResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Codes         []*InviteCodeMessage   `protobuf:"bytes,1,rep,name=codes,proto3" json:"codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteCodesResponseMessage) Reset() {
+	*x = InviteCodesResponseMessage{}
+	mi := &file_packets_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteCodesResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteCodesResponseMessage) ProtoMessage() {}
+
+func (x *InviteCodesResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteCodesResponseMessage.ProtoReflect.Descriptor instead.
+func (*InviteCodesResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *InviteCodesResponseMessage) GetCodes() []*InviteCodeMessage {
+	if x != nil {
+		return x.Codes
+	}
+	return nil
+}
+
+type RevokeInviteCodeRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeInviteCodeRequestMessage) Reset() {
+	*x = RevokeInviteCodeRequestMessage{}
+	mi := &file_packets_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeInviteCodeRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeInviteCodeRequestMessage) ProtoMessage() {}
+
+func (x *RevokeInviteCodeRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeInviteCodeRequestMessage.ProtoReflect.Descriptor instead.
+func (*RevokeInviteCodeRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *RevokeInviteCodeRequestMessage) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type RedeemInviteCodeRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RedeemInviteCodeRequestMessage) Reset() {
+	*x = RedeemInviteCodeRequestMessage{}
+	mi := &file_packets_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RedeemInviteCodeRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemInviteCodeRequestMessage) ProtoMessage() {}
+
+func (x *RedeemInviteCodeRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemInviteCodeRequestMessage.ProtoReflect.Descriptor instead.
+func (*RedeemInviteCodeRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *RedeemInviteCodeRequestMessage) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type InviteCodeRedeemedMessage struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Room          *NewRoomResponseMessage `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Role          RoomRole                `protobuf:"varint,2,opt,name=role,proto3,enum=packets.RoomRole" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteCodeRedeemedMessage) Reset() {
+	*x = InviteCodeRedeemedMessage{}
+	mi := &file_packets_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteCodeRedeemedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteCodeRedeemedMessage) ProtoMessage() {}
+
+func (x *InviteCodeRedeemedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteCodeRedeemedMessage.ProtoReflect.Descriptor instead.
+func (*InviteCodeRedeemedMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *InviteCodeRedeemedMessage) GetRoom() *NewRoomResponseMessage {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *InviteCodeRedeemedMessage) GetRole() RoomRole {
+	if x != nil {
+		return x.Role
+	}
+	return RoomRole_ROOM_ROLE_MEMBER
+}
+
+type ExportDataRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDataRequestMessage) Reset() {
+	*x = ExportDataRequestMessage{}
+	mi := &file_packets_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDataRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDataRequestMessage) ProtoMessage() {}
+
+func (x *ExportDataRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDataRequestMessage.ProtoReflect.Descriptor instead.
+func (*ExportDataRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{70}
+}
+
+type DeleteAccountRequestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Password      string                 `protobuf:"bytes,1,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequestMessage) Reset() {
+	*x = DeleteAccountRequestMessage{}
+	mi := &file_packets_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequestMessage) ProtoMessage() {}
+
+func (x *DeleteAccountRequestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequestMessage.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequestMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *DeleteAccountRequestMessage) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type OkResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OkResponseMessage) Reset() {
+	*x = OkResponseMessage{}
+	mi := &file_packets_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OkResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OkResponseMessage) ProtoMessage() {}
+
+func (x *OkResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OkResponseMessage.ProtoReflect.Descriptor instead.
+func (*OkResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{72}
+}
+
+type DenyResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DenyResponseMessage) Reset() {
+	*x = DenyResponseMessage{}
+	mi := &file_packets_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DenyResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DenyResponseMessage) ProtoMessage() {}
+
+func (x *DenyResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DenyResponseMessage.ProtoReflect.Descriptor instead.
+func (*DenyResponseMessage) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *DenyResponseMessage) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Packet struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	SenderId uint64                 `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	RoomId   uint64                 `protobuf:"varint,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	// Position of the packet in the room's event log, zero for packets that aren't logged
+	Seq uint64 `protobuf:"varint,27,opt,name=seq,proto3" json:"seq,omitempty"`
+	// Types that are valid to be assigned to Msg:
+	//
+	//	*Packet_Chat
+	//	*Packet_Id
+	//	*Packet_Register
+	//	*Packet_Unregister
+	//	*Packet_OkResponse
+	//	*Packet_DenyResponse
+	//	*Packet_JoinRoom
+	//	*Packet_LeaveRoom
+	//	*Packet_DirectMessage
+	//	*Packet_ChatSent
+	//	*Packet_EditChat
+	//	*Packet_DeleteChat
+	//	*Packet_AddReaction
+	//	*Packet_RemoveReaction
+	//	*Packet_Reactions
+	//	*Packet_ThreadRequest
+	//	*Packet_Thread
+	//	*Packet_ThreadUpdated
+	//	*Packet_Typing
+	//	*Packet_MarkRead
+	//	*Packet_ReadPosition
+	//	*Packet_Presence
+	//	*Packet_SetPresence
+	//	*Packet_Mention
+	//	*Packet_MissedPackets
+	//	*Packet_SlowMode
+	//	*Packet_MessageUpdated
+	//	*Packet_System
+	//	*Packet_RoomInvite
+	//	*Packet_RoomUpdated
+	//	*Packet_RoomDeleted
+	Msg           isPacket_Msg `protobuf_oneof:"msg"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Packet) Reset() {
+	*x = Packet{}
+	mi := &file_packets_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Packet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Packet) ProtoMessage() {}
+
+func (x *Packet) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Packet.ProtoReflect.Descriptor instead.
+func (*Packet) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *Packet) GetSenderId() uint64 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *Packet) GetRoomId() uint64 {
+	if x != nil {
+		return x.RoomId
+	}
+	return 0
+}
+
+func (x *Packet) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Packet) GetMsg() isPacket_Msg {
+	if x != nil {
+		return x.Msg
+	}
+	return nil
+}
+
+func (x *Packet) GetChat() *ChatMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Chat); ok {
 			return x.Chat
 		}
 	}
 	return nil
 }
 
-func (x *Packet) GetId() *IdMessage {
+func (x *Packet) GetId() *IdMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Id); ok {
+			return x.Id
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetRegister() *RegisterMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Register); ok {
+			return x.Register
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetUnregister() *UnregisterMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Unregister); ok {
+			return x.Unregister
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetOkResponse() *OkResponseMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_OkResponse); ok {
+			return x.OkResponse
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetDenyResponse() *DenyResponseMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_DenyResponse); ok {
+			return x.DenyResponse
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetJoinRoom() *JoinRoomMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_JoinRoom); ok {
+			return x.JoinRoom
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetLeaveRoom() *LeaveRoomMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_LeaveRoom); ok {
+			return x.LeaveRoom
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetDirectMessage() *DirectMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_DirectMessage); ok {
+			return x.DirectMessage
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetChatSent() *ChatSentMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_ChatSent); ok {
+			return x.ChatSent
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetEditChat() *EditChatMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_EditChat); ok {
+			return x.EditChat
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetDeleteChat() *DeleteChatMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_DeleteChat); ok {
+			return x.DeleteChat
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetAddReaction() *AddReactionMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_AddReaction); ok {
+			return x.AddReaction
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetRemoveReaction() *RemoveReactionMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_RemoveReaction); ok {
+			return x.RemoveReaction
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetReactions() *ReactionsMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Reactions); ok {
+			return x.Reactions
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetThreadRequest() *ThreadRequestMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_ThreadRequest); ok {
+			return x.ThreadRequest
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetThread() *ThreadMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Thread); ok {
+			return x.Thread
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetThreadUpdated() *ThreadUpdatedMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_ThreadUpdated); ok {
+			return x.ThreadUpdated
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetTyping() *TypingMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Typing); ok {
+			return x.Typing
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetMarkRead() *MarkReadMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_MarkRead); ok {
+			return x.MarkRead
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetReadPosition() *ReadPositionMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_ReadPosition); ok {
+			return x.ReadPosition
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetPresence() *PresenceMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Presence); ok {
+			return x.Presence
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetSetPresence() *SetPresenceMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_SetPresence); ok {
+			return x.SetPresence
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetMention() *MentionMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_Mention); ok {
+			return x.Mention
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetMissedPackets() *MissedPacketsMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_MissedPackets); ok {
+			return x.MissedPackets
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetSlowMode() *SlowModeMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_SlowMode); ok {
+			return x.SlowMode
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetMessageUpdated() *MessageUpdatedMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_MessageUpdated); ok {
+			return x.MessageUpdated
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetSystem() *SystemMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_System); ok {
+			return x.System
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetRoomInvite() *RoomInviteMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_RoomInvite); ok {
+			return x.RoomInvite
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetRoomUpdated() *RoomUpdatedMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_RoomUpdated); ok {
+			return x.RoomUpdated
+		}
+	}
+	return nil
+}
+
+func (x *Packet) GetRoomDeleted() *RoomDeletedMessage {
+	if x != nil {
+		if x, ok := x.Msg.(*Packet_RoomDeleted); ok {
+			return x.RoomDeleted
+		}
+	}
+	return nil
+}
+
+type isPacket_Msg interface {
+	isPacket_Msg()
+}
+
+type Packet_Chat struct {
+	Chat *ChatMessage `protobuf:"bytes,3,opt,name=chat,proto3,oneof"`
+}
+
+type Packet_Id struct {
+	Id *IdMessage `protobuf:"bytes,4,opt,name=id,proto3,oneof"`
+}
+
+type Packet_Register struct {
+	Register *RegisterMessage `protobuf:"bytes,5,opt,name=register,proto3,oneof"`
+}
+
+type Packet_Unregister struct {
+	Unregister *UnregisterMessage `protobuf:"bytes,6,opt,name=unregister,proto3,oneof"`
+}
+
+type Packet_OkResponse struct {
+	OkResponse *OkResponseMessage `protobuf:"bytes,7,opt,name=ok_response,json=okResponse,proto3,oneof"`
+}
+
+type Packet_DenyResponse struct {
+	DenyResponse *DenyResponseMessage `protobuf:"bytes,8,opt,name=deny_response,json=denyResponse,proto3,oneof"`
+}
+
+type Packet_JoinRoom struct {
+	JoinRoom *JoinRoomMessage `protobuf:"bytes,9,opt,name=join_room,json=joinRoom,proto3,oneof"`
+}
+
+type Packet_LeaveRoom struct {
+	LeaveRoom *LeaveRoomMessage `protobuf:"bytes,10,opt,name=leave_room,json=leaveRoom,proto3,oneof"`
+}
+
+type Packet_DirectMessage struct {
+	DirectMessage *DirectMessage `protobuf:"bytes,11,opt,name=direct_message,json=directMessage,proto3,oneof"`
+}
+
+type Packet_ChatSent struct {
+	ChatSent *ChatSentMessage `protobuf:"bytes,12,opt,name=chat_sent,json=chatSent,proto3,oneof"`
+}
+
+type Packet_EditChat struct {
+	EditChat *EditChatMessage `protobuf:"bytes,13,opt,name=edit_chat,json=editChat,proto3,oneof"`
+}
+
+type Packet_DeleteChat struct {
+	DeleteChat *DeleteChatMessage `protobuf:"bytes,14,opt,name=delete_chat,json=deleteChat,proto3,oneof"`
+}
+
+type Packet_AddReaction struct {
+	AddReaction *AddReactionMessage `protobuf:"bytes,15,opt,name=add_reaction,json=addReaction,proto3,oneof"`
+}
+
+type Packet_RemoveReaction struct {
+	RemoveReaction *RemoveReactionMessage `protobuf:"bytes,16,opt,name=remove_reaction,json=removeReaction,proto3,oneof"`
+}
+
+type Packet_Reactions struct {
+	Reactions *ReactionsMessage `protobuf:"bytes,17,opt,name=reactions,proto3,oneof"`
+}
+
+type Packet_ThreadRequest struct {
+	ThreadRequest *ThreadRequestMessage `protobuf:"bytes,18,opt,name=thread_request,json=threadRequest,proto3,oneof"`
+}
+
+type Packet_Thread struct {
+	Thread *ThreadMessage `protobuf:"bytes,19,opt,name=thread,proto3,oneof"`
+}
+
+type Packet_ThreadUpdated struct {
+	ThreadUpdated *ThreadUpdatedMessage `protobuf:"bytes,20,opt,name=thread_updated,json=threadUpdated,proto3,oneof"`
+}
+
+type Packet_Typing struct {
+	Typing *TypingMessage `protobuf:"bytes,21,opt,name=typing,proto3,oneof"`
+}
+
+type Packet_MarkRead struct {
+	MarkRead *MarkReadMessage `protobuf:"bytes,22,opt,name=mark_read,json=markRead,proto3,oneof"`
+}
+
+type Packet_ReadPosition struct {
+	ReadPosition *ReadPositionMessage `protobuf:"bytes,23,opt,name=read_position,json=readPosition,proto3,oneof"`
+}
+
+type Packet_Presence struct {
+	Presence *PresenceMessage `protobuf:"bytes,24,opt,name=presence,proto3,oneof"`
+}
+
+type Packet_SetPresence struct {
+	SetPresence *SetPresenceMessage `protobuf:"bytes,25,opt,name=set_presence,json=setPresence,proto3,oneof"`
+}
+
+type Packet_Mention struct {
+	Mention *MentionMessage `protobuf:"bytes,26,opt,name=mention,proto3,oneof"`
+}
+
+type Packet_MissedPackets struct {
+	// Packets meant for the client were dropped because it didn't keep up. It can
+	// resync each room by joining it again with the last seq it saw
+	MissedPackets *MissedPacketsMessage `protobuf:"bytes,28,opt,name=missed_packets,json=missedPackets,proto3,oneof"`
+}
+
+type Packet_SlowMode struct {
+	// Sent by moderators to change how long members wait between messages, and to
+	// the room when it changes. Zero turns slow mode off
+	SlowMode *SlowModeMessage `protobuf:"bytes,29,opt,name=slow_mode,json=slowMode,proto3,oneof"`
+}
+
+type Packet_MessageUpdated struct {
+	// Link previews of a message were fetched after it was sent, or changed with an edit
+	MessageUpdated *MessageUpdatedMessage `protobuf:"bytes,30,opt,name=message_updated,json=messageUpdated,proto3,oneof"`
+}
+
+type Packet_System struct {
+	// Notices from the server. Replies to a slash command only go to whoever ran
+	// it, what commands change in a room is announced to everyone in it
+	System *SystemMessage `protobuf:"bytes,31,opt,name=system,proto3,oneof"`
+}
+
+type Packet_RoomInvite struct {
+	RoomInvite *RoomInviteMessage `protobuf:"bytes,32,opt,name=room_invite,json=roomInvite,proto3,oneof"`
+}
+
+type Packet_RoomUpdated struct {
+	// Sent by moderators to change the topic and description of the room, and to
+	// the room when its details change. Only the owner can rename the room or change
+	// who can join it, over HTTP
+	RoomUpdated *RoomUpdatedMessage `protobuf:"bytes,33,opt,name=room_updated,json=roomUpdated,proto3,oneof"`
+}
+
+type Packet_RoomDeleted struct {
+	// The room was deleted, clients in it are taken out of it and stay in their other rooms
+	RoomDeleted *RoomDeletedMessage `protobuf:"bytes,34,opt,name=room_deleted,json=roomDeleted,proto3,oneof"`
+}
+
+func (*Packet_Chat) isPacket_Msg() {}
+
+func (*Packet_Id) isPacket_Msg() {}
+
+func (*Packet_Register) isPacket_Msg() {}
+
+func (*Packet_Unregister) isPacket_Msg() {}
+
+func (*Packet_OkResponse) isPacket_Msg() {}
+
+func (*Packet_DenyResponse) isPacket_Msg() {}
+
+func (*Packet_JoinRoom) isPacket_Msg() {}
+
+func (*Packet_LeaveRoom) isPacket_Msg() {}
+
+func (*Packet_DirectMessage) isPacket_Msg() {}
+
+func (*Packet_ChatSent) isPacket_Msg() {}
+
+func (*Packet_EditChat) isPacket_Msg() {}
+
+func (*Packet_DeleteChat) isPacket_Msg() {}
+
+func (*Packet_AddReaction) isPacket_Msg() {}
+
+func (*Packet_RemoveReaction) isPacket_Msg() {}
+
+func (*Packet_Reactions) isPacket_Msg() {}
+
+func (*Packet_ThreadRequest) isPacket_Msg() {}
+
+func (*Packet_Thread) isPacket_Msg() {}
+
+func (*Packet_ThreadUpdated) isPacket_Msg() {}
+
+func (*Packet_Typing) isPacket_Msg() {}
+
+func (*Packet_MarkRead) isPacket_Msg() {}
+
+func (*Packet_ReadPosition) isPacket_Msg() {}
+
+func (*Packet_Presence) isPacket_Msg() {}
+
+func (*Packet_SetPresence) isPacket_Msg() {}
+
+func (*Packet_Mention) isPacket_Msg() {}
+
+func (*Packet_MissedPackets) isPacket_Msg() {}
+
+func (*Packet_SlowMode) isPacket_Msg() {}
+
+func (*Packet_MessageUpdated) isPacket_Msg() {}
+
+func (*Packet_System) isPacket_Msg() {}
+
+func (*Packet_RoomInvite) isPacket_Msg() {}
+
+func (*Packet_RoomUpdated) isPacket_Msg() {}
+
+func (*Packet_RoomDeleted) isPacket_Msg() {}
+
+type Message struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Type:
+	//
+	//	*Message_Jwt
+	//	*Message_Login
+	//	*Message_Register
+	//	*Message_Refresh
+	//	*Message_Logout
+	//	*Message_NewRoom
+	//	*Message_RoomsRequest
+	//	*Message_RoomsResponse
+	//	*Message_OkResponse
+	//	*Message_DenyResponse
+	//	*Message_ProfileRequest
+	//	*Message_Profile
+	//	*Message_UpdateProfile
+	//	*Message_ExportData
+	//	*Message_DeleteAccount
+	//	*Message_ConversationsRequest
+	//	*Message_ConversationsResponse
+	//	*Message_ConversationHistoryRequest
+	//	*Message_ConversationHistoryResponse
+	//	*Message_MentionsRequest
+	//	*Message_MentionsResponse
+	//	*Message_UploadAttachment
+	//	*Message_Attachment
+	//	*Message_UpdateRoom
+	//	*Message_RenameRoom
+	//	*Message_DeleteRoom
+	//	*Message_SetRoomVisibility
+	//	*Message_InviteToRoom
+	//	*Message_RoomInvitesRequest
+	//	*Message_RoomInvitesResponse
+	//	*Message_RespondToInvite
+	//	*Message_CreateInviteCode
+	//	*Message_InviteCode
+	//	*Message_InviteCodesRequest
+	//	*Message_InviteCodesResponse
+	//	*Message_RevokeInviteCode
+	//	*Message_RedeemInviteCode
+	//	*Message_InviteCodeRedeemed
+	Type          isMessage_Type `protobuf_oneof:"type"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_packets_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_packets_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_packets_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *Message) GetType() isMessage_Type {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *Message) GetJwt() *JwtMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Jwt); ok {
+			return x.Jwt
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetLogin() *LoginRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Login); ok {
+			return x.Login
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRegister() *RegisterRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Register); ok {
+			return x.Register
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRefresh() *RefreshRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Refresh); ok {
+			return x.Refresh
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetLogout() *LogoutRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Logout); ok {
+			return x.Logout
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetNewRoom() *NewRoomRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_NewRoom); ok {
+			return x.NewRoom
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRoomsRequest() *RoomsRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_RoomsRequest); ok {
+			return x.RoomsRequest
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRoomsResponse() *RoomsResponseMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_RoomsResponse); ok {
+			return x.RoomsResponse
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetOkResponse() *OkResponseMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_OkResponse); ok {
+			return x.OkResponse
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetDenyResponse() *DenyResponseMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_DenyResponse); ok {
+			return x.DenyResponse
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetProfileRequest() *ProfileRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_ProfileRequest); ok {
+			return x.ProfileRequest
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetProfile() *ProfileMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Profile); ok {
+			return x.Profile
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetUpdateProfile() *UpdateProfileRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_UpdateProfile); ok {
+			return x.UpdateProfile
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetExportData() *ExportDataRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_ExportData); ok {
+			return x.ExportData
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetDeleteAccount() *DeleteAccountRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_DeleteAccount); ok {
+			return x.DeleteAccount
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetConversationsRequest() *ConversationsRequestMessage {
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_Id); ok {
-			return x.Id
+		if x, ok := x.Type.(*Message_ConversationsRequest); ok {
+			return x.ConversationsRequest
 		}
 	}
 	return nil
 }
 
-func (x *Packet) GetRegister() *RegisterMessage {
+func (x *Message) GetConversationsResponse() *ConversationsResponseMessage {
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_Register); ok {
-			return x.Register
+		if x, ok := x.Type.(*Message_ConversationsResponse); ok {
+			return x.ConversationsResponse
 		}
 	}
 	return nil
 }
 
-func (x *Packet) GetUnregister() *UnregisterMessage {
+func (x *Message) GetConversationHistoryRequest() *ConversationHistoryRequestMessage {
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_Unregister); ok {
-			return x.Unregister
+		if x, ok := x.Type.(*Message_ConversationHistoryRequest); ok {
+			return x.ConversationHistoryRequest
 		}
 	}
 	return nil
 }
 
-func (x *Packet) GetOkResponse() *OkResponseMessage {
+func (x *Message) GetConversationHistoryResponse() *ConversationHistoryResponseMessage {
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_OkResponse); ok {
-			return x.OkResponse
+		if x, ok := x.Type.(*Message_ConversationHistoryResponse); ok {
+			return x.ConversationHistoryResponse
 		}
 	}
 	return nil
 }
 
-func (x *Packet) GetDenyResponse() *DenyResponseMessage {
+func (x *Message) GetMentionsRequest() *MentionsRequestMessage {
 	if x != nil {
-		if x, ok := x.Msg.(*Packet_DenyResponse); ok {
-			return x.DenyResponse
+		if x, ok := x.Type.(*Message_MentionsRequest); ok {
+			return x.MentionsRequest
 		}
 	}
 	return nil
 }
 
-type isPacket_Msg interface {
-	isPacket_Msg()
-}
-
-type Packet_Chat struct {
-	Chat *ChatMessage `protobuf:"bytes,3,opt,name=chat,proto3,oneof"`
-}
-
-type Packet_Id struct {
-	Id *IdMessage `protobuf:"bytes,4,opt,name=id,proto3,oneof"`
-}
-
-type Packet_Register struct {
-	Register *RegisterMessage `protobuf:"bytes,5,opt,name=register,proto3,oneof"`
-}
-
-type Packet_Unregister struct {
-	Unregister *UnregisterMessage `protobuf:"bytes,6,opt,name=unregister,proto3,oneof"`
-}
-
-type Packet_OkResponse struct {
-	OkResponse *OkResponseMessage `protobuf:"bytes,7,opt,name=ok_response,json=okResponse,proto3,oneof"`
+func (x *Message) GetMentionsResponse() *MentionsResponseMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_MentionsResponse); ok {
+			return x.MentionsResponse
+		}
+	}
+	return nil
 }
 
-type Packet_DenyResponse struct {
-	DenyResponse *DenyResponseMessage `protobuf:"bytes,8,opt,name=deny_response,json=denyResponse,proto3,oneof"`
+func (x *Message) GetUploadAttachment() *UploadAttachmentRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_UploadAttachment); ok {
+			return x.UploadAttachment
+		}
+	}
+	return nil
 }
 
-func (*Packet_Chat) isPacket_Msg() {}
-
-func (*Packet_Id) isPacket_Msg() {}
-
-func (*Packet_Register) isPacket_Msg() {}
-
-func (*Packet_Unregister) isPacket_Msg() {}
-
-func (*Packet_OkResponse) isPacket_Msg() {}
-
-func (*Packet_DenyResponse) isPacket_Msg() {}
-
-type Message struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Type:
-	//
-	//	*Message_Jwt
-	//	*Message_Login
-	//	*Message_Register
-	//	*Message_Refresh
-	//	*Message_Logout
-	//	*Message_NewRoom
-	//	*Message_RoomsRequest
-	//	*Message_RoomsResponse
-	//	*Message_OkResponse
-	//	*Message_DenyResponse
-	Type          isMessage_Type `protobuf_oneof:"type"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Message) GetAttachment() *AttachmentMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_Attachment); ok {
+			return x.Attachment
+		}
+	}
+	return nil
 }
 
-func (x *Message) Reset() {
-	*x = Message{}
-	mi := &file_packets_proto_msgTypes[17]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Message) GetUpdateRoom() *UpdateRoomRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_UpdateRoom); ok {
+			return x.UpdateRoom
+		}
+	}
+	return nil
 }
 
-func (x *Message) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Message) GetRenameRoom() *RenameRoomRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_RenameRoom); ok {
+			return x.RenameRoom
+		}
+	}
+	return nil
 }
 
-func (*Message) ProtoMessage() {}
-
-func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_packets_proto_msgTypes[17]
+func (x *Message) GetDeleteRoom() *DeleteRoomRequestMessage {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x, ok := x.Type.(*Message_DeleteRoom); ok {
+			return x.DeleteRoom
 		}
-		return ms
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use Message.ProtoReflect.Descriptor instead.
-func (*Message) Descriptor() ([]byte, []int) {
-	return file_packets_proto_rawDescGZIP(), []int{17}
+func (x *Message) GetSetRoomVisibility() *SetRoomVisibilityRequestMessage {
+	if x != nil {
+		if x, ok := x.Type.(*Message_SetRoomVisibility); ok {
+			return x.SetRoomVisibility
+		}
+	}
+	return nil
 }
 
-func (x *Message) GetType() isMessage_Type {
+func (x *Message) GetInviteToRoom() *InviteToRoomRequestMessage {
 	if x != nil {
-		return x.Type
+		if x, ok := x.Type.(*Message_InviteToRoom); ok {
+			return x.InviteToRoom
+		}
 	}
 	return nil
 }
 
-func (x *Message) GetJwt() *JwtMessage {
+func (x *Message) GetRoomInvitesRequest() *RoomInvitesRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_Jwt); ok {
-			return x.Jwt
+		if x, ok := x.Type.(*Message_RoomInvitesRequest); ok {
+			return x.RoomInvitesRequest
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetLogin() *LoginRequestMessage {
+func (x *Message) GetRoomInvitesResponse() *RoomInvitesResponseMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_Login); ok {
-			return x.Login
+		if x, ok := x.Type.(*Message_RoomInvitesResponse); ok {
+			return x.RoomInvitesResponse
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetRegister() *RegisterRequestMessage {
+func (x *Message) GetRespondToInvite() *RespondToInviteRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_Register); ok {
-			return x.Register
+		if x, ok := x.Type.(*Message_RespondToInvite); ok {
+			return x.RespondToInvite
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetRefresh() *RefreshRequestMessage {
+func (x *Message) GetCreateInviteCode() *CreateInviteCodeRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_Refresh); ok {
-			return x.Refresh
+		if x, ok := x.Type.(*Message_CreateInviteCode); ok {
+			return x.CreateInviteCode
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetLogout() *LogoutRequestMessage {
+func (x *Message) GetInviteCode() *InviteCodeMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_Logout); ok {
-			return x.Logout
+		if x, ok := x.Type.(*Message_InviteCode); ok {
+			return x.InviteCode
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetNewRoom() *NewRoomRequestMessage {
+func (x *Message) GetInviteCodesRequest() *InviteCodesRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_NewRoom); ok {
-			return x.NewRoom
+		if x, ok := x.Type.(*Message_InviteCodesRequest); ok {
+			return x.InviteCodesRequest
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetRoomsRequest() *RoomsRequestMessage {
+func (x *Message) GetInviteCodesResponse() *InviteCodesResponseMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_RoomsRequest); ok {
-			return x.RoomsRequest
+		if x, ok := x.Type.(*Message_InviteCodesResponse); ok {
+			return x.InviteCodesResponse
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetRoomsResponse() *RoomsResponseMessage {
+func (x *Message) GetRevokeInviteCode() *RevokeInviteCodeRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_RoomsResponse); ok {
-			return x.RoomsResponse
+		if x, ok := x.Type.(*Message_RevokeInviteCode); ok {
+			return x.RevokeInviteCode
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetOkResponse() *OkResponseMessage {
+func (x *Message) GetRedeemInviteCode() *RedeemInviteCodeRequestMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_OkResponse); ok {
-			return x.OkResponse
+		if x, ok := x.Type.(*Message_RedeemInviteCode); ok {
+			return x.RedeemInviteCode
 		}
 	}
 	return nil
 }
 
-func (x *Message) GetDenyResponse() *DenyResponseMessage {
+func (x *Message) GetInviteCodeRedeemed() *InviteCodeRedeemedMessage {
 	if x != nil {
-		if x, ok := x.Type.(*Message_DenyResponse); ok {
-			return x.DenyResponse
+		if x, ok := x.Type.(*Message_InviteCodeRedeemed); ok {
+			return x.InviteCodeRedeemed
 		}
 	}
 	return nil
@@ -1152,6 +5465,118 @@ type Message_DenyResponse struct {
 	DenyResponse *DenyResponseMessage `protobuf:"bytes,10,opt,name=deny_response,json=denyResponse,proto3,oneof"`
 }
 
+type Message_ProfileRequest struct {
+	ProfileRequest *ProfileRequestMessage `protobuf:"bytes,11,opt,name=profile_request,json=profileRequest,proto3,oneof"`
+}
+
+type Message_Profile struct {
+	Profile *ProfileMessage `protobuf:"bytes,12,opt,name=profile,proto3,oneof"`
+}
+
+type Message_UpdateProfile struct {
+	UpdateProfile *UpdateProfileRequestMessage `protobuf:"bytes,13,opt,name=update_profile,json=updateProfile,proto3,oneof"`
+}
+
+type Message_ExportData struct {
+	ExportData *ExportDataRequestMessage `protobuf:"bytes,14,opt,name=export_data,json=exportData,proto3,oneof"`
+}
+
+type Message_DeleteAccount struct {
+	DeleteAccount *DeleteAccountRequestMessage `protobuf:"bytes,15,opt,name=delete_account,json=deleteAccount,proto3,oneof"`
+}
+
+type Message_ConversationsRequest struct {
+	ConversationsRequest *ConversationsRequestMessage `protobuf:"bytes,16,opt,name=conversations_request,json=conversationsRequest,proto3,oneof"`
+}
+
+type Message_ConversationsResponse struct {
+	ConversationsResponse *ConversationsResponseMessage `protobuf:"bytes,17,opt,name=conversations_response,json=conversationsResponse,proto3,oneof"`
+}
+
+type Message_ConversationHistoryRequest struct {
+	ConversationHistoryRequest *ConversationHistoryRequestMessage `protobuf:"bytes,18,opt,name=conversation_history_request,json=conversationHistoryRequest,proto3,oneof"`
+}
+
+type Message_ConversationHistoryResponse struct {
+	ConversationHistoryResponse *ConversationHistoryResponseMessage `protobuf:"bytes,19,opt,name=conversation_history_response,json=conversationHistoryResponse,proto3,oneof"`
+}
+
+type Message_MentionsRequest struct {
+	MentionsRequest *MentionsRequestMessage `protobuf:"bytes,20,opt,name=mentions_request,json=mentionsRequest,proto3,oneof"`
+}
+
+type Message_MentionsResponse struct {
+	MentionsResponse *MentionsResponseMessage `protobuf:"bytes,21,opt,name=mentions_response,json=mentionsResponse,proto3,oneof"`
+}
+
+type Message_UploadAttachment struct {
+	UploadAttachment *UploadAttachmentRequestMessage `protobuf:"bytes,22,opt,name=upload_attachment,json=uploadAttachment,proto3,oneof"`
+}
+
+type Message_Attachment struct {
+	Attachment *AttachmentMessage `protobuf:"bytes,23,opt,name=attachment,proto3,oneof"`
+}
+
+type Message_UpdateRoom struct {
+	UpdateRoom *UpdateRoomRequestMessage `protobuf:"bytes,24,opt,name=update_room,json=updateRoom,proto3,oneof"`
+}
+
+type Message_RenameRoom struct {
+	RenameRoom *RenameRoomRequestMessage `protobuf:"bytes,25,opt,name=rename_room,json=renameRoom,proto3,oneof"`
+}
+
+type Message_DeleteRoom struct {
+	DeleteRoom *DeleteRoomRequestMessage `protobuf:"bytes,26,opt,name=delete_room,json=deleteRoom,proto3,oneof"`
+}
+
+type Message_SetRoomVisibility struct {
+	SetRoomVisibility *SetRoomVisibilityRequestMessage `protobuf:"bytes,27,opt,name=set_room_visibility,json=setRoomVisibility,proto3,oneof"`
+}
+
+type Message_InviteToRoom struct {
+	InviteToRoom *InviteToRoomRequestMessage `protobuf:"bytes,28,opt,name=invite_to_room,json=inviteToRoom,proto3,oneof"`
+}
+
+type Message_RoomInvitesRequest struct {
+	RoomInvitesRequest *RoomInvitesRequestMessage `protobuf:"bytes,29,opt,name=room_invites_request,json=roomInvitesRequest,proto3,oneof"`
+}
+
+type Message_RoomInvitesResponse struct {
+	RoomInvitesResponse *RoomInvitesResponseMessage `protobuf:"bytes,30,opt,name=room_invites_response,json=roomInvitesResponse,proto3,oneof"`
+}
+
+type Message_RespondToInvite struct {
+	RespondToInvite *RespondToInviteRequestMessage `protobuf:"bytes,31,opt,name=respond_to_invite,json=respondToInvite,proto3,oneof"`
+}
+
+type Message_CreateInviteCode struct {
+	CreateInviteCode *CreateInviteCodeRequestMessage `protobuf:"bytes,32,opt,name=create_invite_code,json=createInviteCode,proto3,oneof"`
+}
+
+type Message_InviteCode struct {
+	InviteCode *InviteCodeMessage `protobuf:"bytes,33,opt,name=invite_code,json=inviteCode,proto3,oneof"`
+}
+
+type Message_InviteCodesRequest struct {
+	InviteCodesRequest *InviteCodesRequestMessage `protobuf:"bytes,34,opt,name=invite_codes_request,json=inviteCodesRequest,proto3,oneof"`
+}
+
+type Message_InviteCodesResponse struct {
+	InviteCodesResponse *InviteCodesResponseMessage `protobuf:"bytes,35,opt,name=invite_codes_response,json=inviteCodesResponse,proto3,oneof"`
+}
+
+type Message_RevokeInviteCode struct {
+	RevokeInviteCode *RevokeInviteCodeRequestMessage `protobuf:"bytes,36,opt,name=revoke_invite_code,json=revokeInviteCode,proto3,oneof"`
+}
+
+type Message_RedeemInviteCode struct {
+	RedeemInviteCode *RedeemInviteCodeRequestMessage `protobuf:"bytes,37,opt,name=redeem_invite_code,json=redeemInviteCode,proto3,oneof"`
+}
+
+type Message_InviteCodeRedeemed struct {
+	InviteCodeRedeemed *InviteCodeRedeemedMessage `protobuf:"bytes,38,opt,name=invite_code_redeemed,json=inviteCodeRedeemed,proto3,oneof"`
+}
+
 func (*Message_Jwt) isMessage_Type() {}
 
 func (*Message_Login) isMessage_Type() {}
@@ -1172,28 +5597,246 @@ func (*Message_OkResponse) isMessage_Type() {}
 
 func (*Message_DenyResponse) isMessage_Type() {}
 
+func (*Message_ProfileRequest) isMessage_Type() {}
+
+func (*Message_Profile) isMessage_Type() {}
+
+func (*Message_UpdateProfile) isMessage_Type() {}
+
+func (*Message_ExportData) isMessage_Type() {}
+
+func (*Message_DeleteAccount) isMessage_Type() {}
+
+func (*Message_ConversationsRequest) isMessage_Type() {}
+
+func (*Message_ConversationsResponse) isMessage_Type() {}
+
+func (*Message_ConversationHistoryRequest) isMessage_Type() {}
+
+func (*Message_ConversationHistoryResponse) isMessage_Type() {}
+
+func (*Message_MentionsRequest) isMessage_Type() {}
+
+func (*Message_MentionsResponse) isMessage_Type() {}
+
+func (*Message_UploadAttachment) isMessage_Type() {}
+
+func (*Message_Attachment) isMessage_Type() {}
+
+func (*Message_UpdateRoom) isMessage_Type() {}
+
+func (*Message_RenameRoom) isMessage_Type() {}
+
+func (*Message_DeleteRoom) isMessage_Type() {}
+
+func (*Message_SetRoomVisibility) isMessage_Type() {}
+
+func (*Message_InviteToRoom) isMessage_Type() {}
+
+func (*Message_RoomInvitesRequest) isMessage_Type() {}
+
+func (*Message_RoomInvitesResponse) isMessage_Type() {}
+
+func (*Message_RespondToInvite) isMessage_Type() {}
+
+func (*Message_CreateInviteCode) isMessage_Type() {}
+
+func (*Message_InviteCode) isMessage_Type() {}
+
+func (*Message_InviteCodesRequest) isMessage_Type() {}
+
+func (*Message_InviteCodesResponse) isMessage_Type() {}
+
+func (*Message_RevokeInviteCode) isMessage_Type() {}
+
+func (*Message_RedeemInviteCode) isMessage_Type() {}
+
+func (*Message_InviteCodeRedeemed) isMessage_Type() {}
+
 var File_packets_proto protoreflect.FileDescriptor
 
 const file_packets_proto_rawDesc = "" +
 	"\n" +
-	"\rpackets.proto\x12\apackets\x1a\x1fgoogle/protobuf/timestamp.proto\"\x81\x01\n" +
+	"\rpackets.proto\x12\apackets\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb3\x04\n" +
 	"\vChatMessage\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12&\n" +
 	"\x0esenderUsername\x18\x02 \x01(\tR\x0esenderUsername\x12\x10\n" +
-	"\x03msg\x18\x03 \x01(\tR\x03msg\"k\n" +
+	"\x03msg\x18\x03 \x01(\tR\x03msg\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\x04R\x02id\x12$\n" +
+	"\x0esender_user_id\x18\x05 \x01(\tR\fsenderUserId\x127\n" +
+	"\tedited_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\x126\n" +
+	"\treactions\x18\a \x03(\v2\x18.packets.ReactionSummaryR\treactions\x12\x1b\n" +
+	"\tparent_id\x18\b \x01(\x04R\bparentId\x12\x1f\n" +
+	"\vreply_count\x18\t \x01(\rR\n" +
+	"replyCount\x12\x1f\n" +
+	"\vmention_ids\x18\n" +
+	" \x03(\tR\n" +
+	"mentionIds\x12\x1b\n" +
+	"\tclient_id\x18\v \x01(\tR\bclientId\x12<\n" +
+	"\vattachments\x18\f \x03(\v2\x1a.packets.AttachmentMessageR\vattachments\x127\n" +
+	"\bpreviews\x18\r \x03(\v2\x1b.packets.LinkPreviewMessageR\bpreviews\x12\x16\n" +
+	"\x06action\x18\x0e \x01(\bR\x06action\"\xd0\x01\n" +
+	"\x11AttachmentMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04mime\x18\x03 \x01(\tR\x04mime\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x04R\x04size\x12\x14\n" +
+	"\x05width\x18\x05 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\rR\x06height\x129\n" +
+	"\n" +
+	"thumbnails\x18\a \x03(\v2\x19.packets.ThumbnailMessageR\n" +
+	"thumbnails\"h\n" +
+	"\x10ThumbnailMessage\x12\x12\n" +
+	"\x04size\x18\x01 \x01(\rR\x04size\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\rR\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\rR\x06height\x12\x12\n" +
+	"\x04mime\x18\x04 \x01(\tR\x04mime\"\x98\x01\n" +
+	"\x12LinkPreviewMessage\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x04 \x01(\tR\bimageUrl\x12\x1b\n" +
+	"\tsite_name\x18\x05 \x01(\tR\bsiteName\"o\n" +
+	"\x15MessageUpdatedMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x127\n" +
+	"\bpreviews\x18\x02 \x03(\v2\x1b.packets.LinkPreviewMessageR\bpreviews\"\x87\x01\n" +
+	"\x0fChatSentMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x128\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1b\n" +
+	"\tclient_id\x18\x03 \x01(\tR\bclientId\"{\n" +
+	"\x0fEditChatMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x12\x10\n" +
+	"\x03msg\x18\x02 \x01(\tR\x03msg\x127\n" +
+	"\tedited_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\beditedAt\"2\n" +
+	"\x11DeleteChatMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\"X\n" +
+	"\x0fReactionSummary\x12\x14\n" +
+	"\x05emoji\x18\x01 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12\x19\n" +
+	"\buser_ids\x18\x03 \x03(\tR\auserIds\"I\n" +
+	"\x12AddReactionMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x12\x14\n" +
+	"\x05emoji\x18\x02 \x01(\tR\x05emoji\"L\n" +
+	"\x15RemoveReactionMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x12\x14\n" +
+	"\x05emoji\x18\x02 \x01(\tR\x05emoji\"i\n" +
+	"\x10ReactionsMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x126\n" +
+	"\treactions\x18\x02 \x03(\v2\x18.packets.ReactionSummaryR\treactions\"d\n" +
+	"\x14ThreadRequestMessage\x12\x1b\n" +
+	"\tparent_id\x18\x01 \x01(\x04R\bparentId\x12\x19\n" +
+	"\bafter_id\x18\x02 \x01(\x04R\aafterId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"m\n" +
+	"\rThreadMessage\x12,\n" +
+	"\x06parent\x18\x01 \x01(\v2\x14.packets.ChatMessageR\x06parent\x12.\n" +
+	"\areplies\x18\x02 \x03(\v2\x14.packets.ChatMessageR\areplies\"V\n" +
+	"\x14ThreadUpdatedMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\x12\x1f\n" +
+	"\vreply_count\x18\x02 \x01(\rR\n" +
+	"replyCount\"\\\n" +
+	"\rTypingMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06typing\x18\x03 \x01(\bR\x06typing\"0\n" +
+	"\x0fMarkReadMessage\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\x04R\tmessageId\"M\n" +
+	"\x13ReadPositionMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x04R\tmessageId\"\x94\x01\n" +
+	"\x0fPresenceMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12/\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x17.packets.PresenceStatusR\x06status\x127\n" +
+	"\tlast_seen\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\blastSeen\"E\n" +
+	"\x12SetPresenceMessage\x12/\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x17.packets.PresenceStatusR\x06status\"+\n" +
+	"\x0fSlowModeMessage\x12\x18\n" +
+	"\aseconds\x18\x01 \x01(\rR\aseconds\"#\n" +
+	"\rSystemMessage\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"\xd1\x01\n" +
+	"\x12RoomUpdatedMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"updater_id\x18\x05 \x01(\tR\tupdaterId\x127\n" +
+	"\n" +
+	"visibility\x18\x06 \x01(\x0e2\x17.packets.RoomVisibilityR\n" +
+	"visibility\"\x93\x01\n" +
+	"\x11RoomInviteMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x1b\n" +
+	"\troom_name\x18\x02 \x01(\tR\broomName\x12\x1d\n" +
+	"\n" +
+	"inviter_id\x18\x03 \x01(\tR\tinviterId\x12)\n" +
+	"\x10inviter_username\x18\x04 \x01(\tR\x0finviterUsername\"-\n" +
+	"\x12RoomDeletedMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\"G\n" +
+	"\x14MissedPacketsMessage\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\x12\x19\n" +
+	"\broom_ids\x18\x02 \x03(\x04R\aroomIds\"\x87\x02\n" +
+	"\x0eMentionMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\x04R\tmessageId\x12\x17\n" +
+	"\aroom_id\x18\x03 \x01(\x04R\x06roomId\x12\x1b\n" +
+	"\troom_name\x18\x04 \x01(\tR\broomName\x12\x1b\n" +
+	"\tsender_id\x18\x05 \x01(\tR\bsenderId\x12'\n" +
+	"\x0fsender_username\x18\x06 \x01(\tR\x0esenderUsername\x12\x10\n" +
+	"\x03msg\x18\a \x01(\tR\x03msg\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"k\n" +
 	"\tIdMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
 	"\busername\x18\x02 \x01(\tR\busername\x122\n" +
-	"\x04room\x18\x03 \x01(\v2\x1e.packets.RoomRegisteredMessageR\x04room\"=\n" +
+	"\x04room\x18\x03 \x01(\v2\x1e.packets.RoomRegisteredMessageR\x04room\"p\n" +
 	"\x0fRegisterMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
-	"\busername\x18\x02 \x01(\tR\busername\"#\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x121\n" +
+	"\aprofile\x18\x03 \x01(\v2\x17.packets.ProfileMessageR\aprofile\"#\n" +
 	"\x11UnregisterMessage\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x04R\x02id\"U\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xb4\x02\n" +
 	"\x15RoomRegisteredMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x18\n" +
 	"\aownerId\x18\x02 \x01(\tR\aownerId\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\"T\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x19\n" +
+	"\blast_seq\x18\x04 \x01(\x04R\alastSeq\x12*\n" +
+	"\x11slow_mode_seconds\x18\x05 \x01(\rR\x0fslowModeSeconds\x12\x14\n" +
+	"\x05topic\x18\x06 \x01(\tR\x05topic\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x127\n" +
+	"\n" +
+	"visibility\x18\b \x01(\x0e2\x17.packets.RoomVisibilityR\n" +
+	"visibility\x12%\n" +
+	"\x04role\x18\t \x01(\x0e2\x11.packets.RoomRoleR\x04role\"E\n" +
+	"\x0fJoinRoomMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x19\n" +
+	"\blast_seq\x18\x02 \x01(\x04R\alastSeq\"+\n" +
+	"\x10LeaveRoomMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\"\xff\x01\n" +
+	"\rDirectMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\x04R\x0econversationId\x12#\n" +
+	"\rrecipient_ids\x18\x03 \x03(\tR\frecipientIds\x12\x1b\n" +
+	"\tsender_id\x18\x04 \x01(\tR\bsenderId\x12'\n" +
+	"\x0fsender_username\x18\x05 \x01(\tR\x0esenderUsername\x12\x10\n" +
+	"\x03msg\x18\x06 \x01(\tR\x03msg\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xaf\x01\n" +
+	"\x0eProfileMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x10\n" +
+	"\x03bio\x18\x03 \x01(\tR\x03bio\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"has_avatar\x18\x05 \x01(\bR\thasAvatar\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x03R\aversion\"T\n" +
 	"\n" +
 	"JwtMessage\x12!\n" +
 	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12#\n" +
@@ -1205,23 +5848,131 @@ const file_packets_proto_rawDesc = "" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x17\n" +
 	"\x15RefreshRequestMessage\"\x16\n" +
-	"\x14LogoutRequestMessage\"C\n" +
+	"\x14LogoutRequestMessage\"|\n" +
 	"\x15NewRoomRequestMessage\x12\x16\n" +
 	"\x06roomId\x18\x01 \x01(\x04R\x06roomId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"^\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x127\n" +
+	"\n" +
+	"visibility\x18\x03 \x01(\x0e2\x17.packets.RoomVisibilityR\n" +
+	"visibility\"\x94\x02\n" +
 	"\x16NewRoomResponseMessage\x12\x16\n" +
 	"\x06roomId\x18\x01 \x01(\x04R\x06roomId\x12\x18\n" +
 	"\aownerId\x18\x02 \x01(\tR\aownerId\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\"\x15\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12!\n" +
+	"\funread_count\x18\x04 \x01(\rR\vunreadCount\x12 \n" +
+	"\flast_read_id\x18\x05 \x01(\x04R\n" +
+	"lastReadId\x12\x14\n" +
+	"\x05topic\x18\x06 \x01(\tR\x05topic\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x127\n" +
+	"\n" +
+	"visibility\x18\b \x01(\x0e2\x17.packets.RoomVisibilityR\n" +
+	"visibility\"\x15\n" +
 	"\x13RoomsRequestMessage\"M\n" +
 	"\x14RoomsResponseMessage\x125\n" +
-	"\x05rooms\x18\x01 \x03(\v2\x1f.packets.NewRoomResponseMessageR\x05rooms\"\x13\n" +
+	"\x05rooms\x18\x01 \x03(\v2\x1f.packets.NewRoomResponseMessageR\x05rooms\"0\n" +
+	"\x15ProfileRequestMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xda\x01\n" +
+	"\x1bUpdateProfileRequestMessage\x12&\n" +
+	"\fdisplay_name\x18\x01 \x01(\tH\x00R\vdisplayName\x88\x01\x01\x12\x15\n" +
+	"\x03bio\x18\x02 \x01(\tH\x01R\x03bio\x88\x01\x01\x12\x1b\n" +
+	"\x06status\x18\x03 \x01(\tH\x02R\x06status\x88\x01\x01\x12\x16\n" +
+	"\x06avatar\x18\x04 \x01(\fR\x06avatar\x12#\n" +
+	"\rremove_avatar\x18\x05 \x01(\bR\fremoveAvatarB\x0f\n" +
+	"\r_display_nameB\x06\n" +
+	"\x04_bioB\t\n" +
+	"\a_status\"\x1d\n" +
+	"\x1bConversationsRequestMessage\"\xe5\x01\n" +
+	"\x13ConversationMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1d\n" +
+	"\n" +
+	"member_ids\x18\x02 \x03(\tR\tmemberIds\x12)\n" +
+	"\x10member_usernames\x18\x03 \x03(\tR\x0fmemberUsernames\x129\n" +
+	"\flast_message\x18\x04 \x01(\v2\x16.packets.DirectMessageR\vlastMessage\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"b\n" +
+	"\x1cConversationsResponseMessage\x12B\n" +
+	"\rconversations\x18\x01 \x03(\v2\x1c.packets.ConversationMessageR\rconversations\"\x7f\n" +
+	"!ConversationHistoryRequestMessage\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x04R\x0econversationId\x12\x1b\n" +
+	"\tbefore_id\x18\x02 \x01(\x04R\bbeforeId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"\x81\x01\n" +
+	"\"ConversationHistoryResponseMessage\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\x04R\x0econversationId\x122\n" +
+	"\bmessages\x18\x02 \x03(\v2\x16.packets.DirectMessageR\bmessages\"\x18\n" +
+	"\x16MentionsRequestMessage\"N\n" +
+	"\x17MentionsResponseMessage\x123\n" +
+	"\bmentions\x18\x01 \x03(\v2\x17.packets.MentionMessageR\bmentions\"P\n" +
+	"\x1eUploadAttachmentRequestMessage\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"k\n" +
+	"\x18UpdateRoomRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"G\n" +
+	"\x18RenameRoomRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"3\n" +
+	"\x18DeleteRoomRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\"s\n" +
+	"\x1fSetRoomVisibilityRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x127\n" +
+	"\n" +
+	"visibility\x18\x02 \x01(\x0e2\x17.packets.RoomVisibilityR\n" +
+	"visibility\"Q\n" +
+	"\x1aInviteToRoomRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\"\x1b\n" +
+	"\x19RoomInvitesRequestMessage\"R\n" +
+	"\x1aRoomInvitesResponseMessage\x124\n" +
+	"\ainvites\x18\x01 \x03(\v2\x1a.packets.RoomInviteMessageR\ainvites\"P\n" +
+	"\x1dRespondToInviteRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12\x16\n" +
+	"\x06accept\x18\x02 \x01(\bR\x06accept\"\x80\x01\n" +
+	"\x14InviteCodeUseMessage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x123\n" +
+	"\aused_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06usedAt\"\x86\x03\n" +
+	"\x11InviteCodeMessage\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\x04R\x06roomId\x12%\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x11.packets.RoomRoleR\x04role\x12\x19\n" +
+	"\bmax_uses\x18\x04 \x01(\rR\amaxUses\x12\x12\n" +
+	"\x04uses\x18\x05 \x01(\rR\x04uses\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x18\n" +
+	"\arevoked\x18\a \x01(\bR\arevoked\x12\x1d\n" +
+	"\n" +
+	"creator_id\x18\b \x01(\tR\tcreatorId\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12?\n" +
+	"\vredemptions\x18\n" +
+	" \x03(\v2\x1d.packets.InviteCodeUseMessageR\vredemptions\"\xa9\x01\n" +
+	"\x1eCreateInviteCodeRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\x12%\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x11.packets.RoomRoleR\x04role\x12\x19\n" +
+	"\bmax_uses\x18\x03 \x01(\rR\amaxUses\x12,\n" +
+	"\x12expires_in_seconds\x18\x04 \x01(\rR\x10expiresInSeconds\"4\n" +
+	"\x19InviteCodesRequestMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\x04R\x06roomId\"N\n" +
+	"\x1aInviteCodesResponseMessage\x120\n" +
+	"\x05codes\x18\x01 \x03(\v2\x1a.packets.InviteCodeMessageR\x05codes\"4\n" +
+	"\x1eRevokeInviteCodeRequestMessage\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"4\n" +
+	"\x1eRedeemInviteCodeRequestMessage\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"w\n" +
+	"\x19InviteCodeRedeemedMessage\x123\n" +
+	"\x04room\x18\x01 \x01(\v2\x1f.packets.NewRoomResponseMessageR\x04room\x12%\n" +
+	"\x04role\x18\x02 \x01(\x0e2\x11.packets.RoomRoleR\x04role\"\x1a\n" +
+	"\x18ExportDataRequestMessage\"9\n" +
+	"\x1bDeleteAccountRequestMessage\x12\x1a\n" +
+	"\bpassword\x18\x01 \x01(\tR\bpassword\"\x13\n" +
 	"\x11OkResponseMessage\"-\n" +
 	"\x13DenyResponseMessage\x12\x16\n" +
-	"\x06reason\x18\x01 \x01(\tR\x06reason\"\x91\x03\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"\xb4\x0f\n" +
 	"\x06Packet\x12\x1b\n" +
 	"\tsender_id\x18\x01 \x01(\x04R\bsenderId\x12\x17\n" +
-	"\aroom_id\x18\x02 \x01(\x04R\x06roomId\x12*\n" +
+	"\aroom_id\x18\x02 \x01(\x04R\x06roomId\x12\x10\n" +
+	"\x03seq\x18\x1b \x01(\x04R\x03seq\x12*\n" +
 	"\x04chat\x18\x03 \x01(\v2\x14.packets.ChatMessageH\x00R\x04chat\x12$\n" +
 	"\x02id\x18\x04 \x01(\v2\x12.packets.IdMessageH\x00R\x02id\x126\n" +
 	"\bregister\x18\x05 \x01(\v2\x18.packets.RegisterMessageH\x00R\bregister\x12<\n" +
@@ -1230,8 +5981,37 @@ const file_packets_proto_rawDesc = "" +
 	"unregister\x12=\n" +
 	"\vok_response\x18\a \x01(\v2\x1a.packets.OkResponseMessageH\x00R\n" +
 	"okResponse\x12C\n" +
-	"\rdeny_response\x18\b \x01(\v2\x1c.packets.DenyResponseMessageH\x00R\fdenyResponseB\x05\n" +
-	"\x03msg\"\xf2\x04\n" +
+	"\rdeny_response\x18\b \x01(\v2\x1c.packets.DenyResponseMessageH\x00R\fdenyResponse\x127\n" +
+	"\tjoin_room\x18\t \x01(\v2\x18.packets.JoinRoomMessageH\x00R\bjoinRoom\x12:\n" +
+	"\n" +
+	"leave_room\x18\n" +
+	" \x01(\v2\x19.packets.LeaveRoomMessageH\x00R\tleaveRoom\x12?\n" +
+	"\x0edirect_message\x18\v \x01(\v2\x16.packets.DirectMessageH\x00R\rdirectMessage\x127\n" +
+	"\tchat_sent\x18\f \x01(\v2\x18.packets.ChatSentMessageH\x00R\bchatSent\x127\n" +
+	"\tedit_chat\x18\r \x01(\v2\x18.packets.EditChatMessageH\x00R\beditChat\x12=\n" +
+	"\vdelete_chat\x18\x0e \x01(\v2\x1a.packets.DeleteChatMessageH\x00R\n" +
+	"deleteChat\x12@\n" +
+	"\fadd_reaction\x18\x0f \x01(\v2\x1b.packets.AddReactionMessageH\x00R\vaddReaction\x12I\n" +
+	"\x0fremove_reaction\x18\x10 \x01(\v2\x1e.packets.RemoveReactionMessageH\x00R\x0eremoveReaction\x129\n" +
+	"\treactions\x18\x11 \x01(\v2\x19.packets.ReactionsMessageH\x00R\treactions\x12F\n" +
+	"\x0ethread_request\x18\x12 \x01(\v2\x1d.packets.ThreadRequestMessageH\x00R\rthreadRequest\x120\n" +
+	"\x06thread\x18\x13 \x01(\v2\x16.packets.ThreadMessageH\x00R\x06thread\x12F\n" +
+	"\x0ethread_updated\x18\x14 \x01(\v2\x1d.packets.ThreadUpdatedMessageH\x00R\rthreadUpdated\x120\n" +
+	"\x06typing\x18\x15 \x01(\v2\x16.packets.TypingMessageH\x00R\x06typing\x127\n" +
+	"\tmark_read\x18\x16 \x01(\v2\x18.packets.MarkReadMessageH\x00R\bmarkRead\x12C\n" +
+	"\rread_position\x18\x17 \x01(\v2\x1c.packets.ReadPositionMessageH\x00R\freadPosition\x126\n" +
+	"\bpresence\x18\x18 \x01(\v2\x18.packets.PresenceMessageH\x00R\bpresence\x12@\n" +
+	"\fset_presence\x18\x19 \x01(\v2\x1b.packets.SetPresenceMessageH\x00R\vsetPresence\x123\n" +
+	"\amention\x18\x1a \x01(\v2\x17.packets.MentionMessageH\x00R\amention\x12F\n" +
+	"\x0emissed_packets\x18\x1c \x01(\v2\x1d.packets.MissedPacketsMessageH\x00R\rmissedPackets\x127\n" +
+	"\tslow_mode\x18\x1d \x01(\v2\x18.packets.SlowModeMessageH\x00R\bslowMode\x12I\n" +
+	"\x0fmessage_updated\x18\x1e \x01(\v2\x1e.packets.MessageUpdatedMessageH\x00R\x0emessageUpdated\x120\n" +
+	"\x06system\x18\x1f \x01(\v2\x16.packets.SystemMessageH\x00R\x06system\x12=\n" +
+	"\vroom_invite\x18  \x01(\v2\x1a.packets.RoomInviteMessageH\x00R\n" +
+	"roomInvite\x12@\n" +
+	"\froom_updated\x18! \x01(\v2\x1b.packets.RoomUpdatedMessageH\x00R\vroomUpdated\x12@\n" +
+	"\froom_deleted\x18\" \x01(\v2\x1b.packets.RoomDeletedMessageH\x00R\vroomDeletedB\x05\n" +
+	"\x03msg\"\x84\x17\n" +
 	"\aMessage\x12'\n" +
 	"\x03jwt\x18\x01 \x01(\v2\x13.packets.JwtMessageH\x00R\x03jwt\x124\n" +
 	"\x05login\x18\x02 \x01(\v2\x1c.packets.LoginRequestMessageH\x00R\x05login\x12=\n" +
@@ -1244,8 +6024,57 @@ const file_packets_proto_rawDesc = "" +
 	"\vok_response\x18\t \x01(\v2\x1a.packets.OkResponseMessageH\x00R\n" +
 	"okResponse\x12C\n" +
 	"\rdeny_response\x18\n" +
-	" \x01(\v2\x1c.packets.DenyResponseMessageH\x00R\fdenyResponseB\x06\n" +
-	"\x04typeB\rZ\vpkg/packetsb\x06proto3"
+	" \x01(\v2\x1c.packets.DenyResponseMessageH\x00R\fdenyResponse\x12I\n" +
+	"\x0fprofile_request\x18\v \x01(\v2\x1e.packets.ProfileRequestMessageH\x00R\x0eprofileRequest\x123\n" +
+	"\aprofile\x18\f \x01(\v2\x17.packets.ProfileMessageH\x00R\aprofile\x12M\n" +
+	"\x0eupdate_profile\x18\r \x01(\v2$.packets.UpdateProfileRequestMessageH\x00R\rupdateProfile\x12D\n" +
+	"\vexport_data\x18\x0e \x01(\v2!.packets.ExportDataRequestMessageH\x00R\n" +
+	"exportData\x12M\n" +
+	"\x0edelete_account\x18\x0f \x01(\v2$.packets.DeleteAccountRequestMessageH\x00R\rdeleteAccount\x12[\n" +
+	"\x15conversations_request\x18\x10 \x01(\v2$.packets.ConversationsRequestMessageH\x00R\x14conversationsRequest\x12^\n" +
+	"\x16conversations_response\x18\x11 \x01(\v2%.packets.ConversationsResponseMessageH\x00R\x15conversationsResponse\x12n\n" +
+	"\x1cconversation_history_request\x18\x12 \x01(\v2*.packets.ConversationHistoryRequestMessageH\x00R\x1aconversationHistoryRequest\x12q\n" +
+	"\x1dconversation_history_response\x18\x13 \x01(\v2+.packets.ConversationHistoryResponseMessageH\x00R\x1bconversationHistoryResponse\x12L\n" +
+	"\x10mentions_request\x18\x14 \x01(\v2\x1f.packets.MentionsRequestMessageH\x00R\x0fmentionsRequest\x12O\n" +
+	"\x11mentions_response\x18\x15 \x01(\v2 .packets.MentionsResponseMessageH\x00R\x10mentionsResponse\x12V\n" +
+	"\x11upload_attachment\x18\x16 \x01(\v2'.packets.UploadAttachmentRequestMessageH\x00R\x10uploadAttachment\x12<\n" +
+	"\n" +
+	"attachment\x18\x17 \x01(\v2\x1a.packets.AttachmentMessageH\x00R\n" +
+	"attachment\x12D\n" +
+	"\vupdate_room\x18\x18 \x01(\v2!.packets.UpdateRoomRequestMessageH\x00R\n" +
+	"updateRoom\x12D\n" +
+	"\vrename_room\x18\x19 \x01(\v2!.packets.RenameRoomRequestMessageH\x00R\n" +
+	"renameRoom\x12D\n" +
+	"\vdelete_room\x18\x1a \x01(\v2!.packets.DeleteRoomRequestMessageH\x00R\n" +
+	"deleteRoom\x12Z\n" +
+	"\x13set_room_visibility\x18\x1b \x01(\v2(.packets.SetRoomVisibilityRequestMessageH\x00R\x11setRoomVisibility\x12K\n" +
+	"\x0einvite_to_room\x18\x1c \x01(\v2#.packets.InviteToRoomRequestMessageH\x00R\finviteToRoom\x12V\n" +
+	"\x14room_invites_request\x18\x1d \x01(\v2\".packets.RoomInvitesRequestMessageH\x00R\x12roomInvitesRequest\x12Y\n" +
+	"\x15room_invites_response\x18\x1e \x01(\v2#.packets.RoomInvitesResponseMessageH\x00R\x13roomInvitesResponse\x12T\n" +
+	"\x11respond_to_invite\x18\x1f \x01(\v2&.packets.RespondToInviteRequestMessageH\x00R\x0frespondToInvite\x12W\n" +
+	"\x12create_invite_code\x18  \x01(\v2'.packets.CreateInviteCodeRequestMessageH\x00R\x10createInviteCode\x12=\n" +
+	"\vinvite_code\x18! \x01(\v2\x1a.packets.InviteCodeMessageH\x00R\n" +
+	"inviteCode\x12V\n" +
+	"\x14invite_codes_request\x18\" \x01(\v2\".packets.InviteCodesRequestMessageH\x00R\x12inviteCodesRequest\x12Y\n" +
+	"\x15invite_codes_response\x18# \x01(\v2#.packets.InviteCodesResponseMessageH\x00R\x13inviteCodesResponse\x12W\n" +
+	"\x12revoke_invite_code\x18$ \x01(\v2'.packets.RevokeInviteCodeRequestMessageH\x00R\x10revokeInviteCode\x12W\n" +
+	"\x12redeem_invite_code\x18% \x01(\v2'.packets.RedeemInviteCodeRequestMessageH\x00R\x10redeemInviteCode\x12V\n" +
+	"\x14invite_code_redeemed\x18& \x01(\v2\".packets.InviteCodeRedeemedMessageH\x00R\x12inviteCodeRedeemedB\x06\n" +
+	"\x04type*~\n" +
+	"\x0ePresenceStatus\x12\x14\n" +
+	"\x10PRESENCE_OFFLINE\x10\x00\x12\x13\n" +
+	"\x0fPRESENCE_ONLINE\x10\x01\x12\x11\n" +
+	"\rPRESENCE_IDLE\x10\x02\x12\x11\n" +
+	"\rPRESENCE_AWAY\x10\x03\x12\x1b\n" +
+	"\x17PRESENCE_DO_NOT_DISTURB\x10\x04*F\n" +
+	"\x0eRoomVisibility\x12\x0f\n" +
+	"\vROOM_PUBLIC\x10\x00\x12\x10\n" +
+	"\fROOM_PRIVATE\x10\x01\x12\x11\n" +
+	"\rROOM_UNLISTED\x10\x02*N\n" +
+	"\bRoomRole\x12\x14\n" +
+	"\x10ROOM_ROLE_MEMBER\x10\x00\x12\x17\n" +
+	"\x13ROOM_ROLE_MODERATOR\x10\x01\x12\x13\n" +
+	"\x0fROOM_ROLE_OWNER\x10\x02B\rZ\vpkg/packetsb\x06proto3"
 
 var (
 	file_packets_proto_rawDescOnce sync.Once
@@ -1259,53 +6088,206 @@ func file_packets_proto_rawDescGZIP() []byte {
 	return file_packets_proto_rawDescData
 }
 
-var file_packets_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_packets_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_packets_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
 var file_packets_proto_goTypes = []any{
-	(*ChatMessage)(nil),            // 0: packets.ChatMessage
-	(*IdMessage)(nil),              // 1: packets.IdMessage
-	(*RegisterMessage)(nil),        // 2: packets.RegisterMessage
-	(*UnregisterMessage)(nil),      // 3: packets.UnregisterMessage
-	(*RoomRegisteredMessage)(nil),  // 4: packets.RoomRegisteredMessage
-	(*JwtMessage)(nil),             // 5: packets.JwtMessage
-	(*LoginRequestMessage)(nil),    // 6: packets.LoginRequestMessage
-	(*RegisterRequestMessage)(nil), // 7: packets.RegisterRequestMessage
-	(*RefreshRequestMessage)(nil),  // 8: packets.RefreshRequestMessage
-	(*LogoutRequestMessage)(nil),   // 9: packets.LogoutRequestMessage
-	(*NewRoomRequestMessage)(nil),  // 10: packets.NewRoomRequestMessage
-	(*NewRoomResponseMessage)(nil), // 11: packets.NewRoomResponseMessage
-	(*RoomsRequestMessage)(nil),    // 12: packets.RoomsRequestMessage
-	(*RoomsResponseMessage)(nil),   // 13: packets.RoomsResponseMessage
-	(*OkResponseMessage)(nil),      // 14: packets.OkResponseMessage
-	(*DenyResponseMessage)(nil),    // 15: packets.DenyResponseMessage
-	(*Packet)(nil),                 // 16: packets.Packet
-	(*Message)(nil),                // 17: packets.Message
-	(*timestamppb.Timestamp)(nil),  // 18: google.protobuf.Timestamp
+	(PresenceStatus)(0),                        // 0: packets.PresenceStatus
+	(RoomVisibility)(0),                        // 1: packets.RoomVisibility
+	(RoomRole)(0),                              // 2: packets.RoomRole
+	(*ChatMessage)(nil),                        // 3: packets.ChatMessage
+	(*AttachmentMessage)(nil),                  // 4: packets.AttachmentMessage
+	(*ThumbnailMessage)(nil),                   // 5: packets.ThumbnailMessage
+	(*LinkPreviewMessage)(nil),                 // 6: packets.LinkPreviewMessage
+	(*MessageUpdatedMessage)(nil),              // 7: packets.MessageUpdatedMessage
+	(*ChatSentMessage)(nil),                    // 8: packets.ChatSentMessage
+	(*EditChatMessage)(nil),                    // 9: packets.EditChatMessage
+	(*DeleteChatMessage)(nil),                  // 10: packets.DeleteChatMessage
+	(*ReactionSummary)(nil),                    // 11: packets.ReactionSummary
+	(*AddReactionMessage)(nil),                 // 12: packets.AddReactionMessage
+	(*RemoveReactionMessage)(nil),              // 13: packets.RemoveReactionMessage
+	(*ReactionsMessage)(nil),                   // 14: packets.ReactionsMessage
+	(*ThreadRequestMessage)(nil),               // 15: packets.ThreadRequestMessage
+	(*ThreadMessage)(nil),                      // 16: packets.ThreadMessage
+	(*ThreadUpdatedMessage)(nil),               // 17: packets.ThreadUpdatedMessage
+	(*TypingMessage)(nil),                      // 18: packets.TypingMessage
+	(*MarkReadMessage)(nil),                    // 19: packets.MarkReadMessage
+	(*ReadPositionMessage)(nil),                // 20: packets.ReadPositionMessage
+	(*PresenceMessage)(nil),                    // 21: packets.PresenceMessage
+	(*SetPresenceMessage)(nil),                 // 22: packets.SetPresenceMessage
+	(*SlowModeMessage)(nil),                    // 23: packets.SlowModeMessage
+	(*SystemMessage)(nil),                      // 24: packets.SystemMessage
+	(*RoomUpdatedMessage)(nil),                 // 25: packets.RoomUpdatedMessage
+	(*RoomInviteMessage)(nil),                  // 26: packets.RoomInviteMessage
+	(*RoomDeletedMessage)(nil),                 // 27: packets.RoomDeletedMessage
+	(*MissedPacketsMessage)(nil),               // 28: packets.MissedPacketsMessage
+	(*MentionMessage)(nil),                     // 29: packets.MentionMessage
+	(*IdMessage)(nil),                          // 30: packets.IdMessage
+	(*RegisterMessage)(nil),                    // 31: packets.RegisterMessage
+	(*UnregisterMessage)(nil),                  // 32: packets.UnregisterMessage
+	(*RoomRegisteredMessage)(nil),              // 33: packets.RoomRegisteredMessage
+	(*JoinRoomMessage)(nil),                    // 34: packets.JoinRoomMessage
+	(*LeaveRoomMessage)(nil),                   // 35: packets.LeaveRoomMessage
+	(*DirectMessage)(nil),                      // 36: packets.DirectMessage
+	(*ProfileMessage)(nil),                     // 37: packets.ProfileMessage
+	(*JwtMessage)(nil),                         // 38: packets.JwtMessage
+	(*LoginRequestMessage)(nil),                // 39: packets.LoginRequestMessage
+	(*RegisterRequestMessage)(nil),             // 40: packets.RegisterRequestMessage
+	(*RefreshRequestMessage)(nil),              // 41: packets.RefreshRequestMessage
+	(*LogoutRequestMessage)(nil),               // 42: packets.LogoutRequestMessage
+	(*NewRoomRequestMessage)(nil),              // 43: packets.NewRoomRequestMessage
+	(*NewRoomResponseMessage)(nil),             // 44: packets.NewRoomResponseMessage
+	(*RoomsRequestMessage)(nil),                // 45: packets.RoomsRequestMessage
+	(*RoomsResponseMessage)(nil),               // 46: packets.RoomsResponseMessage
+	(*ProfileRequestMessage)(nil),              // 47: packets.ProfileRequestMessage
+	(*UpdateProfileRequestMessage)(nil),        // 48: packets.UpdateProfileRequestMessage
+	(*ConversationsRequestMessage)(nil),        // 49: packets.ConversationsRequestMessage
+	(*ConversationMessage)(nil),                // 50: packets.ConversationMessage
+	(*ConversationsResponseMessage)(nil),       // 51: packets.ConversationsResponseMessage
+	(*ConversationHistoryRequestMessage)(nil),  // 52: packets.ConversationHistoryRequestMessage
+	(*ConversationHistoryResponseMessage)(nil), // 53: packets.ConversationHistoryResponseMessage
+	(*MentionsRequestMessage)(nil),             // 54: packets.MentionsRequestMessage
+	(*MentionsResponseMessage)(nil),            // 55: packets.MentionsResponseMessage
+	(*UploadAttachmentRequestMessage)(nil),     // 56: packets.UploadAttachmentRequestMessage
+	(*UpdateRoomRequestMessage)(nil),           // 57: packets.UpdateRoomRequestMessage
+	(*RenameRoomRequestMessage)(nil),           // 58: packets.RenameRoomRequestMessage
+	(*DeleteRoomRequestMessage)(nil),           // 59: packets.DeleteRoomRequestMessage
+	(*SetRoomVisibilityRequestMessage)(nil),    // 60: packets.SetRoomVisibilityRequestMessage
+	(*InviteToRoomRequestMessage)(nil),         // 61: packets.InviteToRoomRequestMessage
+	(*RoomInvitesRequestMessage)(nil),          // 62: packets.RoomInvitesRequestMessage
+	(*RoomInvitesResponseMessage)(nil),         // 63: packets.RoomInvitesResponseMessage
+	(*RespondToInviteRequestMessage)(nil),      // 64: packets.RespondToInviteRequestMessage
+	(*InviteCodeUseMessage)(nil),               // 65: packets.InviteCodeUseMessage
+	(*InviteCodeMessage)(nil),                  // 66: packets.InviteCodeMessage
+	(*CreateInviteCodeRequestMessage)(nil),     // 67: packets.CreateInviteCodeRequestMessage
+	(*InviteCodesRequestMessage)(nil),          // 68: packets.InviteCodesRequestMessage
+	(*InviteCodesResponseMessage)(nil),         // 69: packets.InviteCodesResponseMessage
+	(*RevokeInviteCodeRequestMessage)(nil),     // 70: packets.RevokeInviteCodeRequestMessage
+	(*RedeemInviteCodeRequestMessage)(nil),     // 71: packets.RedeemInviteCodeRequestMessage
+	(*InviteCodeRedeemedMessage)(nil),          // 72: packets.InviteCodeRedeemedMessage
+	(*ExportDataRequestMessage)(nil),           // 73: packets.ExportDataRequestMessage
+	(*DeleteAccountRequestMessage)(nil),        // 74: packets.DeleteAccountRequestMessage
+	(*OkResponseMessage)(nil),                  // 75: packets.OkResponseMessage
+	(*DenyResponseMessage)(nil),                // 76: packets.DenyResponseMessage
+	(*Packet)(nil),                             // 77: packets.Packet
+	(*Message)(nil),                            // 78: packets.Message
+	(*timestamppb.Timestamp)(nil),              // 79: google.protobuf.Timestamp
 }
 var file_packets_proto_depIdxs = []int32{
-	18, // 0: packets.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
-	4,  // 1: packets.IdMessage.room:type_name -> packets.RoomRegisteredMessage
-	11, // 2: packets.RoomsResponseMessage.rooms:type_name -> packets.NewRoomResponseMessage
-	0,  // 3: packets.Packet.chat:type_name -> packets.ChatMessage
-	1,  // 4: packets.Packet.id:type_name -> packets.IdMessage
-	2,  // 5: packets.Packet.register:type_name -> packets.RegisterMessage
-	3,  // 6: packets.Packet.unregister:type_name -> packets.UnregisterMessage
-	14, // 7: packets.Packet.ok_response:type_name -> packets.OkResponseMessage
-	15, // 8: packets.Packet.deny_response:type_name -> packets.DenyResponseMessage
-	5,  // 9: packets.Message.jwt:type_name -> packets.JwtMessage
-	6,  // 10: packets.Message.login:type_name -> packets.LoginRequestMessage
-	7,  // 11: packets.Message.register:type_name -> packets.RegisterRequestMessage
-	8,  // 12: packets.Message.refresh:type_name -> packets.RefreshRequestMessage
-	9,  // 13: packets.Message.logout:type_name -> packets.LogoutRequestMessage
-	10, // 14: packets.Message.new_room:type_name -> packets.NewRoomRequestMessage
-	12, // 15: packets.Message.rooms_request:type_name -> packets.RoomsRequestMessage
-	13, // 16: packets.Message.rooms_response:type_name -> packets.RoomsResponseMessage
-	14, // 17: packets.Message.ok_response:type_name -> packets.OkResponseMessage
-	15, // 18: packets.Message.deny_response:type_name -> packets.DenyResponseMessage
-	19, // [19:19] is the sub-list for method output_type
-	19, // [19:19] is the sub-list for method input_type
-	19, // [19:19] is the sub-list for extension type_name
-	19, // [19:19] is the sub-list for extension extendee
-	0,  // [0:19] is the sub-list for field type_name
+	79,  // 0: packets.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
+	79,  // 1: packets.ChatMessage.edited_at:type_name -> google.protobuf.Timestamp
+	11,  // 2: packets.ChatMessage.reactions:type_name -> packets.ReactionSummary
+	4,   // 3: packets.ChatMessage.attachments:type_name -> packets.AttachmentMessage
+	6,   // 4: packets.ChatMessage.previews:type_name -> packets.LinkPreviewMessage
+	5,   // 5: packets.AttachmentMessage.thumbnails:type_name -> packets.ThumbnailMessage
+	6,   // 6: packets.MessageUpdatedMessage.previews:type_name -> packets.LinkPreviewMessage
+	79,  // 7: packets.ChatSentMessage.timestamp:type_name -> google.protobuf.Timestamp
+	79,  // 8: packets.EditChatMessage.edited_at:type_name -> google.protobuf.Timestamp
+	11,  // 9: packets.ReactionsMessage.reactions:type_name -> packets.ReactionSummary
+	3,   // 10: packets.ThreadMessage.parent:type_name -> packets.ChatMessage
+	3,   // 11: packets.ThreadMessage.replies:type_name -> packets.ChatMessage
+	0,   // 12: packets.PresenceMessage.status:type_name -> packets.PresenceStatus
+	79,  // 13: packets.PresenceMessage.last_seen:type_name -> google.protobuf.Timestamp
+	0,   // 14: packets.SetPresenceMessage.status:type_name -> packets.PresenceStatus
+	1,   // 15: packets.RoomUpdatedMessage.visibility:type_name -> packets.RoomVisibility
+	79,  // 16: packets.MentionMessage.timestamp:type_name -> google.protobuf.Timestamp
+	33,  // 17: packets.IdMessage.room:type_name -> packets.RoomRegisteredMessage
+	37,  // 18: packets.RegisterMessage.profile:type_name -> packets.ProfileMessage
+	1,   // 19: packets.RoomRegisteredMessage.visibility:type_name -> packets.RoomVisibility
+	2,   // 20: packets.RoomRegisteredMessage.role:type_name -> packets.RoomRole
+	79,  // 21: packets.DirectMessage.timestamp:type_name -> google.protobuf.Timestamp
+	1,   // 22: packets.NewRoomRequestMessage.visibility:type_name -> packets.RoomVisibility
+	1,   // 23: packets.NewRoomResponseMessage.visibility:type_name -> packets.RoomVisibility
+	44,  // 24: packets.RoomsResponseMessage.rooms:type_name -> packets.NewRoomResponseMessage
+	36,  // 25: packets.ConversationMessage.last_message:type_name -> packets.DirectMessage
+	79,  // 26: packets.ConversationMessage.updated_at:type_name -> google.protobuf.Timestamp
+	50,  // 27: packets.ConversationsResponseMessage.conversations:type_name -> packets.ConversationMessage
+	36,  // 28: packets.ConversationHistoryResponseMessage.messages:type_name -> packets.DirectMessage
+	29,  // 29: packets.MentionsResponseMessage.mentions:type_name -> packets.MentionMessage
+	1,   // 30: packets.SetRoomVisibilityRequestMessage.visibility:type_name -> packets.RoomVisibility
+	26,  // 31: packets.RoomInvitesResponseMessage.invites:type_name -> packets.RoomInviteMessage
+	79,  // 32: packets.InviteCodeUseMessage.used_at:type_name -> google.protobuf.Timestamp
+	2,   // 33: packets.InviteCodeMessage.role:type_name -> packets.RoomRole
+	79,  // 34: packets.InviteCodeMessage.expires_at:type_name -> google.protobuf.Timestamp
+	79,  // 35: packets.InviteCodeMessage.created_at:type_name -> google.protobuf.Timestamp
+	65,  // 36: packets.InviteCodeMessage.redemptions:type_name -> packets.InviteCodeUseMessage
+	2,   // 37: packets.CreateInviteCodeRequestMessage.role:type_name -> packets.RoomRole
+	66,  // 38: packets.InviteCodesResponseMessage.codes:type_name -> packets.InviteCodeMessage
+	44,  // 39: packets.InviteCodeRedeemedMessage.room:type_name -> packets.NewRoomResponseMessage
+	2,   // 40: packets.InviteCodeRedeemedMessage.role:type_name -> packets.RoomRole
+	3,   // 41: packets.Packet.chat:type_name -> packets.ChatMessage
+	30,  // 42: packets.Packet.id:type_name -> packets.IdMessage
+	31,  // 43: packets.Packet.register:type_name -> packets.RegisterMessage
+	32,  // 44: packets.Packet.unregister:type_name -> packets.UnregisterMessage
+	75,  // 45: packets.Packet.ok_response:type_name -> packets.OkResponseMessage
+	76,  // 46: packets.Packet.deny_response:type_name -> packets.DenyResponseMessage
+	34,  // 47: packets.Packet.join_room:type_name -> packets.JoinRoomMessage
+	35,  // 48: packets.Packet.leave_room:type_name -> packets.LeaveRoomMessage
+	36,  // 49: packets.Packet.direct_message:type_name -> packets.DirectMessage
+	8,   // 50: packets.Packet.chat_sent:type_name -> packets.ChatSentMessage
+	9,   // 51: packets.Packet.edit_chat:type_name -> packets.EditChatMessage
+	10,  // 52: packets.Packet.delete_chat:type_name -> packets.DeleteChatMessage
+	12,  // 53: packets.Packet.add_reaction:type_name -> packets.AddReactionMessage
+	13,  // 54: packets.Packet.remove_reaction:type_name -> packets.RemoveReactionMessage
+	14,  // 55: packets.Packet.reactions:type_name -> packets.ReactionsMessage
+	15,  // 56: packets.Packet.thread_request:type_name -> packets.ThreadRequestMessage
+	16,  // 57: packets.Packet.thread:type_name -> packets.ThreadMessage
+	17,  // 58: packets.Packet.thread_updated:type_name -> packets.ThreadUpdatedMessage
+	18,  // 59: packets.Packet.typing:type_name -> packets.TypingMessage
+	19,  // 60: packets.Packet.mark_read:type_name -> packets.MarkReadMessage
+	20,  // 61: packets.Packet.read_position:type_name -> packets.ReadPositionMessage
+	21,  // 62: packets.Packet.presence:type_name -> packets.PresenceMessage
+	22,  // 63: packets.Packet.set_presence:type_name -> packets.SetPresenceMessage
+	29,  // 64: packets.Packet.mention:type_name -> packets.MentionMessage
+	28,  // 65: packets.Packet.missed_packets:type_name -> packets.MissedPacketsMessage
+	23,  // 66: packets.Packet.slow_mode:type_name -> packets.SlowModeMessage
+	7,   // 67: packets.Packet.message_updated:type_name -> packets.MessageUpdatedMessage
+	24,  // 68: packets.Packet.system:type_name -> packets.SystemMessage
+	26,  // 69: packets.Packet.room_invite:type_name -> packets.RoomInviteMessage
+	25,  // 70: packets.Packet.room_updated:type_name -> packets.RoomUpdatedMessage
+	27,  // 71: packets.Packet.room_deleted:type_name -> packets.RoomDeletedMessage
+	38,  // 72: packets.Message.jwt:type_name -> packets.JwtMessage
+	39,  // 73: packets.Message.login:type_name -> packets.LoginRequestMessage
+	40,  // 74: packets.Message.register:type_name -> packets.RegisterRequestMessage
+	41,  // 75: packets.Message.refresh:type_name -> packets.RefreshRequestMessage
+	42,  // 76: packets.Message.logout:type_name -> packets.LogoutRequestMessage
+	43,  // 77: packets.Message.new_room:type_name -> packets.NewRoomRequestMessage
+	45,  // 78: packets.Message.rooms_request:type_name -> packets.RoomsRequestMessage
+	46,  // 79: packets.Message.rooms_response:type_name -> packets.RoomsResponseMessage
+	75,  // 80: packets.Message.ok_response:type_name -> packets.OkResponseMessage
+	76,  // 81: packets.Message.deny_response:type_name -> packets.DenyResponseMessage
+	47,  // 82: packets.Message.profile_request:type_name -> packets.ProfileRequestMessage
+	37,  // 83: packets.Message.profile:type_name -> packets.ProfileMessage
+	48,  // 84: packets.Message.update_profile:type_name -> packets.UpdateProfileRequestMessage
+	73,  // 85: packets.Message.export_data:type_name -> packets.ExportDataRequestMessage
+	74,  // 86: packets.Message.delete_account:type_name -> packets.DeleteAccountRequestMessage
+	49,  // 87: packets.Message.conversations_request:type_name -> packets.ConversationsRequestMessage
+	51,  // 88: packets.Message.conversations_response:type_name -> packets.ConversationsResponseMessage
+	52,  // 89: packets.Message.conversation_history_request:type_name -> packets.ConversationHistoryRequestMessage
+	53,  // 90: packets.Message.conversation_history_response:type_name -> packets.ConversationHistoryResponseMessage
+	54,  // 91: packets.Message.mentions_request:type_name -> packets.MentionsRequestMessage
+	55,  // 92: packets.Message.mentions_response:type_name -> packets.MentionsResponseMessage
+	56,  // 93: packets.Message.upload_attachment:type_name -> packets.UploadAttachmentRequestMessage
+	4,   // 94: packets.Message.attachment:type_name -> packets.AttachmentMessage
+	57,  // 95: packets.Message.update_room:type_name -> packets.UpdateRoomRequestMessage
+	58,  // 96: packets.Message.rename_room:type_name -> packets.RenameRoomRequestMessage
+	59,  // 97: packets.Message.delete_room:type_name -> packets.DeleteRoomRequestMessage
+	60,  // 98: packets.Message.set_room_visibility:type_name -> packets.SetRoomVisibilityRequestMessage
+	61,  // 99: packets.Message.invite_to_room:type_name -> packets.InviteToRoomRequestMessage
+	62,  // 100: packets.Message.room_invites_request:type_name -> packets.RoomInvitesRequestMessage
+	63,  // 101: packets.Message.room_invites_response:type_name -> packets.RoomInvitesResponseMessage
+	64,  // 102: packets.Message.respond_to_invite:type_name -> packets.RespondToInviteRequestMessage
+	67,  // 103: packets.Message.create_invite_code:type_name -> packets.CreateInviteCodeRequestMessage
+	66,  // 104: packets.Message.invite_code:type_name -> packets.InviteCodeMessage
+	68,  // 105: packets.Message.invite_codes_request:type_name -> packets.InviteCodesRequestMessage
+	69,  // 106: packets.Message.invite_codes_response:type_name -> packets.InviteCodesResponseMessage
+	70,  // 107: packets.Message.revoke_invite_code:type_name -> packets.RevokeInviteCodeRequestMessage
+	71,  // 108: packets.Message.redeem_invite_code:type_name -> packets.RedeemInviteCodeRequestMessage
+	72,  // 109: packets.Message.invite_code_redeemed:type_name -> packets.InviteCodeRedeemedMessage
+	110, // [110:110] is the sub-list for method output_type
+	110, // [110:110] is the sub-list for method input_type
+	110, // [110:110] is the sub-list for extension type_name
+	110, // [110:110] is the sub-list for extension extendee
+	0,   // [0:110] is the sub-list for field type_name
 }
 
 func init() { file_packets_proto_init() }
@@ -1313,15 +6295,41 @@ func file_packets_proto_init() {
 	if File_packets_proto != nil {
 		return
 	}
-	file_packets_proto_msgTypes[16].OneofWrappers = []any{
+	file_packets_proto_msgTypes[45].OneofWrappers = []any{}
+	file_packets_proto_msgTypes[74].OneofWrappers = []any{
 		(*Packet_Chat)(nil),
 		(*Packet_Id)(nil),
 		(*Packet_Register)(nil),
 		(*Packet_Unregister)(nil),
 		(*Packet_OkResponse)(nil),
 		(*Packet_DenyResponse)(nil),
+		(*Packet_JoinRoom)(nil),
+		(*Packet_LeaveRoom)(nil),
+		(*Packet_DirectMessage)(nil),
+		(*Packet_ChatSent)(nil),
+		(*Packet_EditChat)(nil),
+		(*Packet_DeleteChat)(nil),
+		(*Packet_AddReaction)(nil),
+		(*Packet_RemoveReaction)(nil),
+		(*Packet_Reactions)(nil),
+		(*Packet_ThreadRequest)(nil),
+		(*Packet_Thread)(nil),
+		(*Packet_ThreadUpdated)(nil),
+		(*Packet_Typing)(nil),
+		(*Packet_MarkRead)(nil),
+		(*Packet_ReadPosition)(nil),
+		(*Packet_Presence)(nil),
+		(*Packet_SetPresence)(nil),
+		(*Packet_Mention)(nil),
+		(*Packet_MissedPackets)(nil),
+		(*Packet_SlowMode)(nil),
+		(*Packet_MessageUpdated)(nil),
+		(*Packet_System)(nil),
+		(*Packet_RoomInvite)(nil),
+		(*Packet_RoomUpdated)(nil),
+		(*Packet_RoomDeleted)(nil),
 	}
-	file_packets_proto_msgTypes[17].OneofWrappers = []any{
+	file_packets_proto_msgTypes[75].OneofWrappers = []any{
 		(*Message_Jwt)(nil),
 		(*Message_Login)(nil),
 		(*Message_Register)(nil),
@@ -1332,19 +6340,48 @@ func file_packets_proto_init() {
 		(*Message_RoomsResponse)(nil),
 		(*Message_OkResponse)(nil),
 		(*Message_DenyResponse)(nil),
+		(*Message_ProfileRequest)(nil),
+		(*Message_Profile)(nil),
+		(*Message_UpdateProfile)(nil),
+		(*Message_ExportData)(nil),
+		(*Message_DeleteAccount)(nil),
+		(*Message_ConversationsRequest)(nil),
+		(*Message_ConversationsResponse)(nil),
+		(*Message_ConversationHistoryRequest)(nil),
+		(*Message_ConversationHistoryResponse)(nil),
+		(*Message_MentionsRequest)(nil),
+		(*Message_MentionsResponse)(nil),
+		(*Message_UploadAttachment)(nil),
+		(*Message_Attachment)(nil),
+		(*Message_UpdateRoom)(nil),
+		(*Message_RenameRoom)(nil),
+		(*Message_DeleteRoom)(nil),
+		(*Message_SetRoomVisibility)(nil),
+		(*Message_InviteToRoom)(nil),
+		(*Message_RoomInvitesRequest)(nil),
+		(*Message_RoomInvitesResponse)(nil),
+		(*Message_RespondToInvite)(nil),
+		(*Message_CreateInviteCode)(nil),
+		(*Message_InviteCode)(nil),
+		(*Message_InviteCodesRequest)(nil),
+		(*Message_InviteCodesResponse)(nil),
+		(*Message_RevokeInviteCode)(nil),
+		(*Message_RedeemInviteCode)(nil),
+		(*Message_InviteCodeRedeemed)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_packets_proto_rawDesc), len(file_packets_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   18,
+			NumEnums:      3,
+			NumMessages:   76,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_packets_proto_goTypes,
 		DependencyIndexes: file_packets_proto_depIdxs,
+		EnumInfos:         file_packets_proto_enumTypes,
 		MessageInfos:      file_packets_proto_msgTypes,
 	}.Build()
 	File_packets_proto = out.File
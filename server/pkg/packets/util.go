@@ -1,6 +1,8 @@
 package packets
 
 import (
+	"time"
+
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -17,25 +19,47 @@ func NewChat(senderUsername string, msg string) Pkt {
 	}
 }
 
-func NewId(id uint64, username string, roomId uint64, roomOwnerId string, roomName string) Pkt {
+// The room is nil when the id is sent for the connection rather than for a joined room
+func NewId(id uint64, username string, room *RoomRegisteredMessage) Pkt {
 	return &Packet_Id{
 		Id: &IdMessage{
 			Id:       id,
 			Username: username,
-			Room: &RoomRegisteredMessage{
-				Id:      roomId,
-				OwnerId: roomOwnerId,
-				Name:    roomName,
-			},
+			Room:     room,
 		},
 	}
 }
 
-func NewRegister(id uint64, username string) Pkt {
+func NewRoomRegistered(roomId uint64, roomOwnerId string, roomName string) *RoomRegisteredMessage {
+	return &RoomRegisteredMessage{
+		Id:      roomId,
+		OwnerId: roomOwnerId,
+		Name:    roomName,
+	}
+}
+
+func NewJoinRoom(roomId uint64) Pkt {
+	return &Packet_JoinRoom{
+		JoinRoom: &JoinRoomMessage{
+			RoomId: roomId,
+		},
+	}
+}
+
+func NewLeaveRoom(roomId uint64) Pkt {
+	return &Packet_LeaveRoom{
+		LeaveRoom: &LeaveRoomMessage{
+			RoomId: roomId,
+		},
+	}
+}
+
+func NewRegister(id uint64, username string, profile *ProfileMessage) Pkt {
 	return &Packet_Register{
 		Register: &RegisterMessage{
 			Id:       id,
 			Username: username,
+			Profile:  profile,
 		},
 	}
 }
@@ -92,3 +116,290 @@ func NewRoomsResponseMsg(rooms []*NewRoomResponseMessage) Msg {
 		},
 	}
 }
+
+func NewRoomInvitesResponseMsg(invites []*RoomInviteMessage) Msg {
+	return &Message_RoomInvitesResponse{
+		RoomInvitesResponse: &RoomInvitesResponseMessage{
+			Invites: invites,
+		},
+	}
+}
+
+func NewProfile(userId string, displayName string, bio string, status string, hasAvatar bool, version int64) *ProfileMessage {
+	return &ProfileMessage{
+		UserId:      userId,
+		DisplayName: displayName,
+		Bio:         bio,
+		Status:      status,
+		HasAvatar:   hasAvatar,
+		Version:     version,
+	}
+}
+
+func NewProfileMsg(profile *ProfileMessage) Msg {
+	return &Message_Profile{
+		Profile: profile,
+	}
+}
+
+func NewDirectMessage(id uint64, conversationId uint64, senderId string, senderUsername string, msg string, timestamp time.Time) *DirectMessage {
+	return &DirectMessage{
+		Id:             id,
+		ConversationId: conversationId,
+		SenderId:       senderId,
+		SenderUsername: senderUsername,
+		Msg:            msg,
+		Timestamp:      timestamppb.New(timestamp),
+	}
+}
+
+func NewDirectMessagePkt(message *DirectMessage) Pkt {
+	return &Packet_DirectMessage{
+		DirectMessage: message,
+	}
+}
+
+func NewConversationsResponseMsg(conversations []*ConversationMessage) Msg {
+	return &Message_ConversationsResponse{
+		ConversationsResponse: &ConversationsResponseMessage{
+			Conversations: conversations,
+		},
+	}
+}
+
+func NewConversationHistoryResponseMsg(conversationId uint64, messages []*DirectMessage) Msg {
+	return &Message_ConversationHistoryResponse{
+		ConversationHistoryResponse: &ConversationHistoryResponseMessage{
+			ConversationId: conversationId,
+			Messages:       messages,
+		},
+	}
+}
+
+// A chat message as stored by the server, with the id clients use to edit or
+// delete it
+func NewSavedChat(id uint64, senderUserId string, senderUsername string, msg string, timestamp time.Time) Pkt {
+	return &Packet_Chat{
+		Chat: &ChatMessage{
+			Id:             id,
+			Timestamp:      timestamppb.New(timestamp),
+			SenderUserId:   senderUserId,
+			SenderUsername: senderUsername,
+			Msg:            msg,
+		},
+	}
+}
+
+func NewChatSent(messageId uint64, clientId string, timestamp time.Time) Pkt {
+	return &Packet_ChatSent{
+		ChatSent: &ChatSentMessage{
+			MessageId: messageId,
+			Timestamp: timestamppb.New(timestamp),
+			ClientId:  clientId,
+		},
+	}
+}
+
+func NewEditChat(messageId uint64, msg string, editedAt time.Time) Pkt {
+	return &Packet_EditChat{
+		EditChat: &EditChatMessage{
+			MessageId: messageId,
+			Msg:       msg,
+			EditedAt:  timestamppb.New(editedAt),
+		},
+	}
+}
+
+func NewLinkPreview(url string, title string, description string, imageUrl string, siteName string) *LinkPreviewMessage {
+	return &LinkPreviewMessage{
+		Url:         url,
+		Title:       title,
+		Description: description,
+		ImageUrl:    imageUrl,
+		SiteName:    siteName,
+	}
+}
+
+func NewMessageUpdated(messageId uint64, previews []*LinkPreviewMessage) Pkt {
+	return &Packet_MessageUpdated{
+		MessageUpdated: &MessageUpdatedMessage{
+			MessageId: messageId,
+			Previews:  previews,
+		},
+	}
+}
+
+func NewDeleteChat(messageId uint64) Pkt {
+	return &Packet_DeleteChat{
+		DeleteChat: &DeleteChatMessage{
+			MessageId: messageId,
+		},
+	}
+}
+
+func NewReactions(messageId uint64, reactions []*ReactionSummary) Pkt {
+	return &Packet_Reactions{
+		Reactions: &ReactionsMessage{
+			MessageId: messageId,
+			Reactions: reactions,
+		},
+	}
+}
+
+func NewThread(parent *ChatMessage, replies []*ChatMessage) Pkt {
+	return &Packet_Thread{
+		Thread: &ThreadMessage{
+			Parent:  parent,
+			Replies: replies,
+		},
+	}
+}
+
+func NewThreadUpdated(messageId uint64, replyCount uint32) Pkt {
+	return &Packet_ThreadUpdated{
+		ThreadUpdated: &ThreadUpdatedMessage{
+			MessageId:  messageId,
+			ReplyCount: replyCount,
+		},
+	}
+}
+
+func NewTyping(userId string, username string, typing bool) Pkt {
+	return &Packet_Typing{
+		Typing: &TypingMessage{
+			UserId:   userId,
+			Username: username,
+			Typing:   typing,
+		},
+	}
+}
+
+func NewReadPosition(userId string, messageId uint64) Pkt {
+	return &Packet_ReadPosition{
+		ReadPosition: &ReadPositionMessage{
+			UserId:    userId,
+			MessageId: messageId,
+		},
+	}
+}
+
+func NewPresence(userId string, status PresenceStatus, lastSeen *time.Time) Pkt {
+	presence := &PresenceMessage{
+		UserId: userId,
+		Status: status,
+	}
+	if lastSeen != nil {
+		presence.LastSeen = timestamppb.New(*lastSeen)
+	}
+	return &Packet_Presence{
+		Presence: presence,
+	}
+}
+
+func NewSlowMode(seconds uint32) Pkt {
+	return &Packet_SlowMode{
+		SlowMode: &SlowModeMessage{
+			Seconds: seconds,
+		},
+	}
+}
+
+func NewSystem(text string) Pkt {
+	return &Packet_System{
+		System: &SystemMessage{
+			Text: text,
+		},
+	}
+}
+
+func NewRoomInvite(roomId uint64, roomName string, inviterId string, inviterUsername string) Pkt {
+	return &Packet_RoomInvite{
+		RoomInvite: &RoomInviteMessage{
+			RoomId:          roomId,
+			RoomName:        roomName,
+			InviterId:       inviterId,
+			InviterUsername: inviterUsername,
+		},
+	}
+}
+
+func NewMissedPackets(count uint32, roomIds []uint64) Pkt {
+	return &Packet_MissedPackets{
+		MissedPackets: &MissedPacketsMessage{
+			Count:   count,
+			RoomIds: roomIds,
+		},
+	}
+}
+
+func NewMention(id uint64, messageId uint64, roomId uint64, roomName string, senderId string, senderUsername string, msg string, timestamp time.Time) *MentionMessage {
+	return &MentionMessage{
+		Id:             id,
+		MessageId:      messageId,
+		RoomId:         roomId,
+		RoomName:       roomName,
+		SenderId:       senderId,
+		SenderUsername: senderUsername,
+		Msg:            msg,
+		Timestamp:      timestamppb.New(timestamp),
+	}
+}
+
+func NewMentionPkt(mention *MentionMessage) Pkt {
+	return &Packet_Mention{
+		Mention: mention,
+	}
+}
+
+func NewMentionsResponseMsg(mentions []*MentionMessage) Msg {
+	return &Message_MentionsResponse{
+		MentionsResponse: &MentionsResponseMessage{
+			Mentions: mentions,
+		},
+	}
+}
+
+func NewAttachment(id string, filename string, mime string, size uint64) *AttachmentMessage {
+	return &AttachmentMessage{
+		Id:       id,
+		Filename: filename,
+		Mime:     mime,
+		Size:     size,
+	}
+}
+
+func NewThumbnail(size uint32, width uint32, height uint32, mime string) *ThumbnailMessage {
+	return &ThumbnailMessage{
+		Size:   size,
+		Width:  width,
+		Height: height,
+		Mime:   mime,
+	}
+}
+
+func NewAttachmentMsg(attachment *AttachmentMessage) Msg {
+	return &Message_Attachment{
+		Attachment: attachment,
+	}
+}
+
+func NewRoomDeleted(roomId uint64) Pkt {
+	return &Packet_RoomDeleted{
+		RoomDeleted: &RoomDeletedMessage{
+			RoomId: roomId,
+		},
+	}
+}
+
+func NewRoomUpdated(roomId uint64, name string, topic string, description string, visibility RoomVisibility, updaterId string) Pkt {
+	return &Packet_RoomUpdated{
+		RoomUpdated: &RoomUpdatedMessage{
+			RoomId:      roomId,
+			Name:        name,
+			Topic:       topic,
+			Description: description,
+			Visibility:  visibility,
+			UpdaterId:   updaterId,
+		},
+	}
+}
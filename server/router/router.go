@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"server/internal/attachments"
+	"server/internal/profile"
 	"server/internal/user"
 	"server/internal/ws"
 
@@ -15,7 +17,7 @@ var (
 	port = flag.Int("port", 8080, "Port to listen on")
 )
 
-func StartRouter(userHandler *user.Handler, wsHandler *ws.Handler) {
+func StartRouter(userHandler *user.Handler, wsHandler *ws.Handler, profileHandler *profile.Handler, attachmentsHandler *attachments.Handler) {
 	flag.Parse()
 
 	mux := http.NewServeMux()
@@ -24,7 +26,8 @@ func StartRouter(userHandler *user.Handler, wsHandler *ws.Handler) {
 		AllowOriginFunc: checkOrigin,
 		AllowedMethods:  []string{"GET", "POST"},
 		AllowedHeaders:  []string{"Content-Type", "Authorization"},
-	}).Handler(mux)
+		ExposedHeaders:  []string{"Content-Disposition"},
+	}).Handler(userHandler.RejectDisabledUsers(mux))
 
 	mux.HandleFunc("/login", userHandler.Login)
 	mux.HandleFunc("/register", userHandler.Register)
@@ -32,6 +35,27 @@ func StartRouter(userHandler *user.Handler, wsHandler *ws.Handler) {
 	mux.HandleFunc("/logout", userHandler.Logout)
 	mux.HandleFunc("/new-room", userHandler.CreateRoom)
 	mux.HandleFunc("/rooms", userHandler.GetRooms)
+	mux.HandleFunc("/update-room", wsHandler.UpdateRoom)
+	mux.HandleFunc("/rename-room", wsHandler.RenameRoom)
+	mux.HandleFunc("/delete-room", wsHandler.DeleteRoom)
+	mux.HandleFunc("/room-visibility", wsHandler.SetRoomVisibility)
+	mux.HandleFunc("/invite-to-room", wsHandler.InviteToRoom)
+	mux.HandleFunc("/room-invites", wsHandler.GetRoomInvites)
+	mux.HandleFunc("/respond-to-invite", wsHandler.RespondToInvite)
+	mux.HandleFunc("/create-invite-code", wsHandler.CreateInviteCode)
+	mux.HandleFunc("/invite-codes", wsHandler.GetInviteCodes)
+	mux.HandleFunc("/revoke-invite-code", wsHandler.RevokeInviteCode)
+	mux.HandleFunc("/redeem-invite-code", wsHandler.RedeemInviteCode)
+	mux.HandleFunc("/conversations", userHandler.GetConversations)
+	mux.HandleFunc("/mentions", userHandler.GetMentions)
+	mux.HandleFunc("/conversation-history", userHandler.GetConversationHistory)
+	mux.HandleFunc("/export", userHandler.ExportData)
+	mux.HandleFunc("/delete-account", userHandler.DeleteAccount)
+	mux.HandleFunc("/profile", profileHandler.GetProfile)
+	mux.HandleFunc("/update-profile", profileHandler.UpdateProfile)
+	mux.HandleFunc("/avatar", profileHandler.GetAvatar)
+	mux.HandleFunc("/upload-attachment", attachmentsHandler.UploadAttachment)
+	mux.HandleFunc("/attachment", attachmentsHandler.GetAttachment)
 
 	mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
 		wsHandler.Serve(ws.NewWebSocketClient, w, r)